@@ -0,0 +1,39 @@
+package main
+
+import (
+	"flag"
+	"github.com/AdilBaidual/baseProject/pkg/oidcmock"
+	"log"
+	"net/http"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:9096", "listen address")
+	issuer := flag.String("issuer", "http://localhost:9096", "issuer URL as seen by clients")
+	clientID := flag.String("client-id", "baseProject", "expected client_id")
+	flag.Parse()
+
+	mock, err := oidcmock.New(oidcmock.Config{
+		Issuer:   *issuer,
+		ClientID: *clientID,
+		DefaultUser: oidcmock.User{
+			Subject:       "mock-user-1",
+			Email:         "author@example.com",
+			EmailVerified: true,
+			Name:          "Mock Author",
+		},
+		Users: map[string]oidcmock.User{
+			"unverified": {
+				Subject: "mock-user-2",
+				Email:   "unverified@example.com",
+				Name:    "Unverified Author",
+			},
+		},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	log.Printf("mock OIDC issuer %s listening on %s", *issuer, *addr)
+	log.Fatal(http.ListenAndServe(*addr, mock))
+}
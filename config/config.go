@@ -2,9 +2,25 @@ package config
 
 import (
 	"fmt"
+	"github.com/AdilBaidual/baseProject/internal/auth"
+	"github.com/AdilBaidual/baseProject/internal/interceptor"
+	"github.com/AdilBaidual/baseProject/internal/service/attachment_service"
+	"github.com/AdilBaidual/baseProject/internal/service/bookmark_service"
+	"github.com/AdilBaidual/baseProject/internal/service/feed_service"
+	"github.com/AdilBaidual/baseProject/internal/service/follow_service"
+	"github.com/AdilBaidual/baseProject/internal/service/notification_service"
+	"github.com/AdilBaidual/baseProject/internal/service/policy_service"
+	"github.com/AdilBaidual/baseProject/internal/service/post_service"
+	"github.com/AdilBaidual/baseProject/internal/service/trash_service"
+	"github.com/AdilBaidual/baseProject/internal/service/user_service"
+	"github.com/AdilBaidual/baseProject/pkg/concurrency"
 	"github.com/AdilBaidual/baseProject/pkg/grpcserver"
 	"github.com/AdilBaidual/baseProject/pkg/httpserver"
 	"github.com/AdilBaidual/baseProject/pkg/jaeger"
+	"github.com/AdilBaidual/baseProject/pkg/mailer"
+	"github.com/AdilBaidual/baseProject/pkg/ratelimit"
+	"github.com/AdilBaidual/baseProject/pkg/sso"
+	"github.com/AdilBaidual/baseProject/pkg/storage/blob"
 	"github.com/AdilBaidual/baseProject/pkg/storage/postgres"
 	"github.com/ilyakaznacheev/cleanenv"
 )
@@ -12,10 +28,27 @@ import (
 const configPath string = "./config/config.yaml"
 
 type Config struct {
-	Postgres   postgres.Config   `yaml:"postgres"`
-	Jaeger     jaeger.Config     `yaml:"jaeger"`
-	GRPCServer grpcserver.Config `yaml:"grpc_server"`
-	HTTPServer httpserver.Config `yaml:"http_server"`
+	Postgres    postgres.Config            `yaml:"postgres"`
+	Jaeger      jaeger.Config              `yaml:"jaeger"`
+	GRPCServer  grpcserver.Config          `yaml:"grpc_server"`
+	HTTPServer  httpserver.Config          `yaml:"http_server"`
+	RateLimit   ratelimit.Config           `yaml:"rate_limit"`
+	Concurrency concurrency.Config         `yaml:"concurrency"`
+	Deadlines   interceptor.DeadlineConfig `yaml:"deadlines"`
+	Auth        auth.Config                `yaml:"auth"`
+	Mailer      mailer.Config              `yaml:"mailer"`
+	Users       user_service.Config        `yaml:"users"`
+	SSO         sso.Config                 `yaml:"sso"`
+	Posts       post_service.Config        `yaml:"posts"`
+	Trash       trash_service.Config       `yaml:"trash"`
+	Policy      policy_service.Config      `yaml:"content_policy"`
+	Blob        blob.Config                `yaml:"blob_storage"`
+	Attachments attachment_service.Config  `yaml:"attachments"`
+	Feeds       feed_service.Config        `yaml:"feeds"`
+	Follows     follow_service.Config      `yaml:"follows"`
+	Bookmarks   bookmark_service.Config    `yaml:"bookmarks"`
+
+	Notifications notification_service.Config `yaml:"notifications"`
 }
 
 func NewConfig() (*Config, error) {
@@ -0,0 +1,70 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS roles
+(
+    id   SERIAL PRIMARY KEY,
+    name VARCHAR(64) UNIQUE NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS permissions
+(
+    id          SERIAL PRIMARY KEY,
+    name        VARCHAR(128) UNIQUE NOT NULL,
+    description TEXT                NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions
+(
+    role_id       INTEGER NOT NULL,
+    permission_id INTEGER NOT NULL,
+    PRIMARY KEY (role_id, permission_id),
+    FOREIGN KEY (role_id) REFERENCES roles (id) ON DELETE CASCADE,
+    FOREIGN KEY (permission_id) REFERENCES permissions (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS user_roles
+(
+    user_uuid  UUID    NOT NULL,
+    role_id    INTEGER NOT NULL,
+    created_at TIMESTAMP WITHOUT TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (user_uuid, role_id),
+    FOREIGN KEY (user_uuid) REFERENCES users (uuid) ON DELETE CASCADE,
+    FOREIGN KEY (role_id) REFERENCES roles (id) ON DELETE CASCADE
+);
+
+INSERT INTO roles (name)
+VALUES ('admin'),
+       ('moderator'),
+       ('user')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO permissions (name, description)
+VALUES ('posts.create', 'Создание постов'),
+       ('posts.update_any', 'Редактирование чужих постов'),
+       ('posts.delete_any', 'Удаление чужих постов'),
+       ('comments.create', 'Создание комментариев'),
+       ('comments.hide', 'Скрытие комментариев'),
+       ('users.ban', 'Блокировка пользователей'),
+       ('roles.manage', 'Управление ролями пользователей')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+         JOIN permissions p ON
+    (r.name = 'admin')
+        OR (r.name = 'moderator' AND p.name IN ('posts.create', 'posts.update_any', 'posts.delete_any',
+                                                'comments.create', 'comments.hide'))
+        OR (r.name = 'user' AND p.name IN ('posts.create', 'comments.create'))
+ON CONFLICT DO NOTHING;
+
+INSERT INTO user_roles (user_uuid, role_id)
+SELECT u.uuid, r.id
+FROM users u
+         JOIN roles r ON r.name = 'user'
+ON CONFLICT DO NOTHING;
+
+-- +goose Down
+DROP TABLE IF EXISTS user_roles;
+DROP TABLE IF EXISTS role_permissions;
+DROP TABLE IF EXISTS permissions;
+DROP TABLE IF EXISTS roles;
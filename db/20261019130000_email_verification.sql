@@ -0,0 +1,22 @@
+-- +goose Up
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMP WITH TIME ZONE;
+
+CREATE TABLE IF NOT EXISTS user_tokens
+(
+    id         SERIAL PRIMARY KEY,
+    user_uuid  UUID                     NOT NULL,
+    purpose    VARCHAR(32)              NOT NULL,
+    token_hash VARCHAR(64)              NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at    TIMESTAMP WITH TIME ZONE,
+    FOREIGN KEY (user_uuid) REFERENCES users (uuid) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS user_tokens_user_uuid_purpose_idx ON user_tokens (user_uuid, purpose);
+
+-- +goose Down
+DROP TABLE IF EXISTS user_tokens;
+ALTER TABLE users
+    DROP COLUMN IF EXISTS email_verified_at;
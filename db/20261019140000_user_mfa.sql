@@ -0,0 +1,25 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS user_mfa
+(
+    user_uuid        UUID PRIMARY KEY,
+    secret_encrypted BYTEA                    NOT NULL,
+    enabled_at       TIMESTAMP WITH TIME ZONE,
+    last_used_step   BIGINT                   NOT NULL DEFAULT 0,
+    created_at       TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (user_uuid) REFERENCES users (uuid) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS user_recovery_codes
+(
+    id         SERIAL PRIMARY KEY,
+    user_uuid  UUID                     NOT NULL,
+    code_hash  VARCHAR(64)              NOT NULL,
+    used_at    TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (user_uuid, code_hash),
+    FOREIGN KEY (user_uuid) REFERENCES users (uuid) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS user_recovery_codes;
+DROP TABLE IF EXISTS user_mfa;
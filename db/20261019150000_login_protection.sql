@@ -0,0 +1,44 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS login_failures
+(
+    scope           VARCHAR(16)              NOT NULL,
+    subject         VARCHAR(320)             NOT NULL,
+    failures        INTEGER                  NOT NULL DEFAULT 0,
+    lockouts        INTEGER                  NOT NULL DEFAULT 0,
+    locked_until    TIMESTAMP WITH TIME ZONE,
+    last_failure_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (scope, subject)
+);
+
+CREATE INDEX IF NOT EXISTS login_failures_last_failure_at_idx ON login_failures (last_failure_at);
+
+CREATE TABLE IF NOT EXISTS audit_events
+(
+    id         BIGSERIAL PRIMARY KEY,
+    event      VARCHAR(64)              NOT NULL,
+    user_uuid  UUID,
+    actor_uuid UUID,
+    ip         VARCHAR(64)              NOT NULL DEFAULT '',
+    details    JSONB                    NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS audit_events_user_uuid_idx ON audit_events (user_uuid, created_at);
+CREATE INDEX IF NOT EXISTS audit_events_event_idx ON audit_events (event, created_at);
+CREATE INDEX IF NOT EXISTS audit_events_created_at_idx ON audit_events (created_at);
+
+INSERT INTO permissions (name, description)
+VALUES ('users.unlock', 'Разблокировка учётных записей после неудачных входов')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+         JOIN permissions p ON p.name = 'users.unlock'
+WHERE r.name = 'admin'
+ON CONFLICT DO NOTHING;
+
+-- +goose Down
+DELETE FROM permissions WHERE name = 'users.unlock';
+DROP TABLE IF EXISTS audit_events;
+DROP TABLE IF EXISTS login_failures;
@@ -0,0 +1,27 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS user_identities
+(
+    provider   VARCHAR(64)              NOT NULL,
+    subject    VARCHAR(255)             NOT NULL,
+    user_uuid  UUID                     NOT NULL,
+    email      VARCHAR(255)             NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (provider, subject),
+    FOREIGN KEY (user_uuid) REFERENCES users (uuid) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS user_identities_user_uuid_idx ON user_identities (user_uuid);
+
+CREATE TABLE IF NOT EXISTS sso_states
+(
+    state_hash    VARCHAR(64) PRIMARY KEY,
+    provider      VARCHAR(64)              NOT NULL,
+    nonce         VARCHAR(128)             NOT NULL,
+    code_verifier VARCHAR(128)             NOT NULL,
+    expires_at    TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at    TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS sso_states;
+DROP TABLE IF EXISTS user_identities;
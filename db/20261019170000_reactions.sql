@@ -0,0 +1,33 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS reactions
+(
+    user_uuid   UUID                     NOT NULL,
+    target_type VARCHAR(16)              NOT NULL,
+    target_id   INTEGER                  NOT NULL,
+    kind        VARCHAR(32)              NOT NULL,
+    created_at  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_uuid, target_type, target_id, kind),
+    FOREIGN KEY (user_uuid) REFERENCES users (uuid) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS reactions_target_idx ON reactions (target_type, target_id, kind, created_at);
+
+-- Счётчики обновляются в том же запросе, что и reactions, поэтому чтение
+-- не требует COUNT(*) по reactions.
+CREATE TABLE IF NOT EXISTS reaction_counts
+(
+    target_type VARCHAR(16) NOT NULL,
+    target_id   INTEGER     NOT NULL,
+    kind        VARCHAR(32) NOT NULL,
+    count       BIGINT      NOT NULL DEFAULT 0 CHECK (count >= 0),
+    PRIMARY KEY (target_type, target_id, kind)
+);
+
+CREATE INDEX IF NOT EXISTS posts_author_uuid_idx ON posts (author_uuid);
+CREATE INDEX IF NOT EXISTS comments_post_id_parent_id_idx ON comments (post_id, parent_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS comments_post_id_parent_id_idx;
+DROP INDEX IF EXISTS posts_author_uuid_idx;
+DROP TABLE IF EXISTS reaction_counts;
+DROP TABLE IF EXISTS reactions;
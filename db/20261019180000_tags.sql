@@ -0,0 +1,28 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS tags
+(
+    id          SERIAL PRIMARY KEY,
+    slug        VARCHAR(64) UNIQUE       NOT NULL,
+    name        VARCHAR(64)              NOT NULL,
+    created_at  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Поиск по префиксу для автодополнения.
+CREATE INDEX IF NOT EXISTS tags_slug_prefix_idx ON tags (slug text_pattern_ops);
+
+CREATE TABLE IF NOT EXISTS post_tags
+(
+    post_id INTEGER NOT NULL,
+    tag_id  INTEGER NOT NULL,
+    PRIMARY KEY (post_id, tag_id),
+    FOREIGN KEY (post_id) REFERENCES posts (id) ON DELETE CASCADE,
+    FOREIGN KEY (tag_id) REFERENCES tags (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS post_tags_tag_id_idx ON post_tags (tag_id, post_id DESC);
+CREATE INDEX IF NOT EXISTS posts_created_at_idx ON posts (created_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS posts_created_at_idx;
+DROP TABLE IF EXISTS post_tags;
+DROP TABLE IF EXISTS tags;
@@ -0,0 +1,34 @@
+-- +goose Up
+ALTER TABLE posts
+    ADD COLUMN IF NOT EXISTS status       VARCHAR(16) NOT NULL DEFAULT 'published'
+        CHECK (status IN ('draft', 'scheduled', 'published', 'archived')),
+    ADD COLUMN IF NOT EXISTS publish_at   TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS unpublish_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS published_at TIMESTAMP WITH TIME ZONE;
+
+-- Всё, что было создано до появления статусов, считается опубликованным в момент создания.
+UPDATE posts SET published_at = created_at WHERE published_at IS NULL;
+
+ALTER TABLE posts
+    ADD CONSTRAINT posts_scheduled_publish_at_check CHECK (status <> 'scheduled' OR publish_at IS NOT NULL),
+    ADD CONSTRAINT posts_published_at_check CHECK (status <> 'published' OR published_at IS NOT NULL);
+
+-- Лента и выборки по тегу идут по опубликованным постам в порядке публикации.
+CREATE INDEX IF NOT EXISTS posts_published_idx ON posts (published_at DESC, id DESC) WHERE status = 'published';
+CREATE INDEX IF NOT EXISTS posts_author_status_idx ON posts (author_uuid, status, id DESC);
+-- Очереди планировщика.
+CREATE INDEX IF NOT EXISTS posts_publish_at_idx ON posts (publish_at) WHERE status = 'scheduled';
+CREATE INDEX IF NOT EXISTS posts_unpublish_at_idx ON posts (unpublish_at) WHERE status = 'published' AND unpublish_at IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS posts_unpublish_at_idx;
+DROP INDEX IF EXISTS posts_publish_at_idx;
+DROP INDEX IF EXISTS posts_author_status_idx;
+DROP INDEX IF EXISTS posts_published_idx;
+ALTER TABLE posts
+    DROP CONSTRAINT IF EXISTS posts_published_at_check,
+    DROP CONSTRAINT IF EXISTS posts_scheduled_publish_at_check,
+    DROP COLUMN IF EXISTS published_at,
+    DROP COLUMN IF EXISTS unpublish_at,
+    DROP COLUMN IF EXISTS publish_at,
+    DROP COLUMN IF EXISTS status;
@@ -0,0 +1,29 @@
+-- +goose Up
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+
+CREATE TABLE IF NOT EXISTS post_revisions
+(
+    post_id       INTEGER                  NOT NULL,
+    version       INTEGER                  NOT NULL,
+    title         VARCHAR(255)             NOT NULL,
+    content       TEXT                     NOT NULL,
+    editor_uuid   UUID                     NOT NULL,
+    -- Версия, из которой восстановлена эта ревизия
+    restored_from INTEGER,
+    created_at    TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (post_id, version),
+    FOREIGN KEY (post_id) REFERENCES posts (id) ON DELETE CASCADE,
+    FOREIGN KEY (editor_uuid) REFERENCES users (uuid)
+);
+
+-- Ревизии неизменяемы: восстановление создаёт новую ревизию.
+CREATE OR REPLACE RULE post_revisions_immutable AS ON UPDATE TO post_revisions DO INSTEAD NOTHING;
+
+INSERT INTO post_revisions (post_id, version, title, content, editor_uuid, created_at)
+SELECT id, version, title, content, author_uuid, created_at
+FROM posts
+ON CONFLICT DO NOTHING;
+
+-- +goose Down
+DROP TABLE IF EXISTS post_revisions;
+ALTER TABLE posts DROP COLUMN IF EXISTS version;
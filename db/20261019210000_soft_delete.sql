@@ -0,0 +1,62 @@
+-- +goose Up
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+
+-- deleted_by без внешнего ключа: удаливший модератор может быть удалён раньше.
+ALTER TABLE posts
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS deleted_by UUID;
+
+ALTER TABLE comments
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS deleted_by UUID;
+
+-- Удалённый комментарий с ответами остаётся в ветке заглушкой без автора и текста.
+ALTER TABLE comments ALTER COLUMN author_uuid DROP NOT NULL;
+
+-- Ревизии чужих постов переживают удаление редактора.
+ALTER TABLE post_revisions ALTER COLUMN editor_uuid DROP NOT NULL;
+ALTER TABLE post_revisions DROP CONSTRAINT IF EXISTS post_revisions_editor_uuid_fkey;
+ALTER TABLE post_revisions
+    ADD CONSTRAINT post_revisions_editor_uuid_fkey FOREIGN KEY (editor_uuid) REFERENCES users (uuid) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS users_deleted_at_idx ON users (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS posts_deleted_at_idx ON posts (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS comments_deleted_at_idx ON comments (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS comments_author_uuid_idx ON comments (author_uuid);
+CREATE INDEX IF NOT EXISTS comments_parent_id_idx ON comments (parent_id) WHERE parent_id <> 0;
+
+INSERT INTO permissions (name, description)
+VALUES ('comments.delete_any', 'Удаление чужих комментариев'),
+       ('users.delete', 'Удаление и восстановление учётных записей')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+         JOIN permissions p ON
+    (r.name = 'admin' AND p.name IN ('comments.delete_any', 'users.delete'))
+        OR (r.name = 'moderator' AND p.name = 'comments.delete_any')
+ON CONFLICT DO NOTHING;
+
+-- +goose Down
+DELETE FROM permissions WHERE name IN ('comments.delete_any', 'users.delete');
+
+DROP INDEX IF EXISTS comments_parent_id_idx;
+DROP INDEX IF EXISTS comments_author_uuid_idx;
+DROP INDEX IF EXISTS comments_deleted_at_idx;
+DROP INDEX IF EXISTS posts_deleted_at_idx;
+DROP INDEX IF EXISTS users_deleted_at_idx;
+
+ALTER TABLE post_revisions DROP CONSTRAINT IF EXISTS post_revisions_editor_uuid_fkey;
+ALTER TABLE post_revisions
+    ADD CONSTRAINT post_revisions_editor_uuid_fkey FOREIGN KEY (editor_uuid) REFERENCES users (uuid);
+
+ALTER TABLE comments
+    DROP COLUMN IF EXISTS deleted_by,
+    DROP COLUMN IF EXISTS deleted_at;
+
+ALTER TABLE posts
+    DROP COLUMN IF EXISTS deleted_by,
+    DROP COLUMN IF EXISTS deleted_at;
+
+ALTER TABLE users DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,65 @@
+-- +goose Up
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS hidden_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS hidden_at TIMESTAMP WITH TIME ZONE;
+
+-- target_id без внешнего ключа, как у реакций: жалобы на очищенные посты
+-- и комментарии удаляет очистка корзины.
+CREATE TABLE IF NOT EXISTS reports
+(
+    id            BIGSERIAL PRIMARY KEY,
+    target_type   VARCHAR(16)              NOT NULL,
+    target_id     INTEGER                  NOT NULL,
+    reporter_uuid UUID                     NOT NULL,
+    reason        VARCHAR(32)              NOT NULL,
+    created_at    TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    resolved_at   TIMESTAMP WITH TIME ZONE,
+    FOREIGN KEY (reporter_uuid) REFERENCES users (uuid) ON DELETE CASCADE
+);
+
+-- Пока жалоба не рассмотрена, повторная жалоба того же читателя не добавляется.
+CREATE UNIQUE INDEX IF NOT EXISTS reports_open_reporter_idx
+    ON reports (target_type, target_id, reporter_uuid) WHERE resolved_at IS NULL;
+CREATE INDEX IF NOT EXISTS reports_reporter_uuid_idx ON reports (reporter_uuid);
+
+-- Журнал модерации неизменяем и переживает удаление модераторов, авторов и контента,
+-- поэтому внешних ключей у него нет.
+CREATE TABLE IF NOT EXISTS moderation_log
+(
+    id               BIGSERIAL PRIMARY KEY,
+    target_type      VARCHAR(16)              NOT NULL,
+    target_id        INTEGER                  NOT NULL,
+    action           VARCHAR(16)              NOT NULL,
+    moderator_uuid   UUID                     NOT NULL,
+    author_uuid      UUID,
+    note             TEXT                     NOT NULL DEFAULT '',
+    reports_resolved INTEGER                  NOT NULL DEFAULT 0,
+    created_at       TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS moderation_log_target_idx ON moderation_log (target_type, target_id, id);
+CREATE INDEX IF NOT EXISTS moderation_log_author_uuid_idx ON moderation_log (author_uuid, id);
+
+CREATE OR REPLACE RULE moderation_log_no_update AS ON UPDATE TO moderation_log DO INSTEAD NOTHING;
+CREATE OR REPLACE RULE moderation_log_no_delete AS ON DELETE TO moderation_log DO INSTEAD NOTHING;
+
+INSERT INTO permissions (name, description)
+VALUES ('reports.create', 'Жалобы на посты и комментарии'),
+       ('moderation.manage', 'Очередь модерации и действия модератора')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+         JOIN permissions p ON
+    (r.name IN ('admin', 'moderator') AND p.name IN ('reports.create', 'moderation.manage'))
+        OR (r.name = 'user' AND p.name = 'reports.create')
+ON CONFLICT DO NOTHING;
+
+-- +goose Down
+DELETE FROM permissions WHERE name IN ('reports.create', 'moderation.manage');
+
+DROP TABLE IF EXISTS moderation_log;
+DROP TABLE IF EXISTS reports;
+
+ALTER TABLE comments DROP COLUMN IF EXISTS hidden_at;
+ALTER TABLE posts DROP COLUMN IF EXISTS hidden_at;
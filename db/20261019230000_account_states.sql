@@ -0,0 +1,35 @@
+-- +goose Up
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS account_state       VARCHAR(16) NOT NULL DEFAULT 'active'
+        CHECK (account_state IN ('active', 'suspended', 'banned', 'shadow_banned')),
+    -- Ограничение снимается само по истечении state_until, NULL — бессрочно.
+    ADD COLUMN IF NOT EXISTS state_until         TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS state_reason        TEXT        NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS state_changed_by    UUID,
+    ADD COLUMN IF NOT EXISTS state_changed_at    TIMESTAMP WITH TIME ZONE,
+    -- Токены доступа, выданные раньше, недействительны.
+    ADD COLUMN IF NOT EXISTS sessions_revoked_at TIMESTAMP WITH TIME ZONE;
+
+-- Выдача постов и комментариев исключает авторов в теневом бане.
+CREATE INDEX IF NOT EXISTS users_shadow_banned_idx ON users (uuid) WHERE account_state = 'shadow_banned';
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+         JOIN permissions p ON r.name = 'moderator' AND p.name = 'users.ban'
+ON CONFLICT DO NOTHING;
+
+-- +goose Down
+DELETE FROM role_permissions
+WHERE role_id = (SELECT id FROM roles WHERE name = 'moderator')
+  AND permission_id = (SELECT id FROM permissions WHERE name = 'users.ban');
+
+DROP INDEX IF EXISTS users_shadow_banned_idx;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS sessions_revoked_at,
+    DROP COLUMN IF EXISTS state_changed_at,
+    DROP COLUMN IF EXISTS state_changed_by,
+    DROP COLUMN IF EXISTS state_reason,
+    DROP COLUMN IF EXISTS state_until,
+    DROP COLUMN IF EXISTS account_state;
@@ -0,0 +1,51 @@
+-- +goose Up
+-- Возраст аккаунта нужен правилам контент-политики. Существующим пользователям
+-- время регистрации восстанавливается по самой ранней публикации.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW();
+
+UPDATE users u
+SET created_at = LEAST(u.created_at,
+                       (SELECT MIN(p.created_at) FROM posts p WHERE p.author_uuid = u.uuid),
+                       (SELECT MIN(c.created_at) FROM comments c WHERE c.author_uuid = u.uuid));
+
+-- Правила проверяются по возрастанию priority, срабатывает первое подходящее.
+CREATE TABLE IF NOT EXISTS content_rules
+(
+    id          BIGSERIAL PRIMARY KEY,
+    name        VARCHAR(100)             NOT NULL UNIQUE,
+    description TEXT                     NOT NULL DEFAULT '',
+    expression  TEXT                     NOT NULL,
+    action      VARCHAR(16)              NOT NULL CHECK (action IN ('allow', 'hold', 'reject')),
+    priority    INTEGER                  NOT NULL DEFAULT 0,
+    enabled     BOOLEAN                  NOT NULL DEFAULT TRUE,
+    created_by  UUID,
+    created_at  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (created_by) REFERENCES users (uuid) ON DELETE SET NULL
+);
+
+-- Контент, задержанный правилом, попадает в очередь модерации жалобой без автора.
+ALTER TABLE reports ALTER COLUMN reporter_uuid DROP NOT NULL;
+ALTER TABLE reports
+    ADD COLUMN IF NOT EXISTS rule_id BIGINT REFERENCES content_rules (id) ON DELETE SET NULL;
+
+INSERT INTO permissions (name, description)
+VALUES ('content_rules.manage', 'Правила контент-политики')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+         JOIN permissions p ON r.name = 'admin' AND p.name = 'content_rules.manage'
+ON CONFLICT DO NOTHING;
+
+-- +goose Down
+DELETE FROM permissions WHERE name = 'content_rules.manage';
+
+DELETE FROM reports WHERE reporter_uuid IS NULL;
+ALTER TABLE reports DROP COLUMN IF EXISTS rule_id;
+ALTER TABLE reports ALTER COLUMN reporter_uuid SET NOT NULL;
+
+DROP TABLE IF EXISTS content_rules;
+
+ALTER TABLE users DROP COLUMN IF EXISTS created_at;
@@ -0,0 +1,16 @@
+-- +goose Up
+-- Отрендеренный из Markdown и очищенный HTML хранится рядом с исходным текстом.
+-- content_html_version — версия рендерера: строки со старой версией (в том числе
+-- существующие, у которых 0) перестраиваются при чтении.
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS content_html TEXT NOT NULL DEFAULT '';
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS content_html_version INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS content_html TEXT NOT NULL DEFAULT '';
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS content_html_version INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE comments DROP COLUMN IF EXISTS content_html_version;
+ALTER TABLE comments DROP COLUMN IF EXISTS content_html;
+
+ALTER TABLE posts DROP COLUMN IF EXISTS content_html_version;
+ALTER TABLE posts DROP COLUMN IF EXISTS content_html;
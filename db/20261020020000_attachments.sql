@@ -0,0 +1,34 @@
+-- +goose Up
+-- Содержимое вложений лежит в blob-хранилище под ключом из sha256, поэтому
+-- одинаковые файлы хранятся один раз. last_used_at обновляется при каждой
+-- загрузке: сборщик мусора удаляет только давно не использованные блобы,
+-- на которые не ссылается ни одно вложение.
+CREATE TABLE IF NOT EXISTS blobs
+(
+    sha256       CHAR(64) PRIMARY KEY,
+    size         BIGINT                   NOT NULL,
+    mime_type    VARCHAR(100)             NOT NULL,
+    created_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_used_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Вложения удаляются вместе с постом при очистке корзины.
+CREATE TABLE IF NOT EXISTS attachments
+(
+    id          BIGSERIAL PRIMARY KEY,
+    post_id     BIGINT                   NOT NULL,
+    sha256      CHAR(64)                 NOT NULL,
+    filename    VARCHAR(255)             NOT NULL DEFAULT '',
+    uploaded_by UUID,
+    created_at  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (post_id, sha256),
+    FOREIGN KEY (post_id) REFERENCES posts (id) ON DELETE CASCADE,
+    FOREIGN KEY (sha256) REFERENCES blobs (sha256),
+    FOREIGN KEY (uploaded_by) REFERENCES users (uuid) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS attachments_sha256_idx ON attachments (sha256);
+
+-- +goose Down
+DROP TABLE IF EXISTS attachments;
+DROP TABLE IF EXISTS blobs;
@@ -0,0 +1,51 @@
+-- +goose Up
+-- Изображения обрабатываются фоновым обработчиком: image_state проходит
+-- pending -> processing -> ready или failed, у остальных файлов none.
+-- processing_started_at позволяет забрать обработку у упавшего экземпляра.
+ALTER TABLE attachments
+    ADD COLUMN IF NOT EXISTS image_state           VARCHAR(20)  NOT NULL DEFAULT 'none',
+    ADD COLUMN IF NOT EXISTS processing_attempts   INT          NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS processing_started_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS processing_error      TEXT         NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS processed_at          TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS width                 INT          NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS height                INT          NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS blurhash              VARCHAR(100) NOT NULL DEFAULT '';
+
+UPDATE attachments a
+SET image_state = 'pending'
+FROM blobs b
+WHERE b.sha256 = a.sha256
+  AND b.mime_type IN ('image/png', 'image/jpeg', 'image/gif', 'image/webp');
+
+CREATE INDEX IF NOT EXISTS attachments_image_queue_idx ON attachments (id)
+    WHERE image_state IN ('pending', 'processing');
+
+-- Варианты изображения: оригинал без метаданных, превью и WebP. Содержимое
+-- лежит в blobs, как и у самих вложений.
+CREATE TABLE IF NOT EXISTS attachment_variants
+(
+    attachment_id BIGINT      NOT NULL,
+    name          VARCHAR(50) NOT NULL,
+    sha256        CHAR(64)    NOT NULL,
+    width         INT         NOT NULL,
+    height        INT         NOT NULL,
+    PRIMARY KEY (attachment_id, name),
+    FOREIGN KEY (attachment_id) REFERENCES attachments (id) ON DELETE CASCADE,
+    FOREIGN KEY (sha256) REFERENCES blobs (sha256)
+);
+
+CREATE INDEX IF NOT EXISTS attachment_variants_sha256_idx ON attachment_variants (sha256);
+
+-- +goose Down
+DROP TABLE IF EXISTS attachment_variants;
+DROP INDEX IF EXISTS attachments_image_queue_idx;
+ALTER TABLE attachments
+    DROP COLUMN IF EXISTS image_state,
+    DROP COLUMN IF EXISTS processing_attempts,
+    DROP COLUMN IF EXISTS processing_started_at,
+    DROP COLUMN IF EXISTS processing_error,
+    DROP COLUMN IF EXISTS processed_at,
+    DROP COLUMN IF EXISTS width,
+    DROP COLUMN IF EXISTS height,
+    DROP COLUMN IF EXISTS blurhash;
@@ -0,0 +1,74 @@
+-- +goose Up
+-- Изменения постов, их тегов и видимых в лентах полей авторов сообщаются в
+-- канал feed_state. Экземпляры слушают его и сбрасывают кэш лент. NOTIFY
+-- доставляется после фиксации транзакции, поэтому к этому моменту изменение
+-- уже видно, а повторные сигналы одной транзакции сливаются в один. Общей
+-- строки-счётчика нет: пишущие транзакции не ждут друг друга.
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION feed_state_notify() RETURNS TRIGGER AS
+$$
+BEGIN
+    IF EXISTS (SELECT 1 FROM changed) THEN
+        PERFORM pg_notify('feed_state', '');
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION feed_state_notify_row() RETURNS TRIGGER AS
+$$
+BEGIN
+    PERFORM pg_notify('feed_state', '');
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- Планировщик и очистка корзины регулярно выполняют UPDATE, не затрагивающий
+-- ни одной строки, поэтому сигнал отправляется, только если строки есть.
+CREATE TRIGGER posts_feed_state_insert
+    AFTER INSERT ON posts REFERENCING NEW TABLE AS changed
+    FOR EACH STATEMENT EXECUTE FUNCTION feed_state_notify();
+CREATE TRIGGER posts_feed_state_delete
+    AFTER DELETE ON posts REFERENCING OLD TABLE AS changed
+    FOR EACH STATEMENT EXECUTE FUNCTION feed_state_notify();
+
+-- Перерисовка content_html при чтении содержимое лент не меняет.
+CREATE TRIGGER posts_feed_state_update
+    AFTER UPDATE ON posts
+    FOR EACH ROW
+    WHEN (OLD.title IS DISTINCT FROM NEW.title
+        OR OLD.content IS DISTINCT FROM NEW.content
+        OR OLD.author_uuid IS DISTINCT FROM NEW.author_uuid
+        OR OLD.status IS DISTINCT FROM NEW.status
+        OR OLD.published_at IS DISTINCT FROM NEW.published_at
+        OR OLD.deleted_at IS DISTINCT FROM NEW.deleted_at
+        OR OLD.hidden_at IS DISTINCT FROM NEW.hidden_at)
+    EXECUTE FUNCTION feed_state_notify_row();
+
+CREATE TRIGGER post_tags_feed_state_insert
+    AFTER INSERT ON post_tags REFERENCING NEW TABLE AS changed
+    FOR EACH STATEMENT EXECUTE FUNCTION feed_state_notify();
+CREATE TRIGGER post_tags_feed_state_delete
+    AFTER DELETE ON post_tags REFERENCING OLD TABLE AS changed
+    FOR EACH STATEMENT EXECUTE FUNCTION feed_state_notify();
+
+CREATE TRIGGER users_feed_state
+    AFTER UPDATE ON users
+    FOR EACH ROW
+    WHEN (OLD.first_name IS DISTINCT FROM NEW.first_name
+        OR OLD.account_state IS DISTINCT FROM NEW.account_state
+        OR OLD.state_until IS DISTINCT FROM NEW.state_until)
+    EXECUTE FUNCTION feed_state_notify_row();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS users_feed_state ON users;
+DROP TRIGGER IF EXISTS post_tags_feed_state_delete ON post_tags;
+DROP TRIGGER IF EXISTS post_tags_feed_state_insert ON post_tags;
+DROP TRIGGER IF EXISTS posts_feed_state_update ON posts;
+DROP TRIGGER IF EXISTS posts_feed_state_delete ON posts;
+DROP TRIGGER IF EXISTS posts_feed_state_insert ON posts;
+DROP FUNCTION IF EXISTS feed_state_notify_row();
+DROP FUNCTION IF EXISTS feed_state_notify();
@@ -0,0 +1,86 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS follows
+(
+    follower_uuid UUID                     NOT NULL,
+    followee_uuid UUID                     NOT NULL,
+    created_at    TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (follower_uuid, followee_uuid),
+    FOREIGN KEY (follower_uuid) REFERENCES users (uuid) ON DELETE CASCADE,
+    FOREIGN KEY (followee_uuid) REFERENCES users (uuid) ON DELETE CASCADE,
+    CHECK (follower_uuid <> followee_uuid)
+);
+
+CREATE INDEX IF NOT EXISTS follows_followers_idx ON follows (followee_uuid, created_at DESC, follower_uuid);
+CREATE INDEX IF NOT EXISTS follows_following_idx ON follows (follower_uuid, created_at DESC, followee_uuid);
+
+-- Посты обычных авторов лента читателя собирает при чтении по этому индексу.
+CREATE INDEX IF NOT EXISTS posts_author_published_idx ON posts (author_uuid, published_at DESC, id DESC) WHERE status = 'published';
+
+-- Авторы, которые публикуют много: их посты раскладываются по лентам
+-- подписчиков при публикации, а не собираются при каждом чтении.
+CREATE TABLE IF NOT EXISTS prolific_authors
+(
+    author_uuid UUID PRIMARY KEY,
+    since       TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (author_uuid) REFERENCES users (uuid) ON DELETE CASCADE
+);
+
+-- Предвычисленная часть ленты: посты авторов из prolific_authors у каждого
+-- подписчика. Видимость постов проверяется при чтении, устаревшие записи
+-- удаляет фоновая задача.
+CREATE TABLE IF NOT EXISTS timeline_entries
+(
+    user_uuid    UUID                     NOT NULL,
+    post_id      INTEGER                  NOT NULL,
+    author_uuid  UUID                     NOT NULL,
+    published_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (user_uuid, post_id),
+    FOREIGN KEY (user_uuid) REFERENCES users (uuid) ON DELETE CASCADE,
+    FOREIGN KEY (post_id) REFERENCES posts (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS timeline_entries_user_idx ON timeline_entries (user_uuid, published_at DESC, post_id DESC);
+CREATE INDEX IF NOT EXISTS timeline_entries_author_idx ON timeline_entries (author_uuid, user_uuid);
+CREATE INDEX IF NOT EXISTS timeline_entries_published_at_idx ON timeline_entries (published_at);
+
+-- Опубликованный пост автора из prolific_authors сразу попадает в ленты
+-- подписчиков. Разделяемая advisory-блокировка автора (класс 'tlne')
+-- не даёт разминуться с переводом автора в prolific_authors и обратно:
+-- после неё запрос видит уже зафиксированное состояние.
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION timeline_fan_out() RETURNS TRIGGER AS
+$$
+BEGIN
+    PERFORM pg_advisory_xact_lock_shared(1953263205, hashtext(NEW.author_uuid::TEXT));
+
+    IF EXISTS (SELECT 1 FROM prolific_authors WHERE author_uuid = NEW.author_uuid) THEN
+        INSERT INTO timeline_entries (user_uuid, post_id, author_uuid, published_at)
+        SELECT follower_uuid, NEW.id, NEW.author_uuid, NEW.published_at
+        FROM follows
+        WHERE followee_uuid = NEW.author_uuid
+        ON CONFLICT (user_uuid, post_id) DO UPDATE SET published_at = EXCLUDED.published_at;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER posts_timeline_insert
+    AFTER INSERT ON posts
+    FOR EACH ROW
+    WHEN (NEW.status = 'published')
+    EXECUTE FUNCTION timeline_fan_out();
+CREATE TRIGGER posts_timeline_update
+    AFTER UPDATE OF status, published_at ON posts
+    FOR EACH ROW
+    WHEN (NEW.status = 'published')
+    EXECUTE FUNCTION timeline_fan_out();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS posts_timeline_update ON posts;
+DROP TRIGGER IF EXISTS posts_timeline_insert ON posts;
+DROP FUNCTION IF EXISTS timeline_fan_out();
+DROP TABLE IF EXISTS timeline_entries;
+DROP TABLE IF EXISTS prolific_authors;
+DROP INDEX IF EXISTS posts_author_published_idx;
+DROP TABLE IF EXISTS follows;
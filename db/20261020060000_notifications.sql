@@ -0,0 +1,52 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS notifications
+(
+    id         BIGSERIAL PRIMARY KEY,
+    user_uuid  UUID                     NOT NULL,
+    type       VARCHAR(16)              NOT NULL CHECK (type IN ('reply', 'comment', 'mention', 'follow')),
+    actor_uuid UUID                     NOT NULL,
+    post_id    INTEGER,
+    comment_id INTEGER,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    read_at    TIMESTAMP WITH TIME ZONE,
+    FOREIGN KEY (user_uuid) REFERENCES users (uuid) ON DELETE CASCADE,
+    FOREIGN KEY (actor_uuid) REFERENCES users (uuid) ON DELETE CASCADE,
+    FOREIGN KEY (post_id) REFERENCES posts (id) ON DELETE CASCADE,
+    FOREIGN KEY (comment_id) REFERENCES comments (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS notifications_user_idx ON notifications (user_uuid, id DESC);
+CREATE INDEX IF NOT EXISTS notifications_unread_idx ON notifications (user_uuid, id DESC) WHERE read_at IS NULL;
+
+-- Отключённые пользователем типы уведомлений: уведомления этих типов не создаются.
+CREATE TABLE IF NOT EXISTS notification_mutes
+(
+    user_uuid UUID        NOT NULL,
+    type      VARCHAR(16) NOT NULL,
+    PRIMARY KEY (user_uuid, type),
+    FOREIGN KEY (user_uuid) REFERENCES users (uuid) ON DELETE CASCADE
+);
+
+-- Экземпляры слушают канал notifications и будят открытые потоки
+-- WatchNotifications получателя. NOTIFY доставляется после фиксации
+-- транзакции, поэтому к этому моменту уведомление уже видно.
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION notifications_notify() RETURNS TRIGGER AS
+$$
+BEGIN
+    PERFORM pg_notify('notifications', NEW.user_uuid::TEXT);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER notifications_notify
+    AFTER INSERT ON notifications
+    FOR EACH ROW
+    EXECUTE FUNCTION notifications_notify();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS notifications_notify ON notifications;
+DROP FUNCTION IF EXISTS notifications_notify();
+DROP TABLE IF EXISTS notification_mutes;
+DROP TABLE IF EXISTS notifications;
@@ -0,0 +1,52 @@
+-- +goose Up
+ALTER TABLE users ADD COLUMN IF NOT EXISTS username VARCHAR(30);
+
+-- Имя хранится в написании владельца, уникально без учёта регистра.
+CREATE UNIQUE INDEX IF NOT EXISTS users_username_idx ON users (LOWER(username));
+
+-- Прежние имена пользователей. Освобождённое имя какое-то время недоступно
+-- другим, чтобы старые ссылки на профиль не вели к чужому человеку.
+CREATE TABLE IF NOT EXISTS username_history
+(
+    id         BIGSERIAL PRIMARY KEY,
+    user_uuid  UUID                     NOT NULL,
+    username   VARCHAR(30)              NOT NULL,
+    changed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (user_uuid) REFERENCES users (uuid) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS username_history_user_idx ON username_history (user_uuid, id DESC);
+CREATE INDEX IF NOT EXISTS username_history_username_idx ON username_history (LOWER(username), changed_at);
+
+-- Упоминания хранят пользователя, которому handle принадлежал при записи:
+-- переименование не переносит их на другого человека.
+CREATE TABLE IF NOT EXISTS post_mentions
+(
+    post_id     INTEGER                  NOT NULL,
+    user_uuid   UUID                     NOT NULL,
+    handle      VARCHAR(30)              NOT NULL,
+    -- Уведомление отправляется, когда пост впервые виден всем.
+    notified_at TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (post_id, user_uuid),
+    FOREIGN KEY (post_id) REFERENCES posts (id) ON DELETE CASCADE,
+    FOREIGN KEY (user_uuid) REFERENCES users (uuid) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS post_mentions_pending_idx ON post_mentions (post_id) WHERE notified_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS comment_mentions
+(
+    comment_id INTEGER     NOT NULL,
+    user_uuid  UUID        NOT NULL,
+    handle     VARCHAR(30) NOT NULL,
+    PRIMARY KEY (comment_id, user_uuid),
+    FOREIGN KEY (comment_id) REFERENCES comments (id) ON DELETE CASCADE,
+    FOREIGN KEY (user_uuid) REFERENCES users (uuid) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS comment_mentions;
+DROP TABLE IF EXISTS post_mentions;
+DROP TABLE IF EXISTS username_history;
+DROP INDEX IF EXISTS users_username_idx;
+ALTER TABLE users DROP COLUMN IF EXISTS username;
@@ -0,0 +1,32 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS bookmark_collections
+(
+    id         BIGSERIAL PRIMARY KEY,
+    user_uuid  UUID                     NOT NULL,
+    name       VARCHAR(64)              NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (user_uuid) REFERENCES users (uuid) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS bookmark_collections_name_idx ON bookmark_collections (user_uuid, LOWER(name));
+
+-- Закладка без коллекции лежит в общем списке. При удалении коллекции её
+-- закладки остаются в нём.
+CREATE TABLE IF NOT EXISTS bookmarks
+(
+    user_uuid     UUID                     NOT NULL,
+    post_id       INTEGER                  NOT NULL,
+    collection_id BIGINT,
+    created_at    TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_uuid, post_id),
+    FOREIGN KEY (user_uuid) REFERENCES users (uuid) ON DELETE CASCADE,
+    FOREIGN KEY (post_id) REFERENCES posts (id) ON DELETE CASCADE,
+    FOREIGN KEY (collection_id) REFERENCES bookmark_collections (id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS bookmarks_user_idx ON bookmarks (user_uuid, created_at DESC, post_id DESC);
+CREATE INDEX IF NOT EXISTS bookmarks_collection_idx ON bookmarks (collection_id, created_at DESC, post_id DESC) WHERE collection_id IS NOT NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS bookmarks;
+DROP TABLE IF EXISTS bookmark_collections;
@@ -0,0 +1,26 @@
+-- +goose Up
+-- Закладка может лежать только в коллекции своего владельца. Внешний ключ по
+-- одному id пропускал чужую коллекцию, если её id угадан.
+UPDATE bookmarks b
+SET collection_id = NULL
+WHERE collection_id IS NOT NULL
+  AND NOT EXISTS (SELECT 1 FROM bookmark_collections c WHERE c.id = b.collection_id AND c.user_uuid = b.user_uuid);
+
+ALTER TABLE bookmark_collections
+    ADD CONSTRAINT bookmark_collections_id_user_uuid_key UNIQUE (id, user_uuid);
+
+-- При удалении коллекции обнуляется только collection_id: user_uuid входит
+-- в первичный ключ закладки.
+ALTER TABLE bookmarks DROP CONSTRAINT IF EXISTS bookmarks_collection_id_fkey;
+ALTER TABLE bookmarks
+    ADD CONSTRAINT bookmarks_collection_id_fkey FOREIGN KEY (collection_id, user_uuid)
+        REFERENCES bookmark_collections (id, user_uuid) ON DELETE SET NULL (collection_id);
+
+-- +goose Down
+ALTER TABLE bookmarks DROP CONSTRAINT IF EXISTS bookmarks_collection_id_fkey;
+ALTER TABLE bookmarks
+    ADD CONSTRAINT bookmarks_collection_id_fkey FOREIGN KEY (collection_id)
+        REFERENCES bookmark_collections (id) ON DELETE SET NULL;
+
+ALTER TABLE bookmark_collections
+    DROP CONSTRAINT IF EXISTS bookmark_collections_id_user_uuid_key;
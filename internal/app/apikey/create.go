@@ -0,0 +1,27 @@
+package apikey
+
+import (
+	"context"
+	"github.com/AdilBaidual/baseProject/internal/app/grpcerr"
+	"github.com/AdilBaidual/baseProject/internal/auth"
+	"github.com/AdilBaidual/baseProject/internal/pb/baseProject/apikey"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (h *Handler) CreateApiKey(ctx context.Context, req *apikey.CreateApiKeyRequest) (*apikey.CreateApiKeyResponse, error) {
+	identity, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	key, raw, err := h.apiKeyService.Create(ctx, identity, req.GetName(), req.GetScopes(), req.GetTtl().AsDuration())
+	if err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	return &apikey.CreateApiKeyResponse{
+		ApiKey: toProto(key),
+		Key:    raw,
+	}, nil
+}
@@ -0,0 +1,58 @@
+package apikey
+
+import (
+	"context"
+	"github.com/AdilBaidual/baseProject/internal/auth"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/AdilBaidual/baseProject/internal/pb/baseProject/apikey"
+	"github.com/google/uuid"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"time"
+)
+
+type apiKeyService interface {
+	Create(ctx context.Context, owner auth.Identity, name string, scopes []string, ttl time.Duration) (model.APIKey, string, error)
+	List(ctx context.Context, userUUID uuid.UUID) ([]model.APIKey, error)
+	Revoke(ctx context.Context, userUUID, id uuid.UUID) error
+}
+
+type Handler struct {
+	apikey.ApiKeyServiceServer
+
+	apiKeyService apiKeyService
+}
+
+func NewHandler(apiKeyService apiKeyService) *Handler {
+	return &Handler{apiKeyService: apiKeyService}
+}
+
+func Register(ctx context.Context, mux *runtime.ServeMux, conn *grpc.ClientConn, gRPCServer *grpc.Server, handler *Handler) error {
+	apikey.RegisterApiKeyServiceServer(gRPCServer, handler)
+	err := apikey.RegisterApiKeyServiceHandler(ctx, mux, conn)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func toProto(key model.APIKey) *apikey.ApiKey {
+	return &apikey.ApiKey{
+		Id:         key.ID.String(),
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     key.Scopes,
+		CreatedAt:  timestamppb.New(key.CreatedAt),
+		ExpiresAt:  optionalTimestamp(key.ExpiresAt),
+		LastUsedAt: optionalTimestamp(key.LastUsedAt),
+		RevokedAt:  optionalTimestamp(key.RevokedAt),
+	}
+}
+
+func optionalTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
@@ -0,0 +1,30 @@
+package apikey
+
+import (
+	"context"
+	"github.com/AdilBaidual/baseProject/internal/app/grpcerr"
+	"github.com/AdilBaidual/baseProject/internal/auth"
+	"github.com/AdilBaidual/baseProject/internal/pb/baseProject/apikey"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func (h *Handler) ListApiKeys(ctx context.Context, _ *emptypb.Empty) (*apikey.ListApiKeysResponse, error) {
+	identity, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	keys, err := h.apiKeyService.List(ctx, identity.UserUUID)
+	if err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	resp := &apikey.ListApiKeysResponse{ApiKeys: make([]*apikey.ApiKey, 0, len(keys))}
+	for _, key := range keys {
+		resp.ApiKeys = append(resp.ApiKeys, toProto(key))
+	}
+
+	return resp, nil
+}
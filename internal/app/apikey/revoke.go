@@ -0,0 +1,30 @@
+package apikey
+
+import (
+	"context"
+	"github.com/AdilBaidual/baseProject/internal/app/grpcerr"
+	"github.com/AdilBaidual/baseProject/internal/auth"
+	"github.com/AdilBaidual/baseProject/internal/pb/baseProject/apikey"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func (h *Handler) RevokeApiKey(ctx context.Context, req *apikey.RevokeApiKeyRequest) (*emptypb.Empty, error) {
+	identity, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	id, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid api key id")
+	}
+
+	if err = h.apiKeyService.Revoke(ctx, identity.UserUUID, id); err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
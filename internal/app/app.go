@@ -2,28 +2,65 @@ package app
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"github.com/AdilBaidual/baseProject/config"
+	"github.com/AdilBaidual/baseProject/constant"
+	apikeyhandler "github.com/AdilBaidual/baseProject/internal/app/apikey"
+	attachmenthandler "github.com/AdilBaidual/baseProject/internal/app/attachment"
+	bookmarkhandler "github.com/AdilBaidual/baseProject/internal/app/bookmark"
+	feedhandler "github.com/AdilBaidual/baseProject/internal/app/feed"
+	followhandler "github.com/AdilBaidual/baseProject/internal/app/follow"
+	moderationhandler "github.com/AdilBaidual/baseProject/internal/app/moderation"
+	notificationhandler "github.com/AdilBaidual/baseProject/internal/app/notification"
+	policyhandler "github.com/AdilBaidual/baseProject/internal/app/policy"
+	posthandler "github.com/AdilBaidual/baseProject/internal/app/post"
+	reactionhandler "github.com/AdilBaidual/baseProject/internal/app/reaction"
+	ssohandler "github.com/AdilBaidual/baseProject/internal/app/sso"
 	testhandler "github.com/AdilBaidual/baseProject/internal/app/test"
+	userhandler "github.com/AdilBaidual/baseProject/internal/app/user"
+	"github.com/AdilBaidual/baseProject/internal/auth"
 	"github.com/AdilBaidual/baseProject/internal/interceptor"
+	"github.com/AdilBaidual/baseProject/internal/mail"
 	"github.com/AdilBaidual/baseProject/internal/service"
+	"github.com/AdilBaidual/baseProject/internal/service/attachment_service"
+	"github.com/AdilBaidual/baseProject/internal/service/bookmark_service"
+	"github.com/AdilBaidual/baseProject/internal/service/feed_service"
+	"github.com/AdilBaidual/baseProject/internal/service/follow_service"
+	"github.com/AdilBaidual/baseProject/internal/service/notification_service"
+	"github.com/AdilBaidual/baseProject/internal/service/policy_service"
+	"github.com/AdilBaidual/baseProject/internal/service/post_service"
+	"github.com/AdilBaidual/baseProject/internal/service/trash_service"
+	"github.com/AdilBaidual/baseProject/internal/service/user_service"
 	"github.com/AdilBaidual/baseProject/internal/store"
+	"github.com/AdilBaidual/baseProject/pkg/clientip"
+	"github.com/AdilBaidual/baseProject/pkg/concurrency"
 	"github.com/AdilBaidual/baseProject/pkg/grpcserver"
 	"github.com/AdilBaidual/baseProject/pkg/httpserver"
 	"github.com/AdilBaidual/baseProject/pkg/jaeger"
+	"github.com/AdilBaidual/baseProject/pkg/mailer"
+	"github.com/AdilBaidual/baseProject/pkg/ratelimit"
+	"github.com/AdilBaidual/baseProject/pkg/secretbox"
+	"github.com/AdilBaidual/baseProject/pkg/sso"
+	"github.com/AdilBaidual/baseProject/pkg/storage/blob"
 	"github.com/AdilBaidual/baseProject/pkg/storage/postgres"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"net"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 )
 
 func NewApp() fx.Option {
@@ -32,8 +69,13 @@ func NewApp() fx.Option {
 		LoggerModule(),
 		PostgresModule(),
 		RepositoryModule(),
+		MailerModule(),
+		BlobStorageModule(),
 		ServiceModule(),
 		JaegerModule(),
+		AuthModule(),
+		RateLimitModule(),
+		ConcurrencyModule(),
 		HandlerModule(),
 		DeliveryModule(),
 		CheckInitializedModules(),
@@ -136,11 +178,266 @@ func RepositoryModule() fx.Option {
 	)
 }
 
+func MailerModule() fx.Option {
+	return fx.Module("mailer",
+		fx.Provide(
+			func(cfg *config.Config) mailer.Config {
+				return cfg.Mailer
+			},
+			mailer.New,
+			mail.NewTemplates,
+		),
+	)
+}
+
+func BlobStorageModule() fx.Option {
+	return fx.Module("blob storage",
+		fx.Provide(
+			func(cfg *config.Config) blob.Config {
+				return cfg.Blob
+			},
+			func(cfg blob.Config) (blob.Storage, error) {
+				return blob.New(context.Background(), cfg)
+			},
+		),
+	)
+}
+
 func ServiceModule() fx.Option {
 	return fx.Module("service",
 		fx.Provide(
+			func(cfg *config.Config) user_service.Config {
+				return cfg.Users
+			},
+			func(cfg user_service.Config) (*secretbox.Box, error) {
+				return secretbox.NewFromBase64(cfg.MFAEncryptionKey)
+			},
+			func(cfg *config.Config) sso.Config {
+				return cfg.SSO
+			},
+			sso.NewRegistry,
+			func(cfg *config.Config) post_service.Config {
+				return cfg.Posts
+			},
+			func(cfg *config.Config) trash_service.Config {
+				return cfg.Trash
+			},
+			func(cfg *config.Config) policy_service.Config {
+				return cfg.Policy
+			},
+			func(cfg *config.Config) attachment_service.Config {
+				return cfg.Attachments
+			},
+			func(cfg *config.Config) feed_service.Config {
+				return cfg.Feeds
+			},
+			func(cfg *config.Config) follow_service.Config {
+				return cfg.Follows
+			},
+			func(cfg *config.Config) notification_service.Config {
+				return cfg.Notifications
+			},
+			func(cfg *config.Config) bookmark_service.Config {
+				return cfg.Bookmarks
+			},
 			service.NewServiceContainer,
 		),
+		fx.Invoke(
+			// Планировщик публикаций запускается в каждом экземпляре, очередь
+			// разбирает тот, кто первым возьмёт advisory-блокировку в Postgres.
+			func(lc fx.Lifecycle, sc *service.ServiceContainer, logger *zap.Logger) {
+				postService := sc.GetPostService()
+				runPeriodic(lc, logger, "post schedule", postService.SchedulerInterval(), postService.ApplySchedule)
+			},
+			// Очистку корзины, как и планировщик, выполняет один экземпляр за раз.
+			func(lc fx.Lifecycle, sc *service.ServiceContainer, logger *zap.Logger) {
+				trashService := sc.GetTrashService()
+				runPeriodic(lc, logger, "trash purge", trashService.PurgeInterval(), trashService.Purge)
+			},
+			// Счётчики неудачных входов, журнал аудита и одноразовые токены чистятся
+			// идемпотентными запросами, поэтому очистка запускается в каждом экземпляре.
+			func(lc fx.Lifecycle, sc *service.ServiceContainer, logger *zap.Logger) {
+				userService := sc.GetUserService()
+				runPeriodic(lc, logger, "user cleanup", userService.CleanupInterval(), userService.Cleanup)
+			},
+			// Просроченные state входа через внешних провайдеров удаляются одним
+			// запросом, параллельный запуск в нескольких экземплярах безопасен.
+			func(lc fx.Lifecycle, sc *service.ServiceContainer, logger *zap.Logger) {
+				ssoService := sc.GetSSOService()
+				runPeriodic(lc, logger, "sso state cleanup", ssoService.CleanupInterval(), ssoService.Cleanup)
+			},
+			// Сборку мусора в blob-хранилище и очередь обработки изображений
+			// экземпляры делят между собой через блокировки строк, поэтому они
+			// тоже запускаются в каждом.
+			func(lc fx.Lifecycle, sc *service.ServiceContainer, logger *zap.Logger) {
+				attachmentService := sc.GetAttachmentService()
+				runPeriodic(lc, logger, "blob gc", attachmentService.GCInterval(), attachmentService.CollectGarbage)
+				runPeriodic(lc, logger, "image processing", attachmentService.ImageInterval(), attachmentService.ProcessImages)
+			},
+			// Режим ленты авторов переключается по одному в отдельных транзакциях,
+			// поэтому пересчёт запускается в каждом экземпляре.
+			func(lc fx.Lifecycle, sc *service.ServiceContainer, logger *zap.Logger) {
+				followService := sc.GetFollowService()
+				runPeriodic(lc, logger, "timeline refresh", followService.RefreshInterval(), followService.RefreshTimelines)
+			},
+			// Каждый экземпляр слушает изменения постов в Postgres и сбрасывает
+			// свой кэш лент.
+			func(lc fx.Lifecycle, sc *service.ServiceContainer) {
+				feedService := sc.GetFeedService()
+
+				ctx, cancel := context.WithCancel(context.Background())
+				lc.Append(fx.Hook{
+					OnStart: func(context.Context) error {
+						go feedService.Listen(ctx)
+						return nil
+					},
+					OnStop: func(context.Context) error {
+						cancel()
+						return nil
+					},
+				})
+			},
+			// Каждый экземпляр слушает новые уведомления в Postgres, чтобы будить
+			// свои потоки WatchNotifications, на каком бы экземпляре ни создано уведомление.
+			func(lc fx.Lifecycle, sc *service.ServiceContainer) {
+				notificationService := sc.GetNotificationService()
+
+				ctx, cancel := context.WithCancel(context.Background())
+				lc.Append(fx.Hook{
+					OnStart: func(context.Context) error {
+						go notificationService.Listen(ctx)
+						return nil
+					},
+					OnStop: func(context.Context) error {
+						cancel()
+						return nil
+					},
+				})
+			},
+			// Правила контент-политики перечитываются каждым экземпляром, так
+			// изменения с другого экземпляра применяются без перезапуска.
+			// Первая загрузка синхронная: серверы стартуют позже, и без правил
+			// экземпляр принимал бы любой контент.
+			func(lc fx.Lifecycle, sc *service.ServiceContainer, logger *zap.Logger) {
+				policyService := sc.GetPolicyService()
+				lc.Append(fx.Hook{OnStart: policyService.Reload})
+				runPeriodic(lc, logger, "content rules reload", policyService.ReloadInterval(), policyService.Reload)
+			},
+		),
+	)
+}
+
+func AuthModule() fx.Option {
+	return fx.Module("auth",
+		fx.Provide(
+			func(cfg *config.Config) auth.Config {
+				return cfg.Auth
+			},
+			auth.NewTokenManager,
+			auth.NewPolicy,
+			func(logger *zap.Logger, policy *auth.Policy, sc *service.ServiceContainer) *interceptor.Auth {
+				return interceptor.NewAuth(logger, policy, sc.GetUserService(), sc.GetAPIKeyService())
+			},
+		),
+	)
+}
+
+func RateLimitModule() fx.Option {
+	return fx.Module("rate limit",
+		fx.Provide(
+			func(cfg *config.Config) ratelimit.Config {
+				return cfg.RateLimit
+			},
+			func(cfg ratelimit.Config) (*clientip.Resolver, error) {
+				return clientip.NewResolver(cfg.TrustedProxies)
+			},
+			ratelimit.NewBackend,
+			interceptor.NewRateLimiter,
+		),
+		fx.Invoke(
+			func(lc fx.Lifecycle, backend ratelimit.Backend, cfg ratelimit.Config, logger *zap.Logger) {
+				if cfg.CleanupInterval <= 0 {
+					return
+				}
+
+				ctx, cancel := context.WithCancel(context.Background())
+				lc.Append(fx.Hook{
+					OnStart: func(context.Context) error {
+						go func() {
+							ticker := time.NewTicker(cfg.CleanupInterval)
+							defer ticker.Stop()
+
+							for {
+								select {
+								case <-ctx.Done():
+									return
+								case <-ticker.C:
+									if err := backend.Cleanup(ctx, cfg.CleanupInterval); err != nil {
+										logger.Error("error cleaning up rate limit buckets", zap.Error(err))
+									}
+								}
+							}
+						}()
+						return nil
+					},
+					OnStop: func(context.Context) error {
+						cancel()
+						return nil
+					},
+				})
+			},
+		),
+	)
+}
+
+func ConcurrencyModule() fx.Option {
+	return fx.Module("concurrency",
+		fx.Provide(
+			func(cfg *config.Config) concurrency.Config {
+				return cfg.Concurrency
+			},
+			concurrency.NewAlgorithm,
+			concurrency.NewLimiter,
+			interceptor.NewConcurrencyLimiter,
+		),
+		fx.Invoke(
+			func(limiter *concurrency.Limiter) error {
+				return concurrency.RegisterMetrics(otel.Meter(constant.ServiceName), limiter)
+			},
+			// Состояние ограничителя раскрывает нагрузку сервиса, поэтому
+			// отдаётся не через шлюз, а на отдельном внутреннем адресе.
+			func(lc fx.Lifecycle, cfg concurrency.Config, limiter *concurrency.Limiter, logger *zap.Logger) {
+				if cfg.DebugAddr == "" {
+					return
+				}
+
+				mux := http.NewServeMux()
+				mux.HandleFunc("GET /debug/concurrency", func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set("Content-Type", "application/json")
+					_ = json.NewEncoder(w).Encode(limiter.Snapshot())
+				})
+				srv := &http.Server{Addr: cfg.DebugAddr, Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+
+				lc.Append(fx.Hook{
+					OnStart: func(context.Context) error {
+						listener, err := net.Listen("tcp", cfg.DebugAddr)
+						if err != nil {
+							return err
+						}
+
+						go func() {
+							if err := srv.Serve(listener); err != nil && err != http.ErrServerClosed {
+								logger.Error("debug server stopped", zap.Error(err))
+							}
+						}()
+						return nil
+					},
+					OnStop: func(ctx context.Context) error {
+						return srv.Shutdown(ctx)
+					},
+				})
+			},
+		),
 	)
 }
 
@@ -150,6 +447,42 @@ func HandlerModule() fx.Option {
 			func(sc *service.ServiceContainer) *testhandler.Handler {
 				return testhandler.NewHandler(sc.GetTestService())
 			},
+			func(logger *zap.Logger, sc *service.ServiceContainer, resolver *clientip.Resolver) *ssohandler.Handler {
+				return ssohandler.NewHandler(logger, sc.GetSSOService(), resolver)
+			},
+			func(sc *service.ServiceContainer, resolver *clientip.Resolver) *userhandler.Handler {
+				return userhandler.NewHandler(sc.GetUserService(), resolver)
+			},
+			func(sc *service.ServiceContainer) *apikeyhandler.Handler {
+				return apikeyhandler.NewHandler(sc.GetAPIKeyService())
+			},
+			func(sc *service.ServiceContainer) *posthandler.Handler {
+				return posthandler.NewHandler(sc.GetPostService())
+			},
+			func(sc *service.ServiceContainer) *reactionhandler.Handler {
+				return reactionhandler.NewHandler(sc.GetReactionService())
+			},
+			func(sc *service.ServiceContainer) *moderationhandler.Handler {
+				return moderationhandler.NewHandler(sc.GetModerationService())
+			},
+			func(sc *service.ServiceContainer) *policyhandler.Handler {
+				return policyhandler.NewHandler(sc.GetPolicyService())
+			},
+			func(sc *service.ServiceContainer) *attachmenthandler.Handler {
+				return attachmenthandler.NewHandler(sc.GetAttachmentService())
+			},
+			func(sc *service.ServiceContainer) *followhandler.Handler {
+				return followhandler.NewHandler(sc.GetFollowService())
+			},
+			func(sc *service.ServiceContainer) *bookmarkhandler.Handler {
+				return bookmarkhandler.NewHandler(sc.GetBookmarkService())
+			},
+			func(sc *service.ServiceContainer, au *interceptor.Auth) *notificationhandler.Handler {
+				return notificationhandler.NewHandler(sc.GetNotificationService(), au)
+			},
+			func(sc *service.ServiceContainer) *feedhandler.Handler {
+				return feedhandler.NewHandler(sc.GetFeedService())
+			},
 		),
 	)
 }
@@ -163,16 +496,49 @@ func DeliveryModule() fx.Option {
 			func() context.Context {
 				return context.Background()
 			},
+			func(cfg *config.Config) interceptor.DeadlineConfig {
+				return cfg.Deadlines
+			},
 			interceptor.NewInterceptor,
-			func(ic *interceptor.Interceptor) []grpc.ServerOption {
+			interceptor.NewDeadline,
+			func(ic *interceptor.Interceptor, dl *interceptor.Deadline, cl *interceptor.ConcurrencyLimiter, au *interceptor.Auth, rl *interceptor.RateLimiter) []grpc.ServerOption {
 				return []grpc.ServerOption{
-					grpc.UnaryInterceptor(ic.LoggingInterceptor()),
+					grpc.ChainUnaryInterceptor(
+						ic.LoggingInterceptor(),
+						dl.DeadlineInterceptor(),
+						cl.ConcurrencyLimitInterceptor(),
+						au.AuthenticationInterceptor(),
+						rl.RateLimitInterceptor(),
+						au.AuthorizationInterceptor(),
+						au.RestrictionInterceptor(),
+					),
+					// Потоки живут дольше обычного дедлайна и не занимают слот
+					// ограничителя параллельности, остальные проверки те же.
+					grpc.ChainStreamInterceptor(
+						ic.StreamLoggingInterceptor(),
+						au.StreamAuthenticationInterceptor(),
+						rl.StreamRateLimitInterceptor(),
+						au.StreamAuthorizationInterceptor(),
+						au.StreamRestrictionInterceptor(),
+					),
 					grpc.StatsHandler(otelgrpc.NewServerHandler()),
 				}
 			},
-			runtime.NewServeMux,
-			func(mux *runtime.ServeMux) http.Handler {
-				return mux
+			NewServeMux,
+			// Gateway обслуживает всё, кроме эндпоинтов входа через внешних провайдеров и лент.
+			func(mux *runtime.ServeMux, ssoHandler *ssohandler.Handler, feedHandler *feedhandler.Handler, cl *interceptor.ConcurrencyLimiter, rl *interceptor.RateLimiter) http.Handler {
+				root := http.NewServeMux()
+				root.Handle("/", mux)
+				ssohandler.Register(root, ssoHandler, func(method string, next http.Handler) http.Handler {
+					return cl.HTTPHandler(method, rl.HTTPHandler(method, next))
+				})
+				feedhandler.Register(root, feedHandler)
+				// Поток уведомлений бессрочный: общий WriteTimeout сервера оборвал бы его.
+				root.HandleFunc("GET /v1/notifications/watch", func(w http.ResponseWriter, r *http.Request) {
+					_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+					mux.ServeHTTP(w, r)
+				})
+				return root
 			},
 			func(cfg grpcserver.Config) (*grpc.ClientConn, error) {
 				return grpc.NewClient(
@@ -187,6 +553,19 @@ func DeliveryModule() fx.Option {
 		),
 		fx.Invoke(
 			testhandler.Register,
+			userhandler.Register,
+			apikeyhandler.Register,
+			posthandler.Register,
+			reactionhandler.Register,
+			moderationhandler.Register,
+			policyhandler.Register,
+			attachmenthandler.Register,
+			followhandler.Register,
+			bookmarkhandler.Register,
+			notificationhandler.Register,
+			func(gRPCServer *grpc.Server) {
+				healthpb.RegisterHealthServer(gRPCServer, health.NewServer())
+			},
 			func(lc fx.Lifecycle, srv *grpcserver.Server, cfg grpcserver.Config, logger *zap.Logger, shutdowner fx.Shutdowner) {
 				lc.Append(fx.Hook{
 					OnStart: func(ctx context.Context) error {
@@ -232,10 +611,67 @@ func DeliveryModule() fx.Option {
 					},
 				})
 			},
+			// Хуки останавливаются в обратном порядке: потоки уведомлений
+			// закрываются раньше, чем серверы начнут ждать завершения вызовов.
+			func(lc fx.Lifecycle, sc *service.ServiceContainer) {
+				lc.Append(fx.Hook{
+					OnStop: func(context.Context) error {
+						sc.GetNotificationService().Close()
+						return nil
+					},
+				})
+			},
 		),
 	)
 }
 
+func NewServeMux() *runtime.ServeMux {
+	return runtime.NewServeMux(
+		runtime.WithIncomingHeaderMatcher(func(key string) (string, bool) {
+			if strings.EqualFold(key, interceptor.APIKeyHeader) {
+				return interceptor.APIKeyHeader, true
+			}
+			return runtime.DefaultHeaderMatcher(key)
+		}),
+		runtime.WithOutgoingHeaderMatcher(func(key string) (string, bool) {
+			if key == interceptor.RetryAfterHeader {
+				return "Retry-After", true
+			}
+			return runtime.MetadataHeaderPrefix + key, true
+		}),
+	)
+}
+
+// runPeriodic запускает fn раз в interval от старта приложения до остановки.
+// Ошибка запуска только логируется: следующий запуск повторит работу.
+func runPeriodic(lc fx.Lifecycle, logger *zap.Logger, name string, interval time.Duration, fn func(context.Context) error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				ticker := time.NewTicker(interval)
+				defer ticker.Stop()
+
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticker.C:
+						if err := fn(ctx); err != nil {
+							logger.Error("error running periodic task", zap.String("task", name), zap.Error(err))
+						}
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			cancel()
+			return nil
+		},
+	})
+}
+
 func CheckInitializedModules() fx.Option {
 	return fx.Module("check modules",
 		fx.Invoke(
@@ -245,6 +681,18 @@ func CheckInitializedModules() fx.Option {
 			func(store *store.Store) {},
 			func(test *service.ServiceContainer) {},
 			func(test *testhandler.Handler) {},
+			func(user *userhandler.Handler) {},
+			func(sso *ssohandler.Handler) {},
+			func(apiKey *apikeyhandler.Handler) {},
+			func(post *posthandler.Handler) {},
+			func(reaction *reactionhandler.Handler) {},
+			func(moderation *moderationhandler.Handler) {},
+			func(policy *policyhandler.Handler) {},
+			func(attachment *attachmenthandler.Handler) {},
+			func(follow *followhandler.Handler) {},
+			func(bookmark *bookmarkhandler.Handler) {},
+			func(notification *notificationhandler.Handler) {},
+			func(feed *feedhandler.Handler) {},
 			//func(tracer *sdktrace.TracerProvider) {},
 			func(srv *grpcserver.Server) {},
 			func(srv *httpserver.Server) {},
@@ -0,0 +1,142 @@
+package attachment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/AdilBaidual/baseProject/internal/app/grpcerr"
+	"github.com/AdilBaidual/baseProject/internal/auth"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/AdilBaidual/baseProject/internal/pb/baseProject/post"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"io"
+)
+
+func (h *Handler) Upload(stream post.AttachmentService_UploadServer) error {
+	ctx := stream.Context()
+
+	identity, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+
+	metadata := first.GetMetadata()
+	if metadata == nil {
+		return status.Error(codes.InvalidArgument, "first message must contain metadata")
+	}
+
+	attachment, err := h.attachmentService.Upload(ctx, identity, metadata.GetPostId(), metadata.GetFilename(), &chunkReader{stream: stream})
+	if err != nil {
+		return grpcerr.ToStatus(ctx, err)
+	}
+
+	return stream.SendAndClose(toProto(attachment))
+}
+
+func (h *Handler) Download(req *post.DownloadAttachmentRequest, stream post.AttachmentService_DownloadServer) error {
+	ctx := stream.Context()
+
+	attachment, variant, content, err := h.attachmentService.Open(ctx, viewer(ctx), req.GetId(), req.GetVariant())
+	if err != nil {
+		return grpcerr.ToStatus(ctx, err)
+	}
+	defer content.Close() //nolint:errcheck
+
+	first := &post.DownloadAttachmentResponse{Data: &post.DownloadAttachmentResponse_Attachment{Attachment: toProto(attachment)}}
+	if variant.Name != "" {
+		first.Data = &post.DownloadAttachmentResponse_Variant{Variant: variantToProto(attachment.ID, variant)}
+	}
+	if err = stream.Send(first); err != nil {
+		return err
+	}
+
+	buf := make([]byte, chunkSize)
+	for {
+		n, err := io.ReadFull(content, buf)
+		if n > 0 {
+			if sendErr := stream.Send(&post.DownloadAttachmentResponse{Data: &post.DownloadAttachmentResponse_Chunk{Chunk: buf[:n]}}); sendErr != nil {
+				return sendErr
+			}
+		}
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil
+		}
+		if err != nil {
+			return grpcerr.ToStatus(ctx, fmt.Errorf("Download - Read - %w", err))
+		}
+	}
+}
+
+func (h *Handler) GetAttachment(ctx context.Context, req *post.GetAttachmentRequest) (*post.Attachment, error) {
+	attachment, err := h.attachmentService.GetAttachment(ctx, viewer(ctx), req.GetId())
+	if err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	return toProto(attachment), nil
+}
+
+func (h *Handler) ListPostAttachments(ctx context.Context, req *post.ListPostAttachmentsRequest) (*post.ListPostAttachmentsResponse, error) {
+	attachments, err := h.attachmentService.ListAttachments(ctx, viewer(ctx), req.GetPostId())
+	if err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	resp := &post.ListPostAttachmentsResponse{Attachments: make([]*post.Attachment, 0, len(attachments))}
+	for _, attachment := range attachments {
+		resp.Attachments = append(resp.Attachments, toProto(attachment))
+	}
+
+	return resp, nil
+}
+
+func (h *Handler) DeleteAttachment(ctx context.Context, req *post.DeleteAttachmentRequest) (*emptypb.Empty, error) {
+	identity, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	if err := h.attachmentService.DeleteAttachment(ctx, identity, req.GetId()); err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// chunkReader читает содержимое из сообщений потока загрузки.
+type chunkReader struct {
+	stream post.AttachmentService_UploadServer
+	buf    []byte
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		msg, err := r.stream.Recv()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return 0, io.EOF
+			}
+			if ctxErr := r.stream.Context().Err(); ctxErr != nil {
+				return 0, ctxErr
+			}
+			return 0, err
+		}
+
+		if msg.GetMetadata() != nil {
+			return 0, fmt.Errorf("%w: metadata must be sent only once", model.ErrInvalidArgument)
+		}
+		r.buf = msg.GetChunk()
+	}
+
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+
+	return n, nil
+}
@@ -0,0 +1,101 @@
+package attachment
+
+import (
+	"context"
+	"github.com/AdilBaidual/baseProject/internal/auth"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/AdilBaidual/baseProject/internal/pb/baseProject/post"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// chunkSize размер части содержимого в сообщениях потока.
+const chunkSize = 64 << 10
+
+type attachmentService interface {
+	Upload(ctx context.Context, uploader auth.Identity, postID int64, filename string, r io.Reader) (model.Attachment, error)
+	Open(ctx context.Context, viewer auth.Identity, id int64, variant string) (model.Attachment, model.AttachmentVariant, io.ReadCloser, error)
+	GetAttachment(ctx context.Context, viewer auth.Identity, id int64) (model.Attachment, error)
+	ListAttachments(ctx context.Context, viewer auth.Identity, postID int64) ([]model.Attachment, error)
+	DeleteAttachment(ctx context.Context, editor auth.Identity, id int64) error
+}
+
+type Handler struct {
+	post.AttachmentServiceServer
+
+	attachmentService attachmentService
+}
+
+func NewHandler(attachmentService attachmentService) *Handler {
+	return &Handler{attachmentService: attachmentService}
+}
+
+// Register кроме gateway подключает HTTP-эндпоинты загрузки и скачивания. Они
+// работают через gRPC-клиент, поэтому проходят те же перехватчики, что и gRPC-вызовы.
+func Register(ctx context.Context, mux *runtime.ServeMux, conn *grpc.ClientConn, gRPCServer *grpc.Server, handler *Handler) error {
+	post.RegisterAttachmentServiceServer(gRPCServer, handler)
+	err := post.RegisterAttachmentServiceHandler(ctx, mux, conn)
+	if err != nil {
+		return err
+	}
+
+	client := post.NewAttachmentServiceClient(conn)
+
+	err = mux.HandlePath(http.MethodPost, uploadPath, uploadHTTP(mux, client))
+	if err != nil {
+		return err
+	}
+	err = mux.HandlePath(http.MethodGet, contentPath, downloadHTTP(mux, client, contentPath))
+	if err != nil {
+		return err
+	}
+	return mux.HandlePath(http.MethodGet, variantPath, downloadHTTP(mux, client, variantPath))
+}
+
+// viewer возвращает читателя для публичных методов: пустую Identity, если запрос анонимный.
+func viewer(ctx context.Context) auth.Identity {
+	identity, _ := auth.IdentityFromContext(ctx)
+	return identity
+}
+
+func toProto(a model.Attachment) *post.Attachment {
+	attachment := &post.Attachment{
+		Id:         a.ID,
+		PostId:     a.PostID,
+		Filename:   a.Filename,
+		Size:       a.Size,
+		MimeType:   a.MimeType,
+		Sha256:     a.SHA256,
+		UploadedBy: a.UploadedBy.String(),
+		CreatedAt:  timestamppb.New(a.CreatedAt),
+		Url:        "/v1/attachments/" + strconv.FormatInt(a.ID, 10) + "/content",
+		ImageState: a.ImageState,
+		Width:      a.Width,
+		Height:     a.Height,
+		Blurhash:   a.Blurhash,
+		Variants:   make([]*post.AttachmentVariant, 0, len(a.Variants)),
+	}
+	for _, v := range a.Variants {
+		attachment.Variants = append(attachment.Variants, variantToProto(a.ID, v))
+	}
+
+	return attachment
+}
+
+// variantToProto в путь добавляется хеш содержимого, поэтому по одному пути
+// всегда отдаётся одно и то же и ответ можно кэшировать надолго.
+func variantToProto(attachmentID int64, v model.AttachmentVariant) *post.AttachmentVariant {
+	return &post.AttachmentVariant{
+		Name:     v.Name,
+		Width:    v.Width,
+		Height:   v.Height,
+		Size:     v.Size,
+		MimeType: v.MimeType,
+		Sha256:   v.SHA256,
+		Url:      "/v1/attachments/" + strconv.FormatInt(attachmentID, 10) + "/variants/" + v.Name + "?" + versionParam + "=" + v.SHA256,
+	}
+}
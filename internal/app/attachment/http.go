@@ -0,0 +1,191 @@
+package attachment
+
+import (
+	"errors"
+	"github.com/AdilBaidual/baseProject/internal/pb/baseProject/post"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+)
+
+const (
+	uploadPath  = "/v1/posts/{post_id}/attachments"
+	contentPath = "/v1/attachments/{id}/content"
+	variantPath = "/v1/attachments/{id}/variants/{name}"
+
+	// versionParam параметр пути варианта с хешем его содержимого.
+	versionParam = "v"
+
+	// uploadField поле multipart-формы с файлом.
+	uploadField = "file"
+)
+
+// uploadHTTP принимает multipart/form-data и передаёт файл в Upload частями,
+// не держа его в памяти целиком. Поля формы до файла пропускаются.
+func uploadHTTP(mux *runtime.ServeMux, client post.AttachmentServiceClient) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+		_, outbound := runtime.MarshalerForRequest(mux, r)
+
+		ctx, err := runtime.AnnotateContext(r.Context(), mux, r, post.AttachmentService_Upload_FullMethodName, runtime.WithHTTPPathPattern(uploadPath))
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outbound, w, r, err)
+			return
+		}
+
+		postID, err := strconv.ParseInt(pathParams["post_id"], 10, 64)
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outbound, w, r, status.Error(codes.InvalidArgument, "invalid post_id"))
+			return
+		}
+
+		form, err := r.MultipartReader()
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outbound, w, r, status.Error(codes.InvalidArgument, "multipart/form-data body expected"))
+			return
+		}
+
+		var part io.Reader
+		var filename string
+		for part == nil {
+			p, err := form.NextPart()
+			if err != nil {
+				runtime.HTTPError(ctx, mux, outbound, w, r, status.Errorf(codes.InvalidArgument, "form field %q is required", uploadField))
+				return
+			}
+			if p.FormName() == uploadField {
+				part, filename = p, p.FileName()
+			}
+		}
+
+		stream, err := client.Upload(ctx)
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outbound, w, r, err)
+			return
+		}
+
+		err = stream.Send(&post.UploadAttachmentRequest{Data: &post.UploadAttachmentRequest_Metadata{
+			Metadata: &post.UploadAttachmentMetadata{PostId: postID, Filename: filename},
+		}})
+
+		// io.EOF от Send значит, что сервер уже завершил вызов: причину вернёт CloseAndRecv.
+		buf := make([]byte, chunkSize)
+		for err == nil {
+			n, readErr := io.ReadFull(part, buf)
+			if n > 0 {
+				err = stream.Send(&post.UploadAttachmentRequest{Data: &post.UploadAttachmentRequest_Chunk{Chunk: buf[:n]}})
+			}
+			if errors.Is(readErr, io.EOF) || errors.Is(readErr, io.ErrUnexpectedEOF) {
+				break
+			}
+			if readErr != nil {
+				runtime.HTTPError(ctx, mux, outbound, w, r, status.Error(codes.InvalidArgument, "error reading request body"))
+				return
+			}
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			runtime.HTTPError(ctx, mux, outbound, w, r, err)
+			return
+		}
+
+		resp, err := stream.CloseAndRecv()
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outbound, w, r, err)
+			return
+		}
+
+		var md runtime.ServerMetadata
+		md.HeaderMD, _ = stream.Header()
+		md.TrailerMD = stream.Trailer()
+		ctx = runtime.NewServerMetadataContext(ctx, md)
+
+		runtime.ForwardResponseMessage(ctx, mux, outbound, w, r, resp)
+	}
+}
+
+// downloadHTTP отдаёт содержимое вложения или варианта изображения с типом,
+// определённым при загрузке или обработке. Браузеру запрещено угадывать тип и
+// исполнять содержимое.
+func downloadHTTP(mux *runtime.ServeMux, client post.AttachmentServiceClient, pattern string) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+		_, outbound := runtime.MarshalerForRequest(mux, r)
+
+		ctx, err := runtime.AnnotateContext(r.Context(), mux, r, post.AttachmentService_Download_FullMethodName, runtime.WithHTTPPathPattern(pattern))
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outbound, w, r, err)
+			return
+		}
+
+		id, err := strconv.ParseInt(pathParams["id"], 10, 64)
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outbound, w, r, status.Error(codes.InvalidArgument, "invalid id"))
+			return
+		}
+
+		stream, err := client.Download(ctx, &post.DownloadAttachmentRequest{Id: id, Variant: pathParams["name"]})
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outbound, w, r, err)
+			return
+		}
+
+		first, err := stream.Recv()
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outbound, w, r, err)
+			return
+		}
+
+		var sha256, mimeType, filename string
+		var size int64
+		switch {
+		case first.GetAttachment() != nil:
+			attachment := first.GetAttachment()
+			sha256, mimeType, size, filename = attachment.GetSha256(), attachment.GetMimeType(), attachment.GetSize(), attachment.GetFilename()
+		case first.GetVariant() != nil:
+			variant := first.GetVariant()
+			sha256, mimeType, size = variant.GetSha256(), variant.GetMimeType(), variant.GetSize()
+		default:
+			runtime.HTTPError(ctx, mux, outbound, w, r, status.Error(codes.Internal, "unexpected response"))
+			return
+		}
+
+		// Пост может быть черновиком, поэтому общим кэшам содержимое не
+		// доверяется. Путь с хешем содержимого неизменен и кэшируется на год.
+		cacheControl := "private, max-age=86400"
+		if v := r.URL.Query().Get(versionParam); v != "" && v == sha256 {
+			cacheControl = "private, max-age=31536000, immutable"
+		}
+
+		etag := `"` + sha256 + `"`
+		header := w.Header()
+		header.Set("ETag", etag)
+		header.Set("Cache-Control", cacheControl)
+		header.Set("X-Content-Type-Options", "nosniff")
+		header.Set("Content-Security-Policy", "default-src 'none'; sandbox")
+
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		header.Set("Content-Type", mimeType)
+		header.Set("Content-Length", strconv.FormatInt(size, 10))
+		if filename != "" {
+			header.Set("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": filename}))
+		}
+		w.WriteHeader(http.StatusOK)
+
+		// После заголовков об ошибке можно сообщить только оборвав ответ.
+		for {
+			msg, err := stream.Recv()
+			if err != nil {
+				return
+			}
+			if _, err = w.Write(msg.GetChunk()); err != nil {
+				return
+			}
+		}
+	}
+}
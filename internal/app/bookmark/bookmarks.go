@@ -0,0 +1,112 @@
+package bookmark
+
+import (
+	"context"
+	"github.com/AdilBaidual/baseProject/internal/app/grpcerr"
+	"github.com/AdilBaidual/baseProject/internal/auth"
+	"github.com/AdilBaidual/baseProject/internal/pb/baseProject/post"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func (h *Handler) AddBookmark(ctx context.Context, req *post.AddBookmarkRequest) (*post.Bookmark, error) {
+	identity, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	bookmark, err := h.bookmarkService.Add(ctx, identity.UserUUID, req.GetPostId(), req.GetCollectionId())
+	if err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	return toProto(bookmark), nil
+}
+
+func (h *Handler) MoveBookmark(ctx context.Context, req *post.MoveBookmarkRequest) (*post.Bookmark, error) {
+	identity, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	bookmark, err := h.bookmarkService.Move(ctx, identity.UserUUID, req.GetPostId(), req.GetCollectionId())
+	if err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	return toProto(bookmark), nil
+}
+
+func (h *Handler) RemoveBookmark(ctx context.Context, req *post.RemoveBookmarkRequest) (*emptypb.Empty, error) {
+	identity, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	if err := h.bookmarkService.Remove(ctx, identity.UserUUID, req.GetPostId()); err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (h *Handler) CreateBookmarkCollection(ctx context.Context, req *post.CreateBookmarkCollectionRequest) (*post.BookmarkCollection, error) {
+	identity, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	collection, err := h.bookmarkService.CreateCollection(ctx, identity.UserUUID, req.GetName())
+	if err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	return collectionToProto(collection), nil
+}
+
+func (h *Handler) RenameBookmarkCollection(ctx context.Context, req *post.RenameBookmarkCollectionRequest) (*post.BookmarkCollection, error) {
+	identity, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	collection, err := h.bookmarkService.RenameCollection(ctx, identity.UserUUID, req.GetId(), req.GetName())
+	if err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	return collectionToProto(collection), nil
+}
+
+func (h *Handler) DeleteBookmarkCollection(ctx context.Context, req *post.DeleteBookmarkCollectionRequest) (*emptypb.Empty, error) {
+	identity, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	if err := h.bookmarkService.DeleteCollection(ctx, identity.UserUUID, req.GetId()); err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (h *Handler) ListBookmarkCollections(ctx context.Context, _ *emptypb.Empty) (*post.ListBookmarkCollectionsResponse, error) {
+	identity, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	collections, err := h.bookmarkService.ListCollections(ctx, identity.UserUUID)
+	if err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	resp := &post.ListBookmarkCollectionsResponse{Collections: make([]*post.BookmarkCollection, 0, len(collections))}
+	for _, c := range collections {
+		resp.Collections = append(resp.Collections, collectionToProto(c))
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,57 @@
+package bookmark
+
+import (
+	"context"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/AdilBaidual/baseProject/internal/pb/baseProject/post"
+	"github.com/google/uuid"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type bookmarkService interface {
+	Add(ctx context.Context, userUUID uuid.UUID, postID, collectionID int64) (model.Bookmark, error)
+	Remove(ctx context.Context, userUUID uuid.UUID, postID int64) error
+	Move(ctx context.Context, userUUID uuid.UUID, postID, collectionID int64) (model.Bookmark, error)
+	CreateCollection(ctx context.Context, userUUID uuid.UUID, name string) (model.BookmarkCollection, error)
+	RenameCollection(ctx context.Context, userUUID uuid.UUID, id int64, name string) (model.BookmarkCollection, error)
+	DeleteCollection(ctx context.Context, userUUID uuid.UUID, id int64) error
+	ListCollections(ctx context.Context, userUUID uuid.UUID) ([]model.BookmarkCollection, error)
+}
+
+type Handler struct {
+	post.BookmarkServiceServer
+
+	bookmarkService bookmarkService
+}
+
+func NewHandler(bookmarkService bookmarkService) *Handler {
+	return &Handler{bookmarkService: bookmarkService}
+}
+
+func Register(ctx context.Context, mux *runtime.ServeMux, conn *grpc.ClientConn, gRPCServer *grpc.Server, handler *Handler) error {
+	post.RegisterBookmarkServiceServer(gRPCServer, handler)
+	err := post.RegisterBookmarkServiceHandler(ctx, mux, conn)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func toProto(b model.Bookmark) *post.Bookmark {
+	return &post.Bookmark{
+		PostId:       b.PostID,
+		CollectionId: b.CollectionID,
+		CreatedAt:    timestamppb.New(b.CreatedAt),
+	}
+}
+
+func collectionToProto(c model.BookmarkCollection) *post.BookmarkCollection {
+	return &post.BookmarkCollection{
+		Id:             c.ID,
+		Name:           c.Name,
+		BookmarksCount: c.BookmarksCount,
+		CreatedAt:      timestamppb.New(c.CreatedAt),
+	}
+}
@@ -0,0 +1,72 @@
+package feed
+
+import (
+	"bytes"
+	"context"
+	"github.com/AdilBaidual/baseProject/internal/app/grpcerr"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/google/uuid"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/status"
+	"net/http"
+)
+
+// cacheControl ленты публичны. Читалки перезапрашивают их не чаще раза в
+// пять минут, а дальше ревалидируют по ETag или If-Modified-Since.
+const cacheControl = "public, max-age=300"
+
+type feedService interface {
+	Feed(ctx context.Context, scope model.FeedScope, format string) (model.FeedDocument, error)
+}
+
+// Handler HTTP-эндпоинты лент RSS и Atom. Это не gRPC-методы: ответ — XML,
+// а ревалидацию выполняет net/http.
+type Handler struct {
+	feedService feedService
+}
+
+func NewHandler(feedService feedService) *Handler {
+	return &Handler{feedService: feedService}
+}
+
+// Register подключает ленты всех постов, автора и тега. {format} — rss или atom.
+func Register(mux *http.ServeMux, handler *Handler) {
+	mux.HandleFunc("GET /v1/feeds/{format}", handler.site)
+	mux.HandleFunc("GET /v1/feeds/authors/{author_uuid}/{format}", handler.author)
+	mux.HandleFunc("GET /v1/feeds/tags/{tag}/{format}", handler.tag)
+}
+
+func (h *Handler) site(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, model.FeedScope{})
+}
+
+func (h *Handler) author(w http.ResponseWriter, r *http.Request) {
+	authorUUID, err := uuid.Parse(r.PathValue("author_uuid"))
+	if err != nil {
+		http.Error(w, "invalid author_uuid", http.StatusBadRequest)
+		return
+	}
+
+	h.serve(w, r, model.FeedScope{AuthorUUID: authorUUID})
+}
+
+func (h *Handler) tag(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, model.FeedScope{TagSlug: r.PathValue("tag")})
+}
+
+func (h *Handler) serve(w http.ResponseWriter, r *http.Request, scope model.FeedScope) {
+	doc, err := h.feedService.Feed(r.Context(), scope, r.PathValue("format"))
+	if err != nil {
+		st := status.Convert(grpcerr.ToStatus(r.Context(), err))
+		http.Error(w, st.Message(), runtime.HTTPStatusFromCode(st.Code()))
+		return
+	}
+
+	header := w.Header()
+	header.Set("Content-Type", doc.ContentType)
+	header.Set("ETag", doc.ETag)
+	header.Set("Cache-Control", cacheControl)
+
+	// ServeContent отвечает 304 по If-None-Match и If-Modified-Since.
+	http.ServeContent(w, r, "", doc.ModifiedAt, bytes.NewReader(doc.Body))
+}
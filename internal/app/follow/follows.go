@@ -0,0 +1,77 @@
+package follow
+
+import (
+	"context"
+	"github.com/AdilBaidual/baseProject/internal/app/grpcerr"
+	"github.com/AdilBaidual/baseProject/internal/auth"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/AdilBaidual/baseProject/internal/pb/baseProject/user"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func (h *Handler) Follow(ctx context.Context, req *user.FollowRequest) (*emptypb.Empty, error) {
+	identity, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	followeeUUID, err := uuid.Parse(req.GetUserUuid())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user uuid")
+	}
+
+	if err = h.followService.Follow(ctx, identity.UserUUID, followeeUUID); err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (h *Handler) Unfollow(ctx context.Context, req *user.UnfollowRequest) (*emptypb.Empty, error) {
+	identity, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	followeeUUID, err := uuid.Parse(req.GetUserUuid())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user uuid")
+	}
+
+	if err = h.followService.Unfollow(ctx, identity.UserUUID, followeeUUID); err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (h *Handler) ListFollowers(ctx context.Context, req *user.ListFollowsRequest) (*user.ListFollowsResponse, error) {
+	userUUID, err := uuid.Parse(req.GetUserUuid())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user uuid")
+	}
+
+	follows, next, err := h.followService.ListFollowers(ctx, userUUID, req.GetPageSize(), req.GetPageToken())
+	if err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	return followsToProto(follows, func(f model.Follow) uuid.UUID { return f.FollowerUUID }, next), nil
+}
+
+func (h *Handler) ListFollowing(ctx context.Context, req *user.ListFollowsRequest) (*user.ListFollowsResponse, error) {
+	userUUID, err := uuid.Parse(req.GetUserUuid())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user uuid")
+	}
+
+	follows, next, err := h.followService.ListFollowing(ctx, userUUID, req.GetPageSize(), req.GetPageToken())
+	if err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	return followsToProto(follows, func(f model.Follow) uuid.UUID { return f.FolloweeUUID }, next), nil
+}
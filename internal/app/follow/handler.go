@@ -0,0 +1,52 @@
+package follow
+
+import (
+	"context"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/AdilBaidual/baseProject/internal/pb/baseProject/user"
+	"github.com/google/uuid"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type followService interface {
+	Follow(ctx context.Context, followerUUID, followeeUUID uuid.UUID) error
+	Unfollow(ctx context.Context, followerUUID, followeeUUID uuid.UUID) error
+	ListFollowers(ctx context.Context, userUUID uuid.UUID, pageSize int32, pageToken string) ([]model.Follow, string, error)
+	ListFollowing(ctx context.Context, userUUID uuid.UUID, pageSize int32, pageToken string) ([]model.Follow, string, error)
+}
+
+type Handler struct {
+	user.FollowServiceServer
+
+	followService followService
+}
+
+func NewHandler(followService followService) *Handler {
+	return &Handler{followService: followService}
+}
+
+func Register(ctx context.Context, mux *runtime.ServeMux, conn *grpc.ClientConn, gRPCServer *grpc.Server, handler *Handler) error {
+	user.RegisterFollowServiceServer(gRPCServer, handler)
+	err := user.RegisterFollowServiceHandler(ctx, mux, conn)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func followsToProto(follows []model.Follow, other func(model.Follow) uuid.UUID, next string) *user.ListFollowsResponse {
+	resp := &user.ListFollowsResponse{
+		Follows:       make([]*user.FollowEntry, 0, len(follows)),
+		NextPageToken: next,
+	}
+	for _, f := range follows {
+		resp.Follows = append(resp.Follows, &user.FollowEntry{
+			UserUuid:  other(f).String(),
+			CreatedAt: timestamppb.New(f.CreatedAt),
+		})
+	}
+
+	return resp
+}
@@ -0,0 +1,44 @@
+package grpcerr
+
+import (
+	"context"
+	"errors"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ToStatus переводит доменные ошибки в gRPC-статусы.
+func ToStatus(ctx context.Context, err error) error {
+	switch {
+	case errors.Is(err, model.ErrInvalidArgument):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, model.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, model.ErrAlreadyExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, model.ErrConflict):
+		return status.Error(codes.Aborted, err.Error())
+	case errors.Is(err, model.ErrFailedPrecondition):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, model.ErrInvalidCredentials):
+		return status.Error(codes.Unauthenticated, "invalid email or password")
+	case errors.Is(err, model.ErrTooManyAttempts):
+		return status.Error(codes.ResourceExhausted, "too many failed attempts, try again later")
+	case errors.Is(err, model.ErrUnauthenticated):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, model.ErrPermissionDenied):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	}
+
+	if logger, ok := ctx.Value("logger").(*zap.Logger); ok {
+		logger.Error("internal error", zap.Error(err))
+	}
+
+	return status.Error(codes.Internal, "internal error")
+}
@@ -0,0 +1,110 @@
+package moderation
+
+import (
+	"context"
+	"github.com/AdilBaidual/baseProject/internal/auth"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/AdilBaidual/baseProject/internal/pb/baseProject/post"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type moderationService interface {
+	Report(ctx context.Context, reporter auth.Identity, target model.ModerationTarget, reason string) error
+	ListQueue(ctx context.Context, targetType string, pageSize int32, pageToken string) ([]model.ModerationItem, string, error)
+	Moderate(ctx context.Context, moderator auth.Identity, target model.ModerationTarget, action, note string) (model.ModerationLogEntry, error)
+	ListLog(ctx context.Context, filter model.ModerationLogFilter, pageSize int32, pageToken string) ([]model.ModerationLogEntry, string, error)
+}
+
+type Handler struct {
+	post.ModerationServiceServer
+
+	moderationService moderationService
+}
+
+func NewHandler(moderationService moderationService) *Handler {
+	return &Handler{moderationService: moderationService}
+}
+
+func Register(ctx context.Context, mux *runtime.ServeMux, conn *grpc.ClientConn, gRPCServer *grpc.Server, handler *Handler) error {
+	post.RegisterModerationServiceServer(gRPCServer, handler)
+	err := post.RegisterModerationServiceHandler(ctx, mux, conn)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+var targetTypeToProto = map[string]post.TargetType{
+	model.ReactionTargetPost:    post.TargetType_TARGET_TYPE_POST,
+	model.ReactionTargetComment: post.TargetType_TARGET_TYPE_COMMENT,
+}
+
+var reasonToProto = map[string]post.ReportReason{
+	model.ReportReasonSpam:           post.ReportReason_REPORT_REASON_SPAM,
+	model.ReportReasonHarassment:     post.ReportReason_REPORT_REASON_HARASSMENT,
+	model.ReportReasonHateSpeech:     post.ReportReason_REPORT_REASON_HATE_SPEECH,
+	model.ReportReasonViolence:       post.ReportReason_REPORT_REASON_VIOLENCE,
+	model.ReportReasonSexualContent:  post.ReportReason_REPORT_REASON_SEXUAL_CONTENT,
+	model.ReportReasonMisinformation: post.ReportReason_REPORT_REASON_MISINFORMATION,
+	model.ReportReasonOther:          post.ReportReason_REPORT_REASON_OTHER,
+}
+
+var actionToProto = map[string]post.ModerationAction{
+	model.ModerationActionHide:    post.ModerationAction_MODERATION_ACTION_HIDE,
+	model.ModerationActionRestore: post.ModerationAction_MODERATION_ACTION_RESTORE,
+	model.ModerationActionDelete:  post.ModerationAction_MODERATION_ACTION_DELETE,
+	model.ModerationActionWarn:    post.ModerationAction_MODERATION_ACTION_WARN,
+	model.ModerationActionDismiss: post.ModerationAction_MODERATION_ACTION_DISMISS,
+}
+
+// fromProto обратное отображение для перечислений из запросов. Пустая строка —
+// значение не задано или неизвестно.
+func fromProto[E comparable](m map[string]E, value E) string {
+	for s, p := range m {
+		if p == value {
+			return s
+		}
+	}
+	return ""
+}
+
+func toTarget(targetType post.TargetType, id int64) (model.ModerationTarget, error) {
+	t := fromProto(targetTypeToProto, targetType)
+	if t == "" {
+		return model.ModerationTarget{}, status.Error(codes.InvalidArgument, "target_type is required")
+	}
+	return model.ModerationTarget{Type: t, ID: id}, nil
+}
+
+func itemToProto(item model.ModerationItem) *post.ModerationItem {
+	return &post.ModerationItem{
+		TargetType:      targetTypeToProto[item.Target.Type],
+		TargetId:        item.Target.ID,
+		AuthorUuid:      item.AuthorUUID.String(),
+		Title:           item.Title,
+		Content:         item.Content,
+		Hidden:          item.HiddenAt != nil,
+		Reports:         item.Reports,
+		Reasons:         item.Reasons,
+		FirstReportedAt: timestamppb.New(item.FirstReportedAt),
+		LastReportedAt:  timestamppb.New(item.LastReportedAt),
+	}
+}
+
+func logEntryToProto(entry model.ModerationLogEntry) *post.ModerationLogEntry {
+	return &post.ModerationLogEntry{
+		Id:              entry.ID,
+		TargetType:      targetTypeToProto[entry.Target.Type],
+		TargetId:        entry.Target.ID,
+		Action:          actionToProto[entry.Action],
+		ModeratorUuid:   entry.ModeratorUUID.String(),
+		AuthorUuid:      entry.AuthorUUID.String(),
+		Note:            entry.Note,
+		ReportsResolved: entry.ReportsResolved,
+		CreatedAt:       timestamppb.New(entry.CreatedAt),
+	}
+}
@@ -0,0 +1,112 @@
+package moderation
+
+import (
+	"context"
+	"github.com/AdilBaidual/baseProject/internal/app/grpcerr"
+	"github.com/AdilBaidual/baseProject/internal/auth"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/AdilBaidual/baseProject/internal/pb/baseProject/post"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func (h *Handler) ReportContent(ctx context.Context, req *post.ReportContentRequest) (*emptypb.Empty, error) {
+	identity, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	target, err := toTarget(req.GetTargetType(), req.GetTargetId())
+	if err != nil {
+		return nil, err
+	}
+
+	reason := fromProto(reasonToProto, req.GetReason())
+	if reason == "" {
+		return nil, status.Error(codes.InvalidArgument, "reason is required")
+	}
+
+	if err = h.moderationService.Report(ctx, identity, target, reason); err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (h *Handler) ListModerationQueue(ctx context.Context, req *post.ListModerationQueueRequest) (*post.ListModerationQueueResponse, error) {
+	items, next, err := h.moderationService.ListQueue(ctx, fromProto(targetTypeToProto, req.GetTargetType()), req.GetPageSize(), req.GetPageToken())
+	if err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	resp := &post.ListModerationQueueResponse{
+		Items:         make([]*post.ModerationItem, 0, len(items)),
+		NextPageToken: next,
+	}
+	for _, item := range items {
+		resp.Items = append(resp.Items, itemToProto(item))
+	}
+
+	return resp, nil
+}
+
+func (h *Handler) Moderate(ctx context.Context, req *post.ModerateRequest) (*post.ModerationLogEntry, error) {
+	identity, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	target, err := toTarget(req.GetTargetType(), req.GetTargetId())
+	if err != nil {
+		return nil, err
+	}
+
+	action := fromProto(actionToProto, req.GetAction())
+	if action == "" {
+		return nil, status.Error(codes.InvalidArgument, "action is required")
+	}
+
+	entry, err := h.moderationService.Moderate(ctx, identity, target, action, req.GetNote())
+	if err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	return logEntryToProto(entry), nil
+}
+
+func (h *Handler) ListModerationLog(ctx context.Context, req *post.ListModerationLogRequest) (*post.ListModerationLogResponse, error) {
+	var filter model.ModerationLogFilter
+
+	if req.GetTargetType() != post.TargetType_TARGET_TYPE_UNSPECIFIED {
+		target, err := toTarget(req.GetTargetType(), req.GetTargetId())
+		if err != nil {
+			return nil, err
+		}
+		filter.Target = target
+	}
+
+	if req.GetAuthorUuid() != "" {
+		authorUUID, err := uuid.Parse(req.GetAuthorUuid())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid author uuid")
+		}
+		filter.AuthorUUID = authorUUID
+	}
+
+	entries, next, err := h.moderationService.ListLog(ctx, filter, req.GetPageSize(), req.GetPageToken())
+	if err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	resp := &post.ListModerationLogResponse{
+		Entries:       make([]*post.ModerationLogEntry, 0, len(entries)),
+		NextPageToken: next,
+	}
+	for _, entry := range entries {
+		resp.Entries = append(resp.Entries, logEntryToProto(entry))
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,79 @@
+package notification
+
+import (
+	"context"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/AdilBaidual/baseProject/internal/pb/baseProject/user"
+	"github.com/google/uuid"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type notificationService interface {
+	List(ctx context.Context, userUUID uuid.UUID, unreadOnly bool, pageSize int32, pageToken string) ([]model.Notification, int64, string, error)
+	MarkRead(ctx context.Context, userUUID uuid.UUID, ids []int64, all bool) (int64, error)
+	Watch(ctx context.Context, userUUID uuid.UUID, afterID int64, check func(context.Context) error, send func(model.Notification) error) error
+	Muted(ctx context.Context, userUUID uuid.UUID) ([]string, error)
+	SetMuted(ctx context.Context, userUUID uuid.UUID, types []string) ([]string, error)
+}
+
+// sessionChecker повторно проверяет учётные данные открытого потока.
+type sessionChecker interface {
+	Reauthenticate(ctx context.Context) error
+}
+
+type Handler struct {
+	user.NotificationServiceServer
+
+	notificationService notificationService
+	sessions            sessionChecker
+}
+
+func NewHandler(notificationService notificationService, sessions sessionChecker) *Handler {
+	return &Handler{
+		notificationService: notificationService,
+		sessions:            sessions,
+	}
+}
+
+func Register(ctx context.Context, mux *runtime.ServeMux, conn *grpc.ClientConn, gRPCServer *grpc.Server, handler *Handler) error {
+	user.RegisterNotificationServiceServer(gRPCServer, handler)
+	err := user.RegisterNotificationServiceHandler(ctx, mux, conn)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+var typeToProto = map[string]user.NotificationType{
+	model.NotificationReply:   user.NotificationType_NOTIFICATION_TYPE_REPLY,
+	model.NotificationComment: user.NotificationType_NOTIFICATION_TYPE_COMMENT,
+	model.NotificationMention: user.NotificationType_NOTIFICATION_TYPE_MENTION,
+	model.NotificationFollow:  user.NotificationType_NOTIFICATION_TYPE_FOLLOW,
+}
+
+func toProto(n model.Notification) *user.Notification {
+	resp := &user.Notification{
+		Id:        n.ID,
+		Type:      typeToProto[n.Type],
+		ActorUuid: n.ActorUUID.String(),
+		PostId:    n.PostID,
+		CommentId: n.CommentID,
+		CreatedAt: timestamppb.New(n.CreatedAt),
+	}
+	if n.ReadAt != nil {
+		resp.ReadAt = timestamppb.New(*n.ReadAt)
+	}
+
+	return resp
+}
+
+func preferencesToProto(muted []string) *user.NotificationPreferences {
+	resp := &user.NotificationPreferences{Muted: make([]user.NotificationType, 0, len(muted))}
+	for _, t := range muted {
+		resp.Muted = append(resp.Muted, typeToProto[t])
+	}
+
+	return resp
+}
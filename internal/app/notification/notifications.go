@@ -0,0 +1,114 @@
+package notification
+
+import (
+	"context"
+	"github.com/AdilBaidual/baseProject/internal/app/grpcerr"
+	"github.com/AdilBaidual/baseProject/internal/auth"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/AdilBaidual/baseProject/internal/pb/baseProject/user"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func (h *Handler) ListNotifications(ctx context.Context, req *user.ListNotificationsRequest) (*user.ListNotificationsResponse, error) {
+	identity, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	notifications, unread, next, err := h.notificationService.List(ctx, identity.UserUUID, req.GetUnreadOnly(), req.GetPageSize(), req.GetPageToken())
+	if err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	resp := &user.ListNotificationsResponse{
+		Notifications: make([]*user.Notification, 0, len(notifications)),
+		NextPageToken: next,
+		UnreadCount:   unread,
+	}
+	for _, n := range notifications {
+		resp.Notifications = append(resp.Notifications, toProto(n))
+	}
+
+	return resp, nil
+}
+
+func (h *Handler) MarkRead(ctx context.Context, req *user.MarkReadRequest) (*user.MarkReadResponse, error) {
+	identity, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	unread, err := h.notificationService.MarkRead(ctx, identity.UserUUID, req.GetIds(), req.GetAll())
+	if err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	return &user.MarkReadResponse{UnreadCount: unread}, nil
+}
+
+func (h *Handler) WatchNotifications(req *user.WatchNotificationsRequest, stream grpc.ServerStreamingServer[user.Notification]) error {
+	ctx := stream.Context()
+
+	identity, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	err := h.notificationService.Watch(ctx, identity.UserUUID, req.GetAfterId(), h.sessions.Reauthenticate, func(n model.Notification) error {
+		return stream.Send(toProto(n))
+	})
+	if err != nil {
+		// Reauthenticate возвращает готовый статус, например Unauthenticated.
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+		return grpcerr.ToStatus(ctx, err)
+	}
+
+	return nil
+}
+
+func (h *Handler) GetNotificationPreferences(ctx context.Context, _ *emptypb.Empty) (*user.NotificationPreferences, error) {
+	identity, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	muted, err := h.notificationService.Muted(ctx, identity.UserUUID)
+	if err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	return preferencesToProto(muted), nil
+}
+
+func (h *Handler) UpdateNotificationPreferences(ctx context.Context, req *user.NotificationPreferences) (*user.NotificationPreferences, error) {
+	identity, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	types := make([]string, 0, len(req.GetMuted()))
+	for _, m := range req.GetMuted() {
+		var t string
+		for name, p := range typeToProto {
+			if p == m {
+				t = name
+			}
+		}
+		if t == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown notification type %s", m)
+		}
+		types = append(types, t)
+	}
+
+	muted, err := h.notificationService.SetMuted(ctx, identity.UserUUID, types)
+	if err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	return preferencesToProto(muted), nil
+}
@@ -0,0 +1,81 @@
+package policy
+
+import (
+	"context"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/AdilBaidual/baseProject/internal/pb/baseProject/post"
+	"github.com/google/uuid"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type policyService interface {
+	ListRules(ctx context.Context) ([]model.ContentRule, error)
+	CreateRule(ctx context.Context, actorUUID uuid.UUID, rule model.ContentRule) (model.ContentRule, error)
+	UpdateRule(ctx context.Context, rule model.ContentRule) (model.ContentRule, error)
+	DeleteRule(ctx context.Context, id int64) error
+}
+
+type Handler struct {
+	post.ContentPolicyServiceServer
+
+	policyService policyService
+}
+
+func NewHandler(policyService policyService) *Handler {
+	return &Handler{policyService: policyService}
+}
+
+func Register(ctx context.Context, mux *runtime.ServeMux, conn *grpc.ClientConn, gRPCServer *grpc.Server, handler *Handler) error {
+	post.RegisterContentPolicyServiceServer(gRPCServer, handler)
+	err := post.RegisterContentPolicyServiceHandler(ctx, mux, conn)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+var actionToProto = map[string]post.PolicyAction{
+	model.PolicyActionAllow:  post.PolicyAction_POLICY_ACTION_ALLOW,
+	model.PolicyActionHold:   post.PolicyAction_POLICY_ACTION_HOLD,
+	model.PolicyActionReject: post.PolicyAction_POLICY_ACTION_REJECT,
+}
+
+func fromInput(input *post.ContentRuleInput) (model.ContentRule, error) {
+	var action string
+	for a, p := range actionToProto {
+		if p == input.GetAction() {
+			action = a
+		}
+	}
+	if action == "" {
+		return model.ContentRule{}, status.Error(codes.InvalidArgument, "action is required")
+	}
+
+	return model.ContentRule{
+		Name:        input.GetName(),
+		Description: input.GetDescription(),
+		Expression:  input.GetExpression(),
+		Action:      action,
+		Priority:    input.GetPriority(),
+		Enabled:     input.GetEnabled(),
+	}, nil
+}
+
+func toProto(rule model.ContentRule) *post.ContentRule {
+	return &post.ContentRule{
+		Id:          rule.ID,
+		Name:        rule.Name,
+		Description: rule.Description,
+		Expression:  rule.Expression,
+		Action:      actionToProto[rule.Action],
+		Priority:    rule.Priority,
+		Enabled:     rule.Enabled,
+		CreatedBy:   rule.CreatedBy.String(),
+		CreatedAt:   timestamppb.New(rule.CreatedAt),
+		UpdatedAt:   timestamppb.New(rule.UpdatedAt),
+	}
+}
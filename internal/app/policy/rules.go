@@ -0,0 +1,67 @@
+package policy
+
+import (
+	"context"
+	"github.com/AdilBaidual/baseProject/internal/app/grpcerr"
+	"github.com/AdilBaidual/baseProject/internal/auth"
+	"github.com/AdilBaidual/baseProject/internal/pb/baseProject/post"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func (h *Handler) ListContentRules(ctx context.Context, _ *emptypb.Empty) (*post.ListContentRulesResponse, error) {
+	rules, err := h.policyService.ListRules(ctx)
+	if err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	resp := &post.ListContentRulesResponse{Rules: make([]*post.ContentRule, 0, len(rules))}
+	for _, rule := range rules {
+		resp.Rules = append(resp.Rules, toProto(rule))
+	}
+
+	return resp, nil
+}
+
+func (h *Handler) CreateContentRule(ctx context.Context, req *post.ContentRuleInput) (*post.ContentRule, error) {
+	identity, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	rule, err := fromInput(req)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := h.policyService.CreateRule(ctx, identity.UserUUID, rule)
+	if err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	return toProto(created), nil
+}
+
+func (h *Handler) UpdateContentRule(ctx context.Context, req *post.UpdateContentRuleRequest) (*post.ContentRule, error) {
+	rule, err := fromInput(req.GetRule())
+	if err != nil {
+		return nil, err
+	}
+	rule.ID = req.GetId()
+
+	updated, err := h.policyService.UpdateRule(ctx, rule)
+	if err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	return toProto(updated), nil
+}
+
+func (h *Handler) DeleteContentRule(ctx context.Context, req *post.DeleteContentRuleRequest) (*emptypb.Empty, error) {
+	if err := h.policyService.DeleteRule(ctx, req.GetId()); err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
@@ -0,0 +1,41 @@
+package post
+
+import (
+	"context"
+	"github.com/AdilBaidual/baseProject/internal/app/grpcerr"
+	"github.com/AdilBaidual/baseProject/internal/auth"
+	"github.com/AdilBaidual/baseProject/internal/pb/baseProject/post"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (h *Handler) CreateComment(ctx context.Context, req *post.CreateCommentRequest) (*post.Comment, error) {
+	identity, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	created, err := h.postService.CreateComment(ctx, identity, req.GetPostId(), req.GetParentId(), req.GetContent())
+	if err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	return commentToProto(created), nil
+}
+
+func (h *Handler) ListComments(ctx context.Context, req *post.ListCommentsRequest) (*post.ListCommentsResponse, error) {
+	comments, next, err := h.postService.ListComments(ctx, viewer(ctx), req.GetPostId(), req.GetParentId(), req.GetPageSize(), req.GetPageToken())
+	if err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	resp := &post.ListCommentsResponse{
+		Comments:      make([]*post.Comment, 0, len(comments)),
+		NextPageToken: next,
+	}
+	for _, c := range comments {
+		resp.Comments = append(resp.Comments, commentToProto(c))
+	}
+
+	return resp, nil
+}
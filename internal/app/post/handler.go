@@ -0,0 +1,150 @@
+package post
+
+import (
+	"context"
+	"github.com/AdilBaidual/baseProject/internal/auth"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/AdilBaidual/baseProject/internal/pb/baseProject/post"
+	"github.com/AdilBaidual/baseProject/internal/service/post_service"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"time"
+)
+
+type postService interface {
+	CreatePost(ctx context.Context, author auth.Identity, title, content string, commentsEnabled bool, tags []string, publication post_service.Publication) (model.Post, error)
+	UpdatePost(ctx context.Context, editor auth.Identity, id int64, version int32, title, content string, commentsEnabled bool, tags []string) (model.Post, error)
+	PublishPost(ctx context.Context, editor auth.Identity, id int64, publishAt, unpublishAt *time.Time) (model.Post, error)
+	UnpublishPost(ctx context.Context, editor auth.Identity, id int64) (model.Post, error)
+	GetPost(ctx context.Context, viewer auth.Identity, id int64) (model.Post, error)
+	ListPosts(ctx context.Context, viewer auth.Identity, filter model.PostFilter, pageSize int32, pageToken string) ([]model.Post, string, error)
+	Timeline(ctx context.Context, viewer auth.Identity, pageSize int32, pageToken string) ([]model.Post, string, error)
+	Bookmarks(ctx context.Context, viewer auth.Identity, collectionID int64, pageSize int32, pageToken string) ([]model.Post, string, error)
+	SearchTags(ctx context.Context, prefix string, limit int32) ([]model.Tag, error)
+	ListPopularTags(ctx context.Context, limit int32) ([]model.Tag, error)
+	CreateComment(ctx context.Context, author auth.Identity, postID, parentID int64, content string) (model.Comment, error)
+	ListComments(ctx context.Context, viewer auth.Identity, postID, parentID int64, pageSize int32, pageToken string) ([]model.Comment, string, error)
+	ListRevisions(ctx context.Context, editor auth.Identity, postID int64, pageSize int32, pageToken string) ([]model.PostRevision, string, error)
+	GetRevision(ctx context.Context, editor auth.Identity, postID int64, version int32) (model.PostRevision, error)
+	DiffRevisions(ctx context.Context, editor auth.Identity, postID int64, from, to int32) (post_service.RevisionDiff, error)
+	RestoreRevision(ctx context.Context, editor auth.Identity, postID int64, version, expectedVersion int32) (model.Post, error)
+	DeletePost(ctx context.Context, editor auth.Identity, id int64) error
+	RestorePost(ctx context.Context, editor auth.Identity, id int64) (model.Post, error)
+	ListDeletedPosts(ctx context.Context, editor auth.Identity, pageSize int32, pageToken string) ([]model.Post, string, error)
+	DeleteComment(ctx context.Context, editor auth.Identity, id int64) error
+	RestoreComment(ctx context.Context, editor auth.Identity, id int64) (model.Comment, error)
+	ListDeletedComments(ctx context.Context, editor auth.Identity, pageSize int32, pageToken string) ([]model.Comment, string, error)
+}
+
+type Handler struct {
+	post.PostServiceServer
+
+	postService postService
+}
+
+func NewHandler(postService postService) *Handler {
+	return &Handler{postService: postService}
+}
+
+func Register(ctx context.Context, mux *runtime.ServeMux, conn *grpc.ClientConn, gRPCServer *grpc.Server, handler *Handler) error {
+	post.RegisterPostServiceServer(gRPCServer, handler)
+	err := post.RegisterPostServiceHandler(ctx, mux, conn)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// viewer возвращает читателя для публичных методов: пустую Identity, если запрос анонимный.
+func viewer(ctx context.Context) auth.Identity {
+	identity, _ := auth.IdentityFromContext(ctx)
+	return identity
+}
+
+func toProto(p model.Post) *post.Post {
+	return &post.Post{
+		Id:              p.ID,
+		Title:           p.Title,
+		Content:         p.Content,
+		ContentHtml:     p.ContentHTML,
+		CommentsEnabled: p.CommentsEnabled,
+		AuthorUuid:      p.AuthorUUID.String(),
+		CreatedAt:       timestamppb.New(p.CreatedAt),
+		Reactions:       reactionsToProto(p.Reactions),
+		Tags:            tagsToProto(p.Tags),
+		Status:          statusToProto[p.Status],
+		PublishAt:       timeToProto(p.PublishAt),
+		UnpublishAt:     timeToProto(p.UnpublishAt),
+		PublishedAt:     timeToProto(p.PublishedAt),
+		Version:         p.Version,
+		Hidden:          p.HiddenAt != nil,
+		Bookmarked:      p.BookmarkedAt != nil,
+	}
+}
+
+var statusToProto = map[string]post.PostStatus{
+	model.PostStatusDraft:     post.PostStatus_POST_STATUS_DRAFT,
+	model.PostStatusScheduled: post.PostStatus_POST_STATUS_SCHEDULED,
+	model.PostStatusPublished: post.PostStatus_POST_STATUS_PUBLISHED,
+	model.PostStatusArchived:  post.PostStatus_POST_STATUS_ARCHIVED,
+}
+
+func statusFromProto(status post.PostStatus) string {
+	for s, p := range statusToProto {
+		if p == status {
+			return s
+		}
+	}
+	return ""
+}
+
+func timeToProto(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+func timeFromProto(t *timestamppb.Timestamp) *time.Time {
+	if t == nil {
+		return nil
+	}
+	v := t.AsTime()
+	return &v
+}
+
+func tagsToProto(tags []model.Tag) []*post.Tag {
+	res := make([]*post.Tag, 0, len(tags))
+	for _, t := range tags {
+		res = append(res, &post.Tag{
+			Slug:       t.Slug,
+			Name:       t.Name,
+			PostsCount: t.PostsCount,
+		})
+	}
+	return res
+}
+
+func commentToProto(c model.Comment) *post.Comment {
+	return &post.Comment{
+		Id:             c.ID,
+		PostId:         c.PostID,
+		ParentId:       c.ParentID,
+		AuthorUuid:     c.AuthorUUID.String(),
+		Content:        c.Content,
+		ContentHtml:    c.ContentHTML,
+		HasSubComments: c.HasSubComments,
+		CreatedAt:      timestamppb.New(c.CreatedAt),
+		Reactions:      reactionsToProto(c.Reactions),
+		Deleted:        c.DeletedAt != nil,
+		Hidden:         c.HiddenAt != nil,
+	}
+}
+
+func reactionsToProto(summary model.ReactionSummary) *post.Reactions {
+	return &post.Reactions{
+		Counts: summary.Counts,
+		Mine:   summary.Mine,
+	}
+}
@@ -0,0 +1,202 @@
+package post
+
+import (
+	"context"
+	"github.com/AdilBaidual/baseProject/internal/app/grpcerr"
+	"github.com/AdilBaidual/baseProject/internal/auth"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/AdilBaidual/baseProject/internal/pb/baseProject/post"
+	"github.com/AdilBaidual/baseProject/internal/service/post_service"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (h *Handler) CreatePost(ctx context.Context, req *post.CreatePostRequest) (*post.Post, error) {
+	identity, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	created, err := h.postService.CreatePost(ctx, identity, req.GetTitle(), req.GetContent(), req.GetCommentsEnabled(), req.GetTags(), post_service.Publication{
+		Draft:       req.GetDraft(),
+		PublishAt:   timeFromProto(req.GetPublishAt()),
+		UnpublishAt: timeFromProto(req.GetUnpublishAt()),
+	})
+	if err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	return toProto(created), nil
+}
+
+func (h *Handler) UpdatePost(ctx context.Context, req *post.UpdatePostRequest) (*post.Post, error) {
+	identity, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	updated, err := h.postService.UpdatePost(ctx, identity, req.GetId(), req.GetVersion(), req.GetTitle(), req.GetContent(), req.GetCommentsEnabled(), req.GetTags())
+	if err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	return toProto(updated), nil
+}
+
+func (h *Handler) PublishPost(ctx context.Context, req *post.PublishPostRequest) (*post.Post, error) {
+	identity, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	published, err := h.postService.PublishPost(ctx, identity, req.GetId(), timeFromProto(req.GetPublishAt()), timeFromProto(req.GetUnpublishAt()))
+	if err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	return toProto(published), nil
+}
+
+func (h *Handler) UnpublishPost(ctx context.Context, req *post.UnpublishPostRequest) (*post.Post, error) {
+	identity, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	unpublished, err := h.postService.UnpublishPost(ctx, identity, req.GetId())
+	if err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	return toProto(unpublished), nil
+}
+
+func (h *Handler) GetPost(ctx context.Context, req *post.GetPostRequest) (*post.Post, error) {
+	p, err := h.postService.GetPost(ctx, viewer(ctx), req.GetId())
+	if err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	return toProto(p), nil
+}
+
+func (h *Handler) ListPosts(ctx context.Context, req *post.ListPostsRequest) (*post.ListPostsResponse, error) {
+	filter, err := toPostFilter(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return h.listPosts(ctx, req, filter)
+}
+
+func (h *Handler) ListPostsByTag(ctx context.Context, req *post.ListPostsRequest) (*post.ListPostsResponse, error) {
+	filter, err := toPostFilter(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if filter.TagSlug == "" {
+		return nil, status.Error(codes.InvalidArgument, "tag is required")
+	}
+
+	return h.listPosts(ctx, req, filter)
+}
+
+func (h *Handler) GetTimeline(ctx context.Context, req *post.GetTimelineRequest) (*post.ListPostsResponse, error) {
+	identity, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	posts, next, err := h.postService.Timeline(ctx, identity, req.GetPageSize(), req.GetPageToken())
+	if err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	resp := &post.ListPostsResponse{
+		Posts:         make([]*post.Post, 0, len(posts)),
+		NextPageToken: next,
+	}
+	for _, p := range posts {
+		resp.Posts = append(resp.Posts, toProto(p))
+	}
+
+	return resp, nil
+}
+
+func (h *Handler) ListBookmarks(ctx context.Context, req *post.ListBookmarksRequest) (*post.ListPostsResponse, error) {
+	identity, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	posts, next, err := h.postService.Bookmarks(ctx, identity, req.GetCollectionId(), req.GetPageSize(), req.GetPageToken())
+	if err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	resp := &post.ListPostsResponse{
+		Posts:         make([]*post.Post, 0, len(posts)),
+		NextPageToken: next,
+	}
+	for _, p := range posts {
+		resp.Posts = append(resp.Posts, toProto(p))
+	}
+
+	return resp, nil
+}
+
+func (h *Handler) listPosts(ctx context.Context, req *post.ListPostsRequest, filter model.PostFilter) (*post.ListPostsResponse, error) {
+	posts, next, err := h.postService.ListPosts(ctx, viewer(ctx), filter, req.GetPageSize(), req.GetPageToken())
+	if err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	resp := &post.ListPostsResponse{
+		Posts:         make([]*post.Post, 0, len(posts)),
+		NextPageToken: next,
+	}
+	for _, p := range posts {
+		resp.Posts = append(resp.Posts, toProto(p))
+	}
+
+	return resp, nil
+}
+
+func (h *Handler) SearchTags(ctx context.Context, req *post.SearchTagsRequest) (*post.ListTagsResponse, error) {
+	tags, err := h.postService.SearchTags(ctx, req.GetPrefix(), req.GetLimit())
+	if err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	return &post.ListTagsResponse{Tags: tagsToProto(tags)}, nil
+}
+
+func (h *Handler) ListPopularTags(ctx context.Context, req *post.ListPopularTagsRequest) (*post.ListTagsResponse, error) {
+	tags, err := h.postService.ListPopularTags(ctx, req.GetLimit())
+	if err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	return &post.ListTagsResponse{Tags: tagsToProto(tags)}, nil
+}
+
+func toPostFilter(req *post.ListPostsRequest) (model.PostFilter, error) {
+	filter := model.PostFilter{
+		Status:        statusFromProto(req.GetStatus()),
+		TagSlug:       req.GetTag(),
+		CreatedAfter:  timeFromProto(req.GetCreatedAfter()),
+		CreatedBefore: timeFromProto(req.GetCreatedBefore()),
+	}
+
+	if req.GetAuthorUuid() != "" {
+		authorUUID, err := uuid.Parse(req.GetAuthorUuid())
+		if err != nil {
+			return model.PostFilter{}, status.Error(codes.InvalidArgument, "invalid author uuid")
+		}
+		filter.AuthorUUID = authorUUID
+	}
+
+	return filter, nil
+}
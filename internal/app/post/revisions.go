@@ -0,0 +1,115 @@
+package post
+
+import (
+	"context"
+	"github.com/AdilBaidual/baseProject/internal/app/grpcerr"
+	"github.com/AdilBaidual/baseProject/internal/auth"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/AdilBaidual/baseProject/internal/pb/baseProject/post"
+	"github.com/AdilBaidual/baseProject/pkg/linediff"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (h *Handler) ListRevisions(ctx context.Context, req *post.ListRevisionsRequest) (*post.ListRevisionsResponse, error) {
+	identity, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	revisions, next, err := h.postService.ListRevisions(ctx, identity, req.GetPostId(), req.GetPageSize(), req.GetPageToken())
+	if err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	resp := &post.ListRevisionsResponse{
+		Revisions:     make([]*post.Revision, 0, len(revisions)),
+		NextPageToken: next,
+	}
+	for _, r := range revisions {
+		// Текст ревизий отдаётся только в GetRevision, чтобы список оставался лёгким.
+		r.Content = ""
+		resp.Revisions = append(resp.Revisions, revisionToProto(r))
+	}
+
+	return resp, nil
+}
+
+func (h *Handler) GetRevision(ctx context.Context, req *post.GetRevisionRequest) (*post.Revision, error) {
+	identity, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	revision, err := h.postService.GetRevision(ctx, identity, req.GetPostId(), req.GetVersion())
+	if err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	return revisionToProto(revision), nil
+}
+
+func (h *Handler) DiffRevisions(ctx context.Context, req *post.DiffRevisionsRequest) (*post.DiffRevisionsResponse, error) {
+	identity, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	diff, err := h.postService.DiffRevisions(ctx, identity, req.GetPostId(), req.GetFromVersion(), req.GetToVersion())
+	if err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	return &post.DiffRevisionsResponse{
+		FromVersion: diff.From.Version,
+		ToVersion:   diff.To.Version,
+		Title:       diffToProto(diff.Title),
+		Content:     diffToProto(diff.Content),
+	}, nil
+}
+
+func (h *Handler) RestoreRevision(ctx context.Context, req *post.RestoreRevisionRequest) (*post.Post, error) {
+	identity, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	restored, err := h.postService.RestoreRevision(ctx, identity, req.GetPostId(), req.GetVersion(), req.GetExpectedVersion())
+	if err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	return toProto(restored), nil
+}
+
+func revisionToProto(r model.PostRevision) *post.Revision {
+	return &post.Revision{
+		PostId:       r.PostID,
+		Version:      r.Version,
+		Title:        r.Title,
+		Content:      r.Content,
+		EditorUuid:   r.EditorUUID.String(),
+		RestoredFrom: r.RestoredFrom,
+		CreatedAt:    timestamppb.New(r.CreatedAt),
+	}
+}
+
+var diffOpToProto = map[linediff.Op]post.DiffOp{
+	linediff.Equal:  post.DiffOp_DIFF_OP_EQUAL,
+	linediff.Insert: post.DiffOp_DIFF_OP_INSERT,
+	linediff.Delete: post.DiffOp_DIFF_OP_DELETE,
+}
+
+func diffToProto(lines []linediff.Line) []*post.DiffLine {
+	res := make([]*post.DiffLine, 0, len(lines))
+	for _, l := range lines {
+		res = append(res, &post.DiffLine{
+			Op:      diffOpToProto[l.Op],
+			Text:    l.Text,
+			OldLine: int32(l.OldLine),
+			NewLine: int32(l.NewLine),
+		})
+	}
+	return res
+}
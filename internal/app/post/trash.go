@@ -0,0 +1,109 @@
+package post
+
+import (
+	"context"
+	"github.com/AdilBaidual/baseProject/internal/app/grpcerr"
+	"github.com/AdilBaidual/baseProject/internal/auth"
+	"github.com/AdilBaidual/baseProject/internal/pb/baseProject/post"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func (h *Handler) DeletePost(ctx context.Context, req *post.DeletePostRequest) (*emptypb.Empty, error) {
+	identity, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	if err := h.postService.DeletePost(ctx, identity, req.GetId()); err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (h *Handler) RestorePost(ctx context.Context, req *post.RestorePostRequest) (*post.Post, error) {
+	identity, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	restored, err := h.postService.RestorePost(ctx, identity, req.GetId())
+	if err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	return toProto(restored), nil
+}
+
+func (h *Handler) ListDeletedPosts(ctx context.Context, req *post.ListDeletedRequest) (*post.ListPostsResponse, error) {
+	identity, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	posts, next, err := h.postService.ListDeletedPosts(ctx, identity, req.GetPageSize(), req.GetPageToken())
+	if err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	resp := &post.ListPostsResponse{
+		Posts:         make([]*post.Post, 0, len(posts)),
+		NextPageToken: next,
+	}
+	for _, p := range posts {
+		resp.Posts = append(resp.Posts, toProto(p))
+	}
+
+	return resp, nil
+}
+
+func (h *Handler) DeleteComment(ctx context.Context, req *post.DeleteCommentRequest) (*emptypb.Empty, error) {
+	identity, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	if err := h.postService.DeleteComment(ctx, identity, req.GetId()); err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (h *Handler) RestoreComment(ctx context.Context, req *post.RestoreCommentRequest) (*post.Comment, error) {
+	identity, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	restored, err := h.postService.RestoreComment(ctx, identity, req.GetId())
+	if err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	return commentToProto(restored), nil
+}
+
+func (h *Handler) ListDeletedComments(ctx context.Context, req *post.ListDeletedRequest) (*post.ListCommentsResponse, error) {
+	identity, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	comments, next, err := h.postService.ListDeletedComments(ctx, identity, req.GetPageSize(), req.GetPageToken())
+	if err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	resp := &post.ListCommentsResponse{
+		Comments:      make([]*post.Comment, 0, len(comments)),
+		NextPageToken: next,
+	}
+	for _, c := range comments {
+		resp.Comments = append(resp.Comments, commentToProto(c))
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,55 @@
+package reaction
+
+import (
+	"context"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/AdilBaidual/baseProject/internal/pb/baseProject/post"
+	"github.com/google/uuid"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type reactionService interface {
+	Add(ctx context.Context, userUUID uuid.UUID, target model.ReactionTarget, kind string) (model.ReactionSummary, error)
+	Remove(ctx context.Context, userUUID uuid.UUID, target model.ReactionTarget, kind string) (model.ReactionSummary, error)
+	List(ctx context.Context, viewer uuid.UUID, target model.ReactionTarget, kind string, pageSize int32, pageToken string) ([]model.Reaction, model.ReactionSummary, string, error)
+}
+
+type Handler struct {
+	post.ReactionServiceServer
+
+	reactionService reactionService
+}
+
+func NewHandler(reactionService reactionService) *Handler {
+	return &Handler{reactionService: reactionService}
+}
+
+func Register(ctx context.Context, mux *runtime.ServeMux, conn *grpc.ClientConn, gRPCServer *grpc.Server, handler *Handler) error {
+	post.RegisterReactionServiceServer(gRPCServer, handler)
+	err := post.RegisterReactionServiceHandler(ctx, mux, conn)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func toTarget(targetType post.TargetType, id int64) (model.ReactionTarget, error) {
+	switch targetType {
+	case post.TargetType_TARGET_TYPE_POST:
+		return model.ReactionTarget{Type: model.ReactionTargetPost, ID: id}, nil
+	case post.TargetType_TARGET_TYPE_COMMENT:
+		return model.ReactionTarget{Type: model.ReactionTargetComment, ID: id}, nil
+	default:
+		return model.ReactionTarget{}, status.Error(codes.InvalidArgument, "target_type is required")
+	}
+}
+
+func reactionsToProto(summary model.ReactionSummary) *post.Reactions {
+	return &post.Reactions{
+		Counts: summary.Counts,
+		Mine:   summary.Mine,
+	}
+}
@@ -0,0 +1,78 @@
+package reaction
+
+import (
+	"context"
+	"github.com/AdilBaidual/baseProject/internal/app/grpcerr"
+	"github.com/AdilBaidual/baseProject/internal/auth"
+	"github.com/AdilBaidual/baseProject/internal/pb/baseProject/post"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (h *Handler) AddReaction(ctx context.Context, req *post.AddReactionRequest) (*post.Reactions, error) {
+	identity, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	target, err := toTarget(req.GetTargetType(), req.GetTargetId())
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := h.reactionService.Add(ctx, identity.UserUUID, target, req.GetKind())
+	if err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	return reactionsToProto(summary), nil
+}
+
+func (h *Handler) RemoveReaction(ctx context.Context, req *post.RemoveReactionRequest) (*post.Reactions, error) {
+	identity, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	target, err := toTarget(req.GetTargetType(), req.GetTargetId())
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := h.reactionService.Remove(ctx, identity.UserUUID, target, req.GetKind())
+	if err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	return reactionsToProto(summary), nil
+}
+
+func (h *Handler) ListReactions(ctx context.Context, req *post.ListReactionsRequest) (*post.ListReactionsResponse, error) {
+	target, err := toTarget(req.GetTargetType(), req.GetTargetId())
+	if err != nil {
+		return nil, err
+	}
+
+	identity, _ := auth.IdentityFromContext(ctx)
+
+	reactions, summary, next, err := h.reactionService.List(ctx, identity.UserUUID, target, req.GetKind(), req.GetPageSize(), req.GetPageToken())
+	if err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	resp := &post.ListReactionsResponse{
+		Reactions:     make([]*post.Reaction, 0, len(reactions)),
+		Summary:       reactionsToProto(summary),
+		NextPageToken: next,
+	}
+	for _, r := range reactions {
+		resp.Reactions = append(resp.Reactions, &post.Reaction{
+			UserUuid:  r.UserUUID.String(),
+			Kind:      r.Kind,
+			CreatedAt: timestamppb.New(r.CreatedAt),
+		})
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,109 @@
+package sso
+
+import (
+	"context"
+	"github.com/AdilBaidual/baseProject/internal/app/grpcerr"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/AdilBaidual/baseProject/internal/pb/baseProject/user"
+	"github.com/AdilBaidual/baseProject/pkg/clientip"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"net/http"
+)
+
+type ssoService interface {
+	Providers() []string
+	Begin(ctx context.Context, provider string) (string, error)
+	Complete(ctx context.Context, provider, state, code, ip string) (model.LoginResult, error)
+}
+
+// Handler HTTP-эндпоинты входа через внешних провайдеров. Это не gRPC-методы:
+// провайдер возвращает пользователя редиректом на callback.
+type Handler struct {
+	logger     *zap.Logger
+	ssoService ssoService
+	resolver   *clientip.Resolver
+	marshaler  protojson.MarshalOptions
+}
+
+func NewHandler(logger *zap.Logger, ssoService ssoService, resolver *clientip.Resolver) *Handler {
+	return &Handler{
+		logger:     logger,
+		ssoService: ssoService,
+		resolver:   resolver,
+		marshaler:  protojson.MarshalOptions{EmitUnpopulated: true},
+	}
+}
+
+// Register подключает эндпоинты. Interceptor'ы gRPC сюда не доходят, поэтому
+// limit оборачивает каждый обработчик ограничителями под условным именем
+// метода "/sso/<Method>", по которому ищутся правила в конфиге.
+func Register(mux *http.ServeMux, handler *Handler, limit func(method string, next http.Handler) http.Handler) {
+	mux.Handle("GET /v1/auth/sso", limit("/sso/Providers", http.HandlerFunc(handler.providers)))
+	mux.Handle("GET /v1/auth/sso/{provider}/login", limit("/sso/Login", http.HandlerFunc(handler.login)))
+	mux.Handle("GET /v1/auth/sso/{provider}/callback", limit("/sso/Callback", http.HandlerFunc(handler.callback)))
+}
+
+func (h *Handler) providers(w http.ResponseWriter, _ *http.Request) {
+	h.write(w, http.StatusOK, &user.ListSsoProvidersResponse{Providers: h.ssoService.Providers()})
+}
+
+func (h *Handler) login(w http.ResponseWriter, r *http.Request) {
+	authURL, err := h.ssoService.Begin(r.Context(), r.PathValue("provider"))
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+func (h *Handler) callback(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	if providerErr := q.Get("error"); providerErr != "" {
+		h.writeError(w, r, status.Errorf(codes.Unauthenticated, "identity provider: %s %s", providerErr, q.Get("error_description")))
+		return
+	}
+
+	result, err := h.ssoService.Complete(r.Context(), r.PathValue("provider"), q.Get("state"), q.Get("code"), h.resolver.FromRequest(r))
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	h.write(w, http.StatusOK, &user.LoginResponse{
+		AccessToken: result.AccessToken,
+		ExpiresAt:   timestamppb.New(result.ExpiresAt),
+		MfaRequired: result.MFARequired,
+		MfaToken:    result.MFAToken,
+	})
+}
+
+func (h *Handler) writeError(w http.ResponseWriter, r *http.Request, err error) {
+	st, ok := status.FromError(err)
+	if !ok {
+		st = status.Convert(grpcerr.ToStatus(r.Context(), err))
+	}
+
+	h.write(w, runtime.HTTPStatusFromCode(st.Code()), st.Proto())
+}
+
+func (h *Handler) write(w http.ResponseWriter, code int, msg proto.Message) {
+	body, err := h.marshaler.Marshal(msg)
+	if err != nil {
+		h.logger.Error("failed to marshal sso response", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	_, _ = w.Write(body)
+}
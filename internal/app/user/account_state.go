@@ -0,0 +1,54 @@
+package user
+
+import (
+	"context"
+	"github.com/AdilBaidual/baseProject/internal/app/grpcerr"
+	"github.com/AdilBaidual/baseProject/internal/auth"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/AdilBaidual/baseProject/internal/pb/baseProject/user"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+var accountStateToProto = map[string]user.AccountState{
+	model.AccountStateActive:       user.AccountState_ACCOUNT_STATE_ACTIVE,
+	model.AccountStateSuspended:    user.AccountState_ACCOUNT_STATE_SUSPENDED,
+	model.AccountStateBanned:       user.AccountState_ACCOUNT_STATE_BANNED,
+	model.AccountStateShadowBanned: user.AccountState_ACCOUNT_STATE_SHADOW_BANNED,
+}
+
+func (h *Handler) SetAccountState(ctx context.Context, req *user.SetAccountStateRequest) (*emptypb.Empty, error) {
+	identity, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	userUUID, err := uuid.Parse(req.GetUserUuid())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user uuid")
+	}
+
+	var state string
+	for s, p := range accountStateToProto {
+		if p == req.GetState() {
+			state = s
+		}
+	}
+	if state == "" {
+		return nil, status.Error(codes.InvalidArgument, "state is required")
+	}
+
+	restriction := model.AccountRestriction{State: state, Reason: req.GetReason()}
+	if req.Until != nil {
+		until := req.GetUntil().AsTime()
+		restriction.Until = &until
+	}
+
+	if err = h.userService.SetAccountState(ctx, identity, userUUID, restriction); err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
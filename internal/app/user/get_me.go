@@ -0,0 +1,48 @@
+package user
+
+import (
+	"context"
+	"github.com/AdilBaidual/baseProject/internal/app/grpcerr"
+	"github.com/AdilBaidual/baseProject/internal/auth"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/AdilBaidual/baseProject/internal/pb/baseProject/user"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"time"
+)
+
+func (h *Handler) GetMe(ctx context.Context, _ *emptypb.Empty) (*user.User, error) {
+	identity, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	u, err := h.userService.GetUser(ctx, identity.UserUUID)
+	if err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	return toProto(u), nil
+}
+
+func toProto(u model.User) *user.User {
+	state := u.State(time.Now())
+
+	resp := &user.User{
+		Uuid:          u.UUID.String(),
+		Email:         u.Email,
+		FirstName:     u.FirstName,
+		Username:      u.Username,
+		Roles:         u.Roles,
+		Permissions:   u.Permissions,
+		EmailVerified: u.EmailVerified(),
+		AccountState:  accountStateToProto[state],
+	}
+	if state != model.AccountStateActive && u.StateUntil != nil {
+		resp.StateUntil = timestamppb.New(*u.StateUntil)
+	}
+
+	return resp
+}
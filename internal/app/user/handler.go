@@ -0,0 +1,58 @@
+package user
+
+import (
+	"context"
+	"github.com/AdilBaidual/baseProject/internal/auth"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/AdilBaidual/baseProject/internal/pb/baseProject/user"
+	"github.com/AdilBaidual/baseProject/pkg/clientip"
+	"github.com/google/uuid"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+)
+
+type userService interface {
+	Register(ctx context.Context, email, firstName, password string) (uuid.UUID, error)
+	Login(ctx context.Context, email, password, ip string) (model.LoginResult, error)
+	VerifyMFA(ctx context.Context, mfaToken, code, recoveryCode, ip string) (model.LoginResult, error)
+	EnrollTOTP(ctx context.Context, userUUID uuid.UUID) (string, string, error)
+	ConfirmTOTP(ctx context.Context, userUUID uuid.UUID, code string) ([]string, error)
+	DisableTOTP(ctx context.Context, userUUID uuid.UUID, code, recoveryCode string) error
+	GetUser(ctx context.Context, id uuid.UUID) (model.User, error)
+	AssignRole(ctx context.Context, userUUID uuid.UUID, role string) error
+	RevokeRole(ctx context.Context, userUUID uuid.UUID, role string) error
+	UnlockAccount(ctx context.Context, actorUUID, userUUID uuid.UUID) error
+	DeleteAccount(ctx context.Context, userUUID uuid.UUID) error
+	DeleteUser(ctx context.Context, actorUUID, userUUID uuid.UUID) error
+	RestoreUser(ctx context.Context, actorUUID, userUUID uuid.UUID) error
+	SetAccountState(ctx context.Context, actor auth.Identity, userUUID uuid.UUID, restriction model.AccountRestriction) error
+	SendVerificationEmail(ctx context.Context, userUUID uuid.UUID) error
+	VerifyEmail(ctx context.Context, token string) error
+	RequestPasswordReset(ctx context.Context, email string)
+	ResetPassword(ctx context.Context, token, newPassword string) error
+	SetUsername(ctx context.Context, userUUID uuid.UUID, username string) (model.User, error)
+	UsernameHistory(ctx context.Context, userUUID uuid.UUID) ([]model.UsernameChange, error)
+}
+
+type Handler struct {
+	user.UserServiceServer
+
+	userService userService
+	resolver    *clientip.Resolver
+}
+
+func NewHandler(userService userService, resolver *clientip.Resolver) *Handler {
+	return &Handler{
+		userService: userService,
+		resolver:    resolver,
+	}
+}
+
+func Register(ctx context.Context, mux *runtime.ServeMux, conn *grpc.ClientConn, gRPCServer *grpc.Server, handler *Handler) error {
+	user.RegisterUserServiceServer(gRPCServer, handler)
+	err := user.RegisterUserServiceHandler(ctx, mux, conn)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package user
+
+import (
+	"context"
+	"github.com/AdilBaidual/baseProject/internal/app/grpcerr"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/AdilBaidual/baseProject/internal/pb/baseProject/user"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (h *Handler) Login(ctx context.Context, req *user.LoginRequest) (*user.LoginResponse, error) {
+	result, err := h.userService.Login(ctx, req.GetEmail(), req.GetPassword(), h.resolver.FromContext(ctx))
+	if err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	return toLoginResponse(result), nil
+}
+
+func (h *Handler) VerifyMfa(ctx context.Context, req *user.VerifyMfaRequest) (*user.LoginResponse, error) {
+	result, err := h.userService.VerifyMFA(ctx, req.GetMfaToken(), req.GetCode(), req.GetRecoveryCode(), h.resolver.FromContext(ctx))
+	if err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	return toLoginResponse(result), nil
+}
+
+func toLoginResponse(result model.LoginResult) *user.LoginResponse {
+	return &user.LoginResponse{
+		AccessToken: result.AccessToken,
+		ExpiresAt:   timestamppb.New(result.ExpiresAt),
+		MfaRequired: result.MFARequired,
+		MfaToken:    result.MFAToken,
+	}
+}
@@ -0,0 +1,55 @@
+package user
+
+import (
+	"context"
+	"github.com/AdilBaidual/baseProject/internal/app/grpcerr"
+	"github.com/AdilBaidual/baseProject/internal/auth"
+	"github.com/AdilBaidual/baseProject/internal/pb/baseProject/user"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func (h *Handler) EnrollTotp(ctx context.Context, _ *emptypb.Empty) (*user.EnrollTotpResponse, error) {
+	identity, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	secret, uri, err := h.userService.EnrollTOTP(ctx, identity.UserUUID)
+	if err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	return &user.EnrollTotpResponse{
+		Secret:          secret,
+		ProvisioningUri: uri,
+	}, nil
+}
+
+func (h *Handler) ConfirmTotp(ctx context.Context, req *user.ConfirmTotpRequest) (*user.ConfirmTotpResponse, error) {
+	identity, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	recoveryCodes, err := h.userService.ConfirmTOTP(ctx, identity.UserUUID, req.GetCode())
+	if err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	return &user.ConfirmTotpResponse{RecoveryCodes: recoveryCodes}, nil
+}
+
+func (h *Handler) DisableTotp(ctx context.Context, req *user.DisableTotpRequest) (*emptypb.Empty, error) {
+	identity, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	if err := h.userService.DisableTOTP(ctx, identity.UserUUID, req.GetCode(), req.GetRecoveryCode()); err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
@@ -0,0 +1,16 @@
+package user
+
+import (
+	"context"
+	"github.com/AdilBaidual/baseProject/internal/app/grpcerr"
+	"github.com/AdilBaidual/baseProject/internal/pb/baseProject/user"
+)
+
+func (h *Handler) Register(ctx context.Context, req *user.RegisterRequest) (*user.RegisterResponse, error) {
+	id, err := h.userService.Register(ctx, req.GetEmail(), req.GetFirstName(), req.GetPassword())
+	if err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	return &user.RegisterResponse{Uuid: id.String()}, nil
+}
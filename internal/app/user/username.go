@@ -0,0 +1,48 @@
+package user
+
+import (
+	"context"
+	"github.com/AdilBaidual/baseProject/internal/app/grpcerr"
+	"github.com/AdilBaidual/baseProject/internal/auth"
+	"github.com/AdilBaidual/baseProject/internal/pb/baseProject/user"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (h *Handler) SetUsername(ctx context.Context, req *user.SetUsernameRequest) (*user.User, error) {
+	identity, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	u, err := h.userService.SetUsername(ctx, identity.UserUUID, req.GetUsername())
+	if err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	return toProto(u), nil
+}
+
+func (h *Handler) ListUsernameHistory(ctx context.Context, req *user.ListUsernameHistoryRequest) (*user.ListUsernameHistoryResponse, error) {
+	userUUID, err := uuid.Parse(req.GetUserUuid())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user uuid")
+	}
+
+	changes, err := h.userService.UsernameHistory(ctx, userUUID)
+	if err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	resp := &user.ListUsernameHistoryResponse{Changes: make([]*user.UsernameChange, 0, len(changes))}
+	for _, c := range changes {
+		resp.Changes = append(resp.Changes, &user.UsernameChange{
+			Username:  c.Username,
+			ChangedAt: timestamppb.New(c.ChangedAt),
+		})
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,46 @@
+package user
+
+import (
+	"context"
+	"github.com/AdilBaidual/baseProject/internal/app/grpcerr"
+	"github.com/AdilBaidual/baseProject/internal/auth"
+	"github.com/AdilBaidual/baseProject/internal/pb/baseProject/user"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func (h *Handler) SendVerificationEmail(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	identity, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	if err := h.userService.SendVerificationEmail(ctx, identity.UserUUID); err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (h *Handler) VerifyEmail(ctx context.Context, req *user.VerifyEmailRequest) (*emptypb.Empty, error) {
+	if err := h.userService.VerifyEmail(ctx, req.GetToken()); err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (h *Handler) RequestPasswordReset(ctx context.Context, req *user.RequestPasswordResetRequest) (*emptypb.Empty, error) {
+	h.userService.RequestPasswordReset(ctx, req.GetEmail())
+
+	return &emptypb.Empty{}, nil
+}
+
+func (h *Handler) ResetPassword(ctx context.Context, req *user.ResetPasswordRequest) (*emptypb.Empty, error) {
+	if err := h.userService.ResetPassword(ctx, req.GetToken(), req.GetNewPassword()); err != nil {
+		return nil, grpcerr.ToStatus(ctx, err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
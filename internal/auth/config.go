@@ -0,0 +1,10 @@
+package auth
+
+import "time"
+
+type Config struct {
+	JWTSecret      string        `env:"AUTH_JWT_SECRET" env-required:"true"`
+	Issuer         string        `yaml:"issuer"`
+	AccessTokenTTL time.Duration `yaml:"access_token_ttl"`
+	PublicMethods  []string      `yaml:"public_methods"`
+}
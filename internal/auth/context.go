@@ -0,0 +1,57 @@
+package auth
+
+import (
+	"context"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/google/uuid"
+	"slices"
+	"time"
+)
+
+type identityKey struct{}
+
+// Identity описывает аутентифицированного пользователя запроса.
+// При аутентификации по API-ключу Permissions ограничены scope ключа.
+// AccountState — действующее на момент запроса состояние аккаунта.
+type Identity struct {
+	UserUUID      uuid.UUID
+	APIKeyID      uuid.UUID
+	EmailVerified bool
+	Roles         []string
+	Permissions   []string
+	AccountState  string
+	StateUntil    *time.Time
+}
+
+// RestrictUnverified убирает права, требующие подтверждённого email.
+func RestrictUnverified(permissions []string) []string {
+	return slices.DeleteFunc(slices.Clone(permissions), func(p string) bool {
+		return slices.Contains(model.VerifiedOnlyPermissions, p)
+	})
+}
+
+func (i Identity) ViaAPIKey() bool {
+	return i.APIKeyID != uuid.Nil
+}
+
+func (i Identity) HasPermission(permission string) bool {
+	return slices.Contains(i.Permissions, permission)
+}
+
+// Suspended аккаунт приостановлен и может только читать.
+func (i Identity) Suspended() bool {
+	return i.AccountState == model.AccountStateSuspended
+}
+
+func (i Identity) HasRole(role string) bool {
+	return slices.Contains(i.Roles, role)
+}
+
+func WithIdentity(ctx context.Context, identity Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+func IdentityFromContext(ctx context.Context) (Identity, bool) {
+	identity, ok := ctx.Value(identityKey{}).(Identity)
+	return identity, ok
+}
@@ -0,0 +1,89 @@
+package auth
+
+import (
+	"github.com/AdilBaidual/baseProject/internal/pb/baseProject/options"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"strings"
+	"sync"
+)
+
+// Policy определяет правила доступа к методам по опции (options.auth) из proto.
+type Policy struct {
+	public []string
+	cache  sync.Map
+	writes sync.Map
+}
+
+func NewPolicy(cfg Config) *Policy {
+	return &Policy{public: cfg.PublicMethods}
+}
+
+// Rule возвращает правило доступа для метода вида "/package.Service/Method".
+// Методы без опции требуют аутентификации, но не конкретного права.
+func (p *Policy) Rule(fullMethod string) *options.AuthRule {
+	if rule, ok := p.cache.Load(fullMethod); ok {
+		return rule.(*options.AuthRule)
+	}
+
+	rule := p.resolve(fullMethod)
+	p.cache.Store(fullMethod, rule)
+
+	return rule
+}
+
+// IsWrite сообщает, изменяет ли метод данные. Читающими считаются методы,
+// опубликованные через HTTP GET, методы без HTTP-правила с потоком только от
+// сервера (скачивание) и методы, не описанные в proto.
+func (p *Policy) IsWrite(fullMethod string) bool {
+	if write, ok := p.writes.Load(fullMethod); ok {
+		return write.(bool)
+	}
+
+	write := false
+	if method, ok := methodDescriptor(fullMethod); ok {
+		rule, _ := proto.GetExtension(method.Options(), annotations.E_Http).(*annotations.HttpRule)
+		if rule != nil {
+			write = rule.GetGet() == ""
+		} else {
+			write = !method.IsStreamingServer() || method.IsStreamingClient()
+		}
+	}
+	p.writes.Store(fullMethod, write)
+
+	return write
+}
+
+func (p *Policy) resolve(fullMethod string) *options.AuthRule {
+	for _, pattern := range p.public {
+		if pattern == fullMethod || (strings.HasSuffix(pattern, "/*") && strings.HasPrefix(fullMethod, strings.TrimSuffix(pattern, "*"))) {
+			return &options.AuthRule{Public: true}
+		}
+	}
+
+	method, ok := methodDescriptor(fullMethod)
+	if !ok {
+		return &options.AuthRule{}
+	}
+
+	rule, ok := proto.GetExtension(method.Options(), options.E_Auth).(*options.AuthRule)
+	if !ok || rule == nil {
+		return &options.AuthRule{}
+	}
+
+	return rule
+}
+
+func methodDescriptor(fullMethod string) (protoreflect.MethodDescriptor, bool) {
+	name := protoreflect.FullName(strings.ReplaceAll(strings.TrimPrefix(fullMethod, "/"), "/", "."))
+
+	desc, err := protoregistry.GlobalFiles.FindDescriptorByName(name)
+	if err != nil {
+		return nil, false
+	}
+
+	method, ok := desc.(protoreflect.MethodDescriptor)
+	return method, ok
+}
@@ -0,0 +1,90 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	TokenTypeAccess = "access"
+	// TokenTypeMFA выдаётся после проверки пароля и обменивается на токен доступа после проверки второго фактора.
+	TokenTypeMFA = "mfa"
+)
+
+var ErrInvalidToken = errors.New("invalid token")
+
+type Claims struct {
+	jwt.RegisteredClaims
+	Type string `json:"typ"`
+}
+
+type TokenManager struct {
+	secret []byte
+	issuer string
+	ttl    time.Duration
+}
+
+func NewTokenManager(cfg Config) *TokenManager {
+	ttl := cfg.AccessTokenTTL
+	if ttl <= 0 {
+		ttl = 15 * time.Minute
+	}
+
+	return &TokenManager{
+		secret: []byte(cfg.JWTSecret),
+		issuer: cfg.Issuer,
+		ttl:    ttl,
+	}
+}
+
+func (m *TokenManager) Issue(userUUID uuid.UUID, tokenType string, ttl time.Duration) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+
+	claims := Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Issuer:    m.issuer,
+			Subject:   userUUID.String(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+		Type: tokenType,
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(m.secret)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("TokenManager.Issue - SignedString - %w", err)
+	}
+
+	return token, expiresAt, nil
+}
+
+func (m *TokenManager) IssueAccess(userUUID uuid.UUID) (string, time.Time, error) {
+	return m.Issue(userUUID, TokenTypeAccess, m.ttl)
+}
+
+// Parse проверяет подпись, срок действия и тип токена.
+func (m *TokenManager) Parse(token, tokenType string) (Claims, error) {
+	var claims Claims
+
+	_, err := jwt.ParseWithClaims(token, &claims, func(*jwt.Token) (interface{}, error) {
+		return m.secret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(m.issuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return Claims{}, fmt.Errorf("%w: %s", ErrInvalidToken, err.Error())
+	}
+
+	if claims.Type != tokenType {
+		return Claims{}, fmt.Errorf("%w: unexpected token type %q", ErrInvalidToken, claims.Type)
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,218 @@
+package interceptor
+
+import (
+	"context"
+	"errors"
+	"github.com/AdilBaidual/baseProject/internal/auth"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"strings"
+	"time"
+)
+
+const (
+	AuthorizationHeader = "authorization"
+	bearerPrefix        = "bearer "
+)
+
+type authenticator interface {
+	Authenticate(ctx context.Context, credential string) (auth.Identity, error)
+}
+
+type Auth struct {
+	logger  *zap.Logger
+	policy  *auth.Policy
+	tokens  authenticator
+	apiKeys authenticator
+}
+
+func NewAuth(logger *zap.Logger, policy *auth.Policy, tokens authenticator, apiKeys authenticator) *Auth {
+	return &Auth{
+		logger:  logger,
+		policy:  policy,
+		tokens:  tokens,
+		apiKeys: apiKeys,
+	}
+}
+
+// AuthenticationInterceptor проверяет bearer-токен или API-ключ, если они переданы,
+// и кладёт пользователя вместе с его правами в контекст.
+func (a *Auth) AuthenticationInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := a.authenticate(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// AuthorizationInterceptor проверяет право, указанное в опции (options.auth) метода.
+func (a *Auth) AuthorizationInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := a.authorize(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// RestrictionInterceptor запрещает пользователям с приостановленным аккаунтом
+// вызывать изменяющие методы, кроме отмеченных allow_restricted в опции (options.auth).
+func (a *Auth) RestrictionInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := a.checkRestriction(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func (a *Auth) StreamAuthenticationInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := a.authenticate(ss.Context())
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func (a *Auth) StreamAuthorizationInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := a.authorize(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+
+		return handler(srv, ss)
+	}
+}
+
+func (a *Auth) StreamRestrictionInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := a.checkRestriction(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+
+		return handler(srv, ss)
+	}
+}
+
+// Reauthenticate повторно проверяет учётные данные, с которыми открыт поток.
+// Interceptor проверяет их только при открытии, а за время жизни потока
+// сессию могут отозвать, а аккаунт — заблокировать или удалить.
+func (a *Auth) Reauthenticate(ctx context.Context) error {
+	if _, ok := auth.IdentityFromContext(ctx); !ok {
+		return nil
+	}
+
+	_, err := a.authenticate(ctx)
+	return err
+}
+
+func (a *Auth) authenticate(ctx context.Context) (context.Context, error) {
+	var (
+		identity auth.Identity
+		err      error
+	)
+
+	if token := bearerToken(ctx); token != "" {
+		identity, err = a.tokens.Authenticate(ctx, token)
+	} else if key := apiKey(ctx); key != "" {
+		identity, err = a.apiKeys.Authenticate(ctx, key)
+	} else {
+		return ctx, nil
+	}
+
+	if err != nil {
+		if errors.Is(err, model.ErrUnauthenticated) || errors.Is(err, auth.ErrInvalidToken) {
+			return ctx, status.Error(codes.Unauthenticated, "invalid or expired credentials")
+		}
+
+		a.logger.Error("authentication failed", zap.Error(err))
+		return ctx, status.Error(codes.Internal, "authentication failed")
+	}
+
+	return auth.WithIdentity(ctx, identity), nil
+}
+
+func (a *Auth) authorize(ctx context.Context, method string) error {
+	rule := a.policy.Rule(method)
+	if rule.GetPublic() {
+		return nil
+	}
+
+	identity, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	permission := rule.GetPermission()
+	if permission == "" {
+		// Ключ действует только в пределах своих scope: методы без права, в том
+		// числе управление аккаунтом, по ключу недоступны.
+		if identity.ViaAPIKey() {
+			return status.Error(codes.PermissionDenied, "method is not available with an api key")
+		}
+		return nil
+	}
+
+	if !identity.HasPermission(permission) {
+		return status.Errorf(codes.PermissionDenied, "permission %q required", permission)
+	}
+
+	return nil
+}
+
+func (a *Auth) checkRestriction(ctx context.Context, method string) error {
+	identity, ok := auth.IdentityFromContext(ctx)
+	if !ok || !identity.Suspended() {
+		return nil
+	}
+
+	if a.policy.Rule(method).GetAllowRestricted() || !a.policy.IsWrite(method) {
+		return nil
+	}
+
+	if identity.StateUntil != nil {
+		return status.Errorf(codes.PermissionDenied, "account is suspended until %s", identity.StateUntil.UTC().Format(time.RFC3339))
+	}
+
+	return status.Error(codes.PermissionDenied, "account is suspended")
+}
+
+func bearerToken(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	for _, value := range md.Get(AuthorizationHeader) {
+		if len(value) > len(bearerPrefix) && strings.EqualFold(value[:len(bearerPrefix)], bearerPrefix) {
+			return strings.TrimSpace(value[len(bearerPrefix):])
+		}
+	}
+
+	return ""
+}
+
+func apiKey(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	if values := md.Get(APIKeyHeader); len(values) > 0 {
+		return strings.TrimSpace(values[0])
+	}
+
+	return ""
+}
@@ -0,0 +1,117 @@
+package interceptor
+
+import (
+	"context"
+	"github.com/AdilBaidual/baseProject/pkg/concurrency"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net/http"
+	"strings"
+)
+
+type ConcurrencyLimiter struct {
+	logger  *zap.Logger
+	cfg     concurrency.Config
+	limiter *concurrency.Limiter
+}
+
+func NewConcurrencyLimiter(logger *zap.Logger, cfg concurrency.Config, limiter *concurrency.Limiter) *ConcurrencyLimiter {
+	return &ConcurrencyLimiter{
+		logger:  logger,
+		cfg:     cfg,
+		limiter: limiter,
+	}
+}
+
+func (cl *ConcurrencyLimiter) ConcurrencyLimitInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !cl.cfg.Enabled {
+			return handler(ctx, req)
+		}
+
+		priority := cl.priorityFor(info.FullMethod)
+
+		token, ok := cl.limiter.Acquire(priority)
+		if !ok {
+			cl.logger.Warn("request shed by concurrency limiter",
+				zap.String("method", info.FullMethod),
+				zap.String("priority", string(priority)),
+			)
+			return nil, status.Error(codes.Unavailable, "server is overloaded, try again later")
+		}
+
+		resp, err := handler(ctx, req)
+
+		switch status.Code(err) {
+		case codes.DeadlineExceeded, codes.Unavailable:
+			token.OnDropped()
+		case codes.Canceled, codes.ResourceExhausted:
+			token.OnIgnore()
+		default:
+			token.OnSuccess()
+		}
+
+		return resp, err
+	}
+}
+
+// HTTPHandler занимает слот ограничителя на время HTTP-запроса, обслуживаемого
+// в обход grpc-gateway. method — условное имя для поиска приоритета.
+func (cl *ConcurrencyLimiter) HTTPHandler(method string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !cl.cfg.Enabled {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		priority := cl.priorityFor(method)
+
+		token, ok := cl.limiter.Acquire(priority)
+		if !ok {
+			cl.logger.Warn("request shed by concurrency limiter",
+				zap.String("method", method),
+				zap.String("priority", string(priority)),
+			)
+			http.Error(w, "server is overloaded, try again later", http.StatusServiceUnavailable)
+			return
+		}
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		switch {
+		case rec.status == http.StatusGatewayTimeout, rec.status == http.StatusServiceUnavailable:
+			token.OnDropped()
+		case rec.status == http.StatusTooManyRequests, r.Context().Err() != nil:
+			token.OnIgnore()
+		default:
+			token.OnSuccess()
+		}
+	})
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (cl *ConcurrencyLimiter) priorityFor(method string) concurrency.Priority {
+	if p, ok := cl.cfg.Priorities[method]; ok {
+		return p
+	}
+
+	if idx := strings.LastIndex(method, "/"); idx > 0 {
+		if p, ok := cl.cfg.Priorities[method[:idx]+"/*"]; ok {
+			return p
+		}
+	}
+
+	return concurrency.PriorityNormal
+}
@@ -0,0 +1,92 @@
+package interceptor
+
+import (
+	"context"
+	"errors"
+	"github.com/AdilBaidual/baseProject/pkg/storage/postgres"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"strings"
+	"time"
+)
+
+type DeadlineConfig struct {
+	Default time.Duration            `yaml:"default" env:"DEADLINE_DEFAULT"`
+	Methods map[string]time.Duration `yaml:"methods"`
+}
+
+type Deadline struct {
+	logger *zap.Logger
+	cfg    DeadlineConfig
+}
+
+func NewDeadline(logger *zap.Logger, cfg DeadlineConfig) *Deadline {
+	return &Deadline{
+		logger: logger,
+		cfg:    cfg,
+	}
+}
+
+func (d *Deadline) DeadlineInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+
+		// Дедлайн клиента имеет приоритет над настройками сервера.
+		if _, ok := ctx.Deadline(); !ok {
+			if timeout := d.timeoutFor(info.FullMethod); timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, timeout)
+				defer cancel()
+			}
+		}
+
+		ctx, timings := postgres.WithTimings(ctx)
+
+		resp, err := handler(ctx, req)
+
+		if errors.Is(err, context.DeadlineExceeded) {
+			err = status.Error(codes.DeadlineExceeded, "request deadline exceeded")
+		}
+
+		if status.Code(err) == codes.DeadlineExceeded || errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			d.logTimeout(ctx, info.FullMethod, start, timings)
+		}
+
+		return resp, err
+	}
+}
+
+func (d *Deadline) logTimeout(ctx context.Context, method string, start time.Time, timings *postgres.Timings) {
+	elapsed := time.Since(start)
+
+	fields := []zap.Field{
+		zap.String("method", method),
+		zap.Duration("elapsed", elapsed),
+		zap.Duration("db_acquire", timings.Acquire()),
+		zap.Duration("db_query", timings.Query()),
+		zap.Int64("db_acquires", timings.Acquires()),
+		zap.Int64("db_queries", timings.Queries()),
+		zap.Duration("other", elapsed-timings.Acquire()-timings.Query()),
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		fields = append(fields, zap.Duration("budget", deadline.Sub(start)))
+	}
+
+	d.logger.Warn("request deadline exceeded", fields...)
+}
+
+func (d *Deadline) timeoutFor(method string) time.Duration {
+	if timeout, ok := d.cfg.Methods[method]; ok {
+		return timeout
+	}
+
+	if idx := strings.LastIndex(method, "/"); idx > 0 {
+		if timeout, ok := d.cfg.Methods[method[:idx]+"/*"]; ok {
+			return timeout
+		}
+	}
+
+	return d.cfg.Default
+}
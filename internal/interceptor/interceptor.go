@@ -40,3 +40,34 @@ func (ic *Interceptor) LoggingInterceptor() grpc.UnaryServerInterceptor {
 		return resp, err
 	}
 }
+
+func (ic *Interceptor) StreamLoggingInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+
+		spanContext := trace.SpanContextFromContext(ss.Context())
+		requestLogger := ic.logger.With(zap.String("request_id", spanContext.TraceID().String()))
+		ctx := context.WithValue(ss.Context(), "logger", requestLogger)
+
+		err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+
+		logInfos := []zap.Field{zap.String("method", info.FullMethod), zap.String("processing time", time.Since(start).String())}
+		if err != nil {
+			logInfos = append(logInfos, zap.String("errors", err.Error()))
+		}
+
+		requestLogger.Info("Stream info", logInfos...)
+
+		return err
+	}
+}
+
+// serverStream подменяет контекст потока, как unary-перехватчики подменяют ctx.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,173 @@
+package interceptor
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/AdilBaidual/baseProject/internal/auth"
+	"github.com/AdilBaidual/baseProject/pkg/clientip"
+	"github.com/AdilBaidual/baseProject/pkg/ratelimit"
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	RetryAfterHeader = "retry-after"
+	APIKeyHeader     = "x-api-key"
+)
+
+type RateLimiter struct {
+	logger   *zap.Logger
+	cfg      ratelimit.Config
+	backend  ratelimit.Backend
+	resolver *clientip.Resolver
+}
+
+func NewRateLimiter(logger *zap.Logger, cfg ratelimit.Config, backend ratelimit.Backend, resolver *clientip.Resolver) *RateLimiter {
+	return &RateLimiter{
+		logger:   logger,
+		cfg:      cfg,
+		backend:  backend,
+		resolver: resolver,
+	}
+}
+
+func (rl *RateLimiter) RateLimitInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := rl.check(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamRateLimitInterceptor расходует токен при открытии потока.
+func (rl *RateLimiter) StreamRateLimitInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := rl.check(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+
+		return handler(srv, ss)
+	}
+}
+
+// HTTPHandler ограничивает HTTP-эндпоинт, обслуживаемый в обход grpc-gateway.
+// method — условное имя для поиска правила, например "/sso/Login"; запросы
+// таких эндпоинтов анонимны, поэтому лимит всегда считается по IP клиента.
+func (rl *RateLimiter) HTTPHandler(method string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if seconds, ok := rl.take(r.Context(), method, rl.resolver.FromRequest(r)); !ok {
+			w.Header().Set(RetryAfterHeader, strconv.Itoa(seconds))
+			http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (rl *RateLimiter) check(ctx context.Context, method string) error {
+	seconds, ok := rl.take(ctx, method, "")
+	if ok {
+		return nil
+	}
+
+	if err := grpc.SetHeader(ctx, metadata.Pairs(RetryAfterHeader, strconv.Itoa(seconds))); err != nil {
+		rl.logger.Warn("failed to set retry-after header", zap.Error(err))
+	}
+
+	st := status.New(codes.ResourceExhausted, "rate limit exceeded")
+	if detailed, err := st.WithDetails(&errdetails.RetryInfo{
+		RetryDelay: durationpb.New(time.Duration(seconds) * time.Second),
+	}); err == nil {
+		st = detailed
+	}
+
+	return st.Err()
+}
+
+// take расходует токен из корзины метода. ip, если задан, заменяет IP из
+// контекста. При отказе возвращает, через сколько секунд повторить запрос.
+func (rl *RateLimiter) take(ctx context.Context, method, ip string) (int, bool) {
+	if !rl.cfg.Enabled {
+		return 0, true
+	}
+
+	rule := rl.ruleFor(method)
+	if !rule.Enabled() {
+		return 0, true
+	}
+
+	subject := "ip:" + ip
+	if ip == "" {
+		subject = rl.subject(ctx, rule.Key)
+	}
+
+	res, err := rl.backend.Take(ctx, method+"|"+subject, rule)
+	if err != nil {
+		// Недоступность хранилища лимитов не должна ронять API.
+		rl.logger.Error("rate limit backend error", zap.Error(err), zap.String("method", method))
+		return 0, true
+	}
+
+	if res.Allowed {
+		return 0, true
+	}
+
+	seconds := int(math.Ceil(res.RetryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+
+	return seconds, false
+}
+
+// ruleFor ищет правило для метода: точное совпадение, затем "/package.Service/*", затем default.
+func (rl *RateLimiter) ruleFor(method string) ratelimit.Rule {
+	if rule, ok := rl.cfg.Methods[method]; ok {
+		return rule
+	}
+
+	if idx := strings.LastIndex(method, "/"); idx > 0 {
+		if rule, ok := rl.cfg.Methods[method[:idx]+"/*"]; ok {
+			return rule
+		}
+	}
+
+	return rl.cfg.Default
+}
+
+// subject возвращает идентификатор, по которому считается лимит.
+// Если нужного идентификатора в запросе нет, используется IP клиента.
+func (rl *RateLimiter) subject(ctx context.Context, keyType string) string {
+	switch keyType {
+	case ratelimit.KeyUser:
+		if identity, ok := auth.IdentityFromContext(ctx); ok {
+			return "user:" + identity.UserUUID.String()
+		}
+	case ratelimit.KeyAPIKey:
+		if identity, ok := auth.IdentityFromContext(ctx); ok && identity.ViaAPIKey() {
+			return "api_key:" + identity.APIKeyID.String()
+		}
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get(APIKeyHeader); len(values) > 0 && values[0] != "" {
+				sum := sha256.Sum256([]byte(values[0]))
+				return "api_key:" + hex.EncodeToString(sum[:8])
+			}
+		}
+	}
+
+	return "ip:" + rl.resolver.FromContext(ctx)
+}
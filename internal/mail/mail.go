@@ -0,0 +1,25 @@
+package mail
+
+import (
+	"embed"
+	"fmt"
+	"github.com/AdilBaidual/baseProject/pkg/mailer"
+	"io/fs"
+)
+
+const (
+	TemplateVerifyEmail   = "verify_email"
+	TemplatePasswordReset = "password_reset"
+)
+
+//go:embed templates
+var templates embed.FS
+
+func NewTemplates() (*mailer.Templates, error) {
+	sub, err := fs.Sub(templates, "templates")
+	if err != nil {
+		return nil, fmt.Errorf("NewTemplates - fs.Sub - %w", err)
+	}
+
+	return mailer.NewTemplates(sub)
+}
@@ -0,0 +1,35 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	AccountStateActive = "active"
+	// AccountStateSuspended аккаунт может читать, но не может ничего изменять.
+	AccountStateSuspended = "suspended"
+	// AccountStateBanned вход запрещён, все сессии и API-ключи отозваны.
+	AccountStateBanned = "banned"
+	// AccountStateShadowBanned аккаунт работает как обычно, но его посты
+	// и комментарии видят только он сам и модераторы.
+	AccountStateShadowBanned = "shadow_banned"
+)
+
+var AccountStates = []string{AccountStateActive, AccountStateSuspended, AccountStateBanned, AccountStateShadowBanned}
+
+const (
+	AuditAccountSuspended    = "account.suspended"
+	AuditAccountBanned       = "account.banned"
+	AuditAccountShadowBanned = "account.shadow_banned"
+	AuditAccountReinstated   = "account.reinstated"
+)
+
+// AccountRestriction новое состояние аккаунта, которое назначает модератор.
+// Until = nil — бессрочно.
+type AccountRestriction struct {
+	State     string
+	Until     *time.Time
+	Reason    string
+	ActorUUID uuid.UUID
+}
@@ -0,0 +1,62 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// Состояния обработки изображения. Файлы других типов не обрабатываются.
+const (
+	ImageStateNone       = "none"
+	ImageStatePending    = "pending"
+	ImageStateProcessing = "processing"
+	ImageStateReady      = "ready"
+	ImageStateFailed     = "failed"
+)
+
+// Варианты изображения помимо превью, которые называются thumb_<размер>.
+const (
+	// VariantOriginal исходное изображение без метаданных, с учётом поворота из EXIF.
+	VariantOriginal = "original"
+	VariantWebP     = "webp"
+)
+
+// Attachment файл, загруженный к посту. Содержимое хранится в blob-хранилище
+// под ключом из SHA256 и общее у всех вложений с одинаковым содержимым.
+type Attachment struct {
+	ID         int64
+	PostID     int64
+	SHA256     string
+	Filename   string
+	Size       int64
+	MimeType   string
+	UploadedBy uuid.UUID
+	CreatedAt  time.Time
+
+	ImageState         string
+	ProcessingAttempts int32
+	Width              int32
+	Height             int32
+	Blurhash           string
+	Variants           []AttachmentVariant
+}
+
+// Variant возвращает вариант изображения по имени.
+func (a Attachment) Variant(name string) (AttachmentVariant, bool) {
+	for _, v := range a.Variants {
+		if v.Name == name {
+			return v, true
+		}
+	}
+	return AttachmentVariant{}, false
+}
+
+// AttachmentVariant производное от изображения, созданное при обработке.
+type AttachmentVariant struct {
+	Name     string
+	SHA256   string
+	Size     int64
+	MimeType string
+	Width    int32
+	Height   int32
+}
@@ -0,0 +1,23 @@
+package model
+
+import "github.com/google/uuid"
+
+const (
+	AuditLoginSucceeded  = "login.succeeded"
+	AuditLoginFailed     = "login.failed"
+	AuditLoginBlocked    = "login.blocked"
+	AuditAccountLocked   = "account.locked"
+	AuditIPLocked        = "ip.locked"
+	AuditAccountUnlocked = "account.unlocked"
+	AuditMFAFailed       = "mfa.failed"
+)
+
+// AuditEvent событие журнала безопасности. UserUUID пуст, если событие
+// не удалось связать с пользователем (например, вход с неизвестным email).
+type AuditEvent struct {
+	Event     string
+	UserUUID  uuid.UUID
+	ActorUUID uuid.UUID
+	IP        string
+	Details   map[string]any
+}
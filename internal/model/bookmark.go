@@ -0,0 +1,23 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type Bookmark struct {
+	UserUUID uuid.UUID
+	PostID   int64
+	// CollectionID 0 — закладка вне коллекций.
+	CollectionID int64
+	CreatedAt    time.Time
+}
+
+// BookmarkCollection именованный список закладок пользователя.
+type BookmarkCollection struct {
+	ID             int64
+	UserUUID       uuid.UUID
+	Name           string
+	BookmarksCount int64
+	CreatedAt      time.Time
+}
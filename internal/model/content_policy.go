@@ -0,0 +1,62 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	// PolicyActionAllow пропускает контент, остальные правила не проверяются.
+	PolicyActionAllow = "allow"
+	// PolicyActionHold публикует контент скрытым и ставит его в очередь модерации.
+	PolicyActionHold = "hold"
+	// PolicyActionReject отклоняет контент.
+	PolicyActionReject = "reject"
+)
+
+var PolicyActions = []string{PolicyActionAllow, PolicyActionHold, PolicyActionReject}
+
+// ReportReasonPolicy причина жалобы, которую создаёт правило с действием hold.
+// Пользователи такую причину указать не могут.
+const ReportReasonPolicy = "policy"
+
+// LinkPattern признак ссылки в тексте. Используется и в Go, и в регулярных
+// выражениях Postgres, поэтому без флагов.
+const LinkPattern = `(https?://|www\.)`
+
+// ContentRule правило контент-политики: CEL-выражение и действие, если оно истинно.
+type ContentRule struct {
+	ID          int64
+	Name        string
+	Description string
+	Expression  string
+	Action      string
+	Priority    int32
+	Enabled     bool
+	CreatedBy   uuid.UUID
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// PolicyContent проверяемый контент. Kind — ReactionTargetPost или ReactionTargetComment.
+type PolicyContent struct {
+	Kind  string
+	Title string
+	Text  string
+}
+
+// AuthorActivity публикации автора за последнее время, включая удалённые.
+type AuthorActivity struct {
+	PostsLastHour    int64
+	PostsLastDay     int64
+	CommentsLastHour int64
+	CommentsLastDay  int64
+	LinksLastHour    int64
+}
+
+// PolicyDecision результат проверки. RuleID = 0, если ни одно правило не сработало.
+type PolicyDecision struct {
+	Action   string
+	RuleID   int64
+	RuleName string
+}
@@ -0,0 +1,35 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	FeedFormatRSS  = "rss"
+	FeedFormatAtom = "atom"
+)
+
+var FeedFormats = []string{FeedFormatRSS, FeedFormatAtom}
+
+// FeedScope какие опубликованные посты попадают в ленту: все, одного автора
+// или с одним тегом.
+type FeedScope struct {
+	AuthorUUID uuid.UUID
+	TagSlug    string
+}
+
+// FeedState поколение содержимого лент: меняется с каждым сигналом
+// feed_state из Postgres.
+type FeedState struct {
+	Generation int64
+	ChangedAt  time.Time
+}
+
+// FeedDocument сформированная лента.
+type FeedDocument struct {
+	Body        []byte
+	ContentType string
+	ETag        string
+	ModifiedAt  time.Time
+}
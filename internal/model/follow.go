@@ -0,0 +1,13 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// Follow подписка FollowerUUID на посты FolloweeUUID.
+type Follow struct {
+	FollowerUUID uuid.UUID
+	FolloweeUUID uuid.UUID
+	CreatedAt    time.Time
+}
@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+const (
+	AuditIdentityLinked  = "identity.linked"
+	AuditIdentityCreated = "identity.created"
+)
+
+// ExternalIdentity учётная запись пользователя у внешнего провайдера входа.
+type ExternalIdentity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// SSOState параметры начатого входа через внешнего провайдера.
+type SSOState struct {
+	Provider     string
+	Nonce        string
+	CodeVerifier string
+	ExpiresAt    time.Time
+}
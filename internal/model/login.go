@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+const (
+	LoginScopeAccount = "account"
+	LoginScopeIP      = "ip"
+)
+
+// LoginFailures счётчик неудачных попыток входа для email или IP.
+type LoginFailures struct {
+	Failures    int
+	Lockouts    int
+	LockedUntil *time.Time
+}
+
+func (f LoginFailures) Locked(now time.Time) bool {
+	return f.LockedUntil != nil && f.LockedUntil.After(now)
+}
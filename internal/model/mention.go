@@ -0,0 +1,10 @@
+package model
+
+import "github.com/google/uuid"
+
+// Mention упоминание @Handle в тексте. Handle в нижнем регистре, UserUUID —
+// пользователь, которому имя принадлежало, когда текст был записан.
+type Mention struct {
+	Handle   string
+	UserUUID uuid.UUID
+}
@@ -0,0 +1,26 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type MFA struct {
+	UserUUID        uuid.UUID
+	SecretEncrypted []byte
+	EnabledAt       *time.Time
+	LastUsedStep    int64
+}
+
+func (m MFA) Enabled() bool {
+	return m.EnabledAt != nil
+}
+
+// LoginResult результат входа. Если у пользователя включена 2FA, вместо
+// токена доступа выдаётся короткоживущий MFAToken для второго шага.
+type LoginResult struct {
+	AccessToken string
+	ExpiresAt   time.Time
+	MFARequired bool
+	MFAToken    string
+}
@@ -0,0 +1,96 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// HiddenCommentPlaceholder показывается читателям вместо текста скрытого
+// модерацией комментария, у которого есть ответы.
+const HiddenCommentPlaceholder = "[hidden]"
+
+const (
+	ReportReasonSpam           = "spam"
+	ReportReasonHarassment     = "harassment"
+	ReportReasonHateSpeech     = "hate_speech"
+	ReportReasonViolence       = "violence"
+	ReportReasonSexualContent  = "sexual_content"
+	ReportReasonMisinformation = "misinformation"
+	ReportReasonOther          = "other"
+)
+
+var ReportReasons = []string{
+	ReportReasonSpam,
+	ReportReasonHarassment,
+	ReportReasonHateSpeech,
+	ReportReasonViolence,
+	ReportReasonSexualContent,
+	ReportReasonMisinformation,
+	ReportReasonOther,
+}
+
+const (
+	// ModerationActionHide убирает контент из публичной выдачи.
+	ModerationActionHide = "hide"
+	// ModerationActionRestore возвращает скрытый контент в выдачу.
+	ModerationActionRestore = "restore"
+	// ModerationActionDelete переносит контент в корзину от имени модератора.
+	ModerationActionDelete = "delete"
+	// ModerationActionWarn предупреждение автору, контент не меняется.
+	ModerationActionWarn = "warn"
+	// ModerationActionDismiss отклоняет жалобы без последствий для контента.
+	ModerationActionDismiss = "dismiss"
+)
+
+var ModerationActions = []string{
+	ModerationActionHide,
+	ModerationActionRestore,
+	ModerationActionDelete,
+	ModerationActionWarn,
+	ModerationActionDismiss,
+}
+
+// ModerationTarget пост или комментарий. Типы те же, что у целей реакций.
+type ModerationTarget struct {
+	Type string
+	ID   int64
+}
+
+type Report struct {
+	ID           int64
+	Target       ModerationTarget
+	ReporterUUID uuid.UUID
+	Reason       string
+	CreatedAt    time.Time
+}
+
+// ModerationItem элемент очереди модерации: контент и сводка по открытым жалобам на него.
+type ModerationItem struct {
+	Target          ModerationTarget
+	AuthorUUID      uuid.UUID
+	Title           string
+	Content         string
+	HiddenAt        *time.Time
+	Reports         int64
+	Reasons         map[string]int64
+	FirstReportedAt time.Time
+	LastReportedAt  time.Time
+}
+
+// ModerationLogEntry запись неизменяемого журнала модерации.
+type ModerationLogEntry struct {
+	ID              int64
+	Target          ModerationTarget
+	Action          string
+	ModeratorUUID   uuid.UUID
+	AuthorUUID      uuid.UUID
+	Note            string
+	ReportsResolved int64
+	CreatedAt       time.Time
+}
+
+// ModerationLogFilter условия выборки журнала. Пустые поля не ограничивают выборку.
+type ModerationLogFilter struct {
+	Target     ModerationTarget
+	AuthorUUID uuid.UUID
+}
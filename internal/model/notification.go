@@ -0,0 +1,30 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	// NotificationReply ответ на комментарий пользователя.
+	NotificationReply = "reply"
+	// NotificationComment комментарий к посту пользователя.
+	NotificationComment = "comment"
+	// NotificationMention упоминание пользователя в посте или комментарии.
+	NotificationMention = "mention"
+	// NotificationFollow новый подписчик.
+	NotificationFollow = "follow"
+)
+
+var NotificationTypes = []string{NotificationReply, NotificationComment, NotificationMention, NotificationFollow}
+
+type Notification struct {
+	ID        int64
+	UserUUID  uuid.UUID
+	Type      string
+	ActorUUID uuid.UUID
+	PostID    int64
+	CommentID int64
+	CreatedAt time.Time
+	ReadAt    *time.Time
+}
@@ -0,0 +1,98 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	PostStatusDraft     = "draft"
+	PostStatusScheduled = "scheduled"
+	PostStatusPublished = "published"
+	PostStatusArchived  = "archived"
+)
+
+var PostStatuses = []string{PostStatusDraft, PostStatusScheduled, PostStatusPublished, PostStatusArchived}
+
+type Post struct {
+	ID      int64
+	Title   string
+	Content string
+	// ContentHTML Content, отрендеренный из Markdown и очищенный версией рендерера ContentHTMLVersion.
+	ContentHTML        string
+	ContentHTMLVersion int32
+	CommentsEnabled    bool
+	AuthorUUID         uuid.UUID
+	CreatedAt          time.Time
+	Status             string
+	PublishAt          *time.Time
+	UnpublishAt        *time.Time
+	PublishedAt        *time.Time
+	Version            int32
+	DeletedAt          *time.Time
+	DeletedBy          uuid.UUID
+	HiddenAt           *time.Time
+	// AuthorShadowBanned автор в теневом бане: пост видят только он сам и модераторы.
+	AuthorShadowBanned bool
+	Tags               []Tag
+	Reactions          ReactionSummary
+	// Mentions пользователи, упомянутые в Content.
+	Mentions []Mention
+	// BookmarkedAt когда читатель добавил пост в закладки, nil — не добавлял.
+	BookmarkedAt *time.Time
+}
+
+// VisibleTo неопубликованные посты (черновики, запланированные, архивные) видит только автор,
+// скрытые модерацией — только модераторы, посты авторов в теневом бане — автор и модераторы.
+func (p Post) VisibleTo(viewer uuid.UUID, moderator bool) bool {
+	own := viewer != uuid.Nil && p.AuthorUUID == viewer
+
+	if p.HiddenAt != nil && !moderator {
+		return false
+	}
+	if p.AuthorShadowBanned && !moderator && !own {
+		return false
+	}
+	return p.Status == PostStatusPublished || own
+}
+
+// PostRevision неизменяемый снимок заголовка и текста поста после правки.
+type PostRevision struct {
+	PostID       int64
+	Version      int32
+	Title        string
+	Content      string
+	EditorUUID   uuid.UUID
+	RestoredFrom int32
+	CreatedAt    time.Time
+}
+
+type Comment struct {
+	ID         int64
+	PostID     int64
+	ParentID   int64
+	AuthorUUID uuid.UUID
+	Content    string
+	// ContentHTML см. Post.ContentHTML.
+	ContentHTML        string
+	ContentHTMLVersion int32
+	HasSubComments     bool
+	CreatedAt          time.Time
+	DeletedAt          *time.Time
+	DeletedBy          uuid.UUID
+	HiddenAt           *time.Time
+	// AuthorShadowBanned автор в теневом бане: комментарий видят только он сам и модераторы.
+	AuthorShadowBanned bool
+	Reactions          ReactionSummary
+	// Mentions см. Post.Mentions.
+	Mentions []Mention
+}
+
+// VisibleTo скрытые модерацией комментарии видят только модераторы,
+// комментарии авторов в теневом бане — автор и модераторы.
+func (c Comment) VisibleTo(viewer uuid.UUID, moderator bool) bool {
+	if moderator {
+		return true
+	}
+	return c.HiddenAt == nil && (!c.AuthorShadowBanned || (viewer != uuid.Nil && c.AuthorUUID == viewer))
+}
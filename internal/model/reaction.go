@@ -0,0 +1,40 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	ReactionTargetPost    = "post"
+	ReactionTargetComment = "comment"
+)
+
+const (
+	ReactionLike       = "like"
+	ReactionLove       = "love"
+	ReactionLaugh      = "laugh"
+	ReactionInsightful = "insightful"
+	ReactionSad        = "sad"
+)
+
+var ReactionKinds = []string{ReactionLike, ReactionLove, ReactionLaugh, ReactionInsightful, ReactionSad}
+
+type ReactionTarget struct {
+	Type string
+	ID   int64
+}
+
+type Reaction struct {
+	UserUUID  uuid.UUID
+	Target    ReactionTarget
+	Kind      string
+	CreatedAt time.Time
+}
+
+// ReactionSummary агрегированные реакции на пост или комментарий.
+// Mine заполняется только для аутентифицированного читателя.
+type ReactionSummary struct {
+	Counts map[string]int64
+	Mine   []string
+}
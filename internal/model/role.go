@@ -0,0 +1,31 @@
+package model
+
+const (
+	RoleAdmin     = "admin"
+	RoleModerator = "moderator"
+	RoleUser      = "user"
+)
+
+const (
+	PermissionPostsCreate        = "posts.create"
+	PermissionPostsUpdateAny     = "posts.update_any"
+	PermissionPostsDeleteAny     = "posts.delete_any"
+	PermissionCommentsCreate     = "comments.create"
+	PermissionCommentsHide       = "comments.hide"
+	PermissionUsersBan           = "users.ban"
+	PermissionRolesManage        = "roles.manage"
+	PermissionAPIKeysManage      = "api_keys.manage"
+	PermissionUsersUnlock        = "users.unlock"
+	PermissionCommentsDeleteAny  = "comments.delete_any"
+	PermissionUsersDelete        = "users.delete"
+	PermissionReportsCreate      = "reports.create"
+	PermissionModerationManage   = "moderation.manage"
+	PermissionContentRulesManage = "content_rules.manage"
+)
+
+// VerifiedOnlyPermissions недоступны пользователям с неподтверждённым email.
+var VerifiedOnlyPermissions = []string{
+	PermissionPostsCreate,
+	PermissionCommentsCreate,
+	PermissionReportsCreate,
+}
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type Tag struct {
+	ID         int64
+	Slug       string
+	Name       string
+	PostsCount int64
+}
+
+// PostFilter условия выборки постов. Пустые поля не ограничивают выборку,
+// кроме Status: без него выбираются только опубликованные посты. Скрытые
+// модерацией посты и посты авторов в теневом бане попадают в выборку только
+// с IncludeHidden, свои посты в теневом бане Viewer видит всегда.
+type PostFilter struct {
+	Status        string
+	TagSlug       string
+	AuthorUUID    uuid.UUID
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	Viewer        uuid.UUID
+	IncludeHidden bool
+}
@@ -0,0 +1,21 @@
+package model
+
+// DeletedCommentPlaceholder показывается вместо текста удалённого комментария,
+// который остаётся в ветке, потому что у него есть ответы.
+const DeletedCommentPlaceholder = "[deleted]"
+
+const (
+	AuditAccountDeleted  = "account.deleted"
+	AuditAccountRestored = "account.restored"
+)
+
+// PurgeResult сколько записей окончательно удалено за один проход очистки корзины.
+type PurgeResult struct {
+	Users    int
+	Posts    int
+	Comments int
+}
+
+func (r PurgeResult) Empty() bool {
+	return r.Users == 0 && r.Posts == 0 && r.Comments == 0
+}
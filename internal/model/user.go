@@ -0,0 +1,46 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	TokenPurposeEmailVerification = "email_verification"
+	TokenPurposePasswordReset     = "password_reset"
+)
+
+type User struct {
+	UUID      uuid.UUID
+	Email     string
+	FirstName string
+	// Username имя для упоминаний и ссылок на профиль, пустое, пока не выбрано.
+	Username          string
+	PasswordHash      string
+	EmailVerifiedAt   *time.Time
+	AccountState      string
+	StateUntil        *time.Time
+	StateReason       string
+	SessionsRevokedAt *time.Time
+	CreatedAt         time.Time
+	Roles             []string
+	Permissions       []string
+}
+
+func (u User) EmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+// State состояние аккаунта на момент now: истёкшее ограничение снимается само.
+func (u User) State(now time.Time) string {
+	if u.AccountState == "" || (u.StateUntil != nil && !now.Before(*u.StateUntil)) {
+		return AccountStateActive
+	}
+	return u.AccountState
+}
+
+// UsernameChange прежнее имя пользователя и время, когда от него отказались.
+type UsernameChange struct {
+	Username  string
+	ChangedAt time.Time
+}
@@ -0,0 +1,368 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.27.3
+// source: baseProject/post/bookmark.proto
+
+package post
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	BookmarkService_AddBookmark_FullMethodName              = "/post.BookmarkService/AddBookmark"
+	BookmarkService_MoveBookmark_FullMethodName             = "/post.BookmarkService/MoveBookmark"
+	BookmarkService_RemoveBookmark_FullMethodName           = "/post.BookmarkService/RemoveBookmark"
+	BookmarkService_CreateBookmarkCollection_FullMethodName = "/post.BookmarkService/CreateBookmarkCollection"
+	BookmarkService_RenameBookmarkCollection_FullMethodName = "/post.BookmarkService/RenameBookmarkCollection"
+	BookmarkService_DeleteBookmarkCollection_FullMethodName = "/post.BookmarkService/DeleteBookmarkCollection"
+	BookmarkService_ListBookmarkCollections_FullMethodName  = "/post.BookmarkService/ListBookmarkCollections"
+)
+
+// BookmarkServiceClient is the client API for BookmarkService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type BookmarkServiceClient interface {
+	// AddBookmark добавление поста в закладки. Пост, который уже в закладках,
+	// переносится в указанную коллекцию
+	AddBookmark(ctx context.Context, in *AddBookmarkRequest, opts ...grpc.CallOption) (*Bookmark, error)
+	// MoveBookmark перенос закладки в другую коллекцию
+	MoveBookmark(ctx context.Context, in *MoveBookmarkRequest, opts ...grpc.CallOption) (*Bookmark, error)
+	// RemoveBookmark удаление поста из закладок. Удаление отсутствующей закладки
+	// не считается ошибкой
+	RemoveBookmark(ctx context.Context, in *RemoveBookmarkRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// CreateBookmarkCollection новая коллекция закладок
+	CreateBookmarkCollection(ctx context.Context, in *CreateBookmarkCollectionRequest, opts ...grpc.CallOption) (*BookmarkCollection, error)
+	// RenameBookmarkCollection переименование коллекции
+	RenameBookmarkCollection(ctx context.Context, in *RenameBookmarkCollectionRequest, opts ...grpc.CallOption) (*BookmarkCollection, error)
+	// DeleteBookmarkCollection удаление коллекции, её закладки остаются вне коллекций
+	DeleteBookmarkCollection(ctx context.Context, in *DeleteBookmarkCollectionRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// ListBookmarkCollections коллекции текущего пользователя по имени
+	ListBookmarkCollections(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListBookmarkCollectionsResponse, error)
+}
+
+type bookmarkServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBookmarkServiceClient(cc grpc.ClientConnInterface) BookmarkServiceClient {
+	return &bookmarkServiceClient{cc}
+}
+
+func (c *bookmarkServiceClient) AddBookmark(ctx context.Context, in *AddBookmarkRequest, opts ...grpc.CallOption) (*Bookmark, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Bookmark)
+	err := c.cc.Invoke(ctx, BookmarkService_AddBookmark_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookmarkServiceClient) MoveBookmark(ctx context.Context, in *MoveBookmarkRequest, opts ...grpc.CallOption) (*Bookmark, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Bookmark)
+	err := c.cc.Invoke(ctx, BookmarkService_MoveBookmark_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookmarkServiceClient) RemoveBookmark(ctx context.Context, in *RemoveBookmarkRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, BookmarkService_RemoveBookmark_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookmarkServiceClient) CreateBookmarkCollection(ctx context.Context, in *CreateBookmarkCollectionRequest, opts ...grpc.CallOption) (*BookmarkCollection, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BookmarkCollection)
+	err := c.cc.Invoke(ctx, BookmarkService_CreateBookmarkCollection_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookmarkServiceClient) RenameBookmarkCollection(ctx context.Context, in *RenameBookmarkCollectionRequest, opts ...grpc.CallOption) (*BookmarkCollection, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BookmarkCollection)
+	err := c.cc.Invoke(ctx, BookmarkService_RenameBookmarkCollection_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookmarkServiceClient) DeleteBookmarkCollection(ctx context.Context, in *DeleteBookmarkCollectionRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, BookmarkService_DeleteBookmarkCollection_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookmarkServiceClient) ListBookmarkCollections(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListBookmarkCollectionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListBookmarkCollectionsResponse)
+	err := c.cc.Invoke(ctx, BookmarkService_ListBookmarkCollections_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BookmarkServiceServer is the server API for BookmarkService service.
+// All implementations must embed UnimplementedBookmarkServiceServer
+// for forward compatibility.
+type BookmarkServiceServer interface {
+	// AddBookmark добавление поста в закладки. Пост, который уже в закладках,
+	// переносится в указанную коллекцию
+	AddBookmark(context.Context, *AddBookmarkRequest) (*Bookmark, error)
+	// MoveBookmark перенос закладки в другую коллекцию
+	MoveBookmark(context.Context, *MoveBookmarkRequest) (*Bookmark, error)
+	// RemoveBookmark удаление поста из закладок. Удаление отсутствующей закладки
+	// не считается ошибкой
+	RemoveBookmark(context.Context, *RemoveBookmarkRequest) (*emptypb.Empty, error)
+	// CreateBookmarkCollection новая коллекция закладок
+	CreateBookmarkCollection(context.Context, *CreateBookmarkCollectionRequest) (*BookmarkCollection, error)
+	// RenameBookmarkCollection переименование коллекции
+	RenameBookmarkCollection(context.Context, *RenameBookmarkCollectionRequest) (*BookmarkCollection, error)
+	// DeleteBookmarkCollection удаление коллекции, её закладки остаются вне коллекций
+	DeleteBookmarkCollection(context.Context, *DeleteBookmarkCollectionRequest) (*emptypb.Empty, error)
+	// ListBookmarkCollections коллекции текущего пользователя по имени
+	ListBookmarkCollections(context.Context, *emptypb.Empty) (*ListBookmarkCollectionsResponse, error)
+	mustEmbedUnimplementedBookmarkServiceServer()
+}
+
+// UnimplementedBookmarkServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedBookmarkServiceServer struct{}
+
+func (UnimplementedBookmarkServiceServer) AddBookmark(context.Context, *AddBookmarkRequest) (*Bookmark, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddBookmark not implemented")
+}
+func (UnimplementedBookmarkServiceServer) MoveBookmark(context.Context, *MoveBookmarkRequest) (*Bookmark, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MoveBookmark not implemented")
+}
+func (UnimplementedBookmarkServiceServer) RemoveBookmark(context.Context, *RemoveBookmarkRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveBookmark not implemented")
+}
+func (UnimplementedBookmarkServiceServer) CreateBookmarkCollection(context.Context, *CreateBookmarkCollectionRequest) (*BookmarkCollection, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateBookmarkCollection not implemented")
+}
+func (UnimplementedBookmarkServiceServer) RenameBookmarkCollection(context.Context, *RenameBookmarkCollectionRequest) (*BookmarkCollection, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenameBookmarkCollection not implemented")
+}
+func (UnimplementedBookmarkServiceServer) DeleteBookmarkCollection(context.Context, *DeleteBookmarkCollectionRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteBookmarkCollection not implemented")
+}
+func (UnimplementedBookmarkServiceServer) ListBookmarkCollections(context.Context, *emptypb.Empty) (*ListBookmarkCollectionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBookmarkCollections not implemented")
+}
+func (UnimplementedBookmarkServiceServer) mustEmbedUnimplementedBookmarkServiceServer() {}
+func (UnimplementedBookmarkServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafeBookmarkServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BookmarkServiceServer will
+// result in compilation errors.
+type UnsafeBookmarkServiceServer interface {
+	mustEmbedUnimplementedBookmarkServiceServer()
+}
+
+func RegisterBookmarkServiceServer(s grpc.ServiceRegistrar, srv BookmarkServiceServer) {
+	// If the following call pancis, it indicates UnimplementedBookmarkServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&BookmarkService_ServiceDesc, srv)
+}
+
+func _BookmarkService_AddBookmark_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddBookmarkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookmarkServiceServer).AddBookmark(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookmarkService_AddBookmark_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookmarkServiceServer).AddBookmark(ctx, req.(*AddBookmarkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookmarkService_MoveBookmark_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MoveBookmarkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookmarkServiceServer).MoveBookmark(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookmarkService_MoveBookmark_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookmarkServiceServer).MoveBookmark(ctx, req.(*MoveBookmarkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookmarkService_RemoveBookmark_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveBookmarkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookmarkServiceServer).RemoveBookmark(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookmarkService_RemoveBookmark_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookmarkServiceServer).RemoveBookmark(ctx, req.(*RemoveBookmarkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookmarkService_CreateBookmarkCollection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateBookmarkCollectionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookmarkServiceServer).CreateBookmarkCollection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookmarkService_CreateBookmarkCollection_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookmarkServiceServer).CreateBookmarkCollection(ctx, req.(*CreateBookmarkCollectionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookmarkService_RenameBookmarkCollection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenameBookmarkCollectionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookmarkServiceServer).RenameBookmarkCollection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookmarkService_RenameBookmarkCollection_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookmarkServiceServer).RenameBookmarkCollection(ctx, req.(*RenameBookmarkCollectionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookmarkService_DeleteBookmarkCollection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteBookmarkCollectionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookmarkServiceServer).DeleteBookmarkCollection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookmarkService_DeleteBookmarkCollection_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookmarkServiceServer).DeleteBookmarkCollection(ctx, req.(*DeleteBookmarkCollectionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookmarkService_ListBookmarkCollections_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookmarkServiceServer).ListBookmarkCollections(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookmarkService_ListBookmarkCollections_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookmarkServiceServer).ListBookmarkCollections(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// BookmarkService_ServiceDesc is the grpc.ServiceDesc for BookmarkService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var BookmarkService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "post.BookmarkService",
+	HandlerType: (*BookmarkServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "AddBookmark",
+			Handler:    _BookmarkService_AddBookmark_Handler,
+		},
+		{
+			MethodName: "MoveBookmark",
+			Handler:    _BookmarkService_MoveBookmark_Handler,
+		},
+		{
+			MethodName: "RemoveBookmark",
+			Handler:    _BookmarkService_RemoveBookmark_Handler,
+		},
+		{
+			MethodName: "CreateBookmarkCollection",
+			Handler:    _BookmarkService_CreateBookmarkCollection_Handler,
+		},
+		{
+			MethodName: "RenameBookmarkCollection",
+			Handler:    _BookmarkService_RenameBookmarkCollection_Handler,
+		},
+		{
+			MethodName: "DeleteBookmarkCollection",
+			Handler:    _BookmarkService_DeleteBookmarkCollection_Handler,
+		},
+		{
+			MethodName: "ListBookmarkCollections",
+			Handler:    _BookmarkService_ListBookmarkCollections_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "baseProject/post/bookmark.proto",
+}
@@ -0,0 +1,266 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.27.3
+// source: baseProject/post/content_policy.proto
+
+package post
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ContentPolicyService_ListContentRules_FullMethodName  = "/post.ContentPolicyService/ListContentRules"
+	ContentPolicyService_CreateContentRule_FullMethodName = "/post.ContentPolicyService/CreateContentRule"
+	ContentPolicyService_UpdateContentRule_FullMethodName = "/post.ContentPolicyService/UpdateContentRule"
+	ContentPolicyService_DeleteContentRule_FullMethodName = "/post.ContentPolicyService/DeleteContentRule"
+)
+
+// ContentPolicyServiceClient is the client API for ContentPolicyService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// ContentPolicyService правила проверки новых постов и комментариев. Правило —
+// CEL-выражение над переменными:
+//
+//	author      — map: uuid, roles, email_verified, account_state, api_key
+//	account_age — duration с регистрации автора
+//	content     — map: kind ("post", "comment"), title, text, length, links, uppercase_ratio
+//	activity    — map: posts_last_hour, posts_last_day, comments_last_hour,
+//	              comments_last_day, links_last_hour
+//
+// Например: account_age < duration("24h") && activity.links_last_hour + content.links > 3
+type ContentPolicyServiceClient interface {
+	// ListContentRules все правила в порядке проверки
+	ListContentRules(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListContentRulesResponse, error)
+	// CreateContentRule создание правила. Выражение проверяется при сохранении
+	CreateContentRule(ctx context.Context, in *ContentRuleInput, opts ...grpc.CallOption) (*ContentRule, error)
+	// UpdateContentRule замена правила целиком
+	UpdateContentRule(ctx context.Context, in *UpdateContentRuleRequest, opts ...grpc.CallOption) (*ContentRule, error)
+	// DeleteContentRule удаление правила
+	DeleteContentRule(ctx context.Context, in *DeleteContentRuleRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+}
+
+type contentPolicyServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewContentPolicyServiceClient(cc grpc.ClientConnInterface) ContentPolicyServiceClient {
+	return &contentPolicyServiceClient{cc}
+}
+
+func (c *contentPolicyServiceClient) ListContentRules(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListContentRulesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListContentRulesResponse)
+	err := c.cc.Invoke(ctx, ContentPolicyService_ListContentRules_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *contentPolicyServiceClient) CreateContentRule(ctx context.Context, in *ContentRuleInput, opts ...grpc.CallOption) (*ContentRule, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ContentRule)
+	err := c.cc.Invoke(ctx, ContentPolicyService_CreateContentRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *contentPolicyServiceClient) UpdateContentRule(ctx context.Context, in *UpdateContentRuleRequest, opts ...grpc.CallOption) (*ContentRule, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ContentRule)
+	err := c.cc.Invoke(ctx, ContentPolicyService_UpdateContentRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *contentPolicyServiceClient) DeleteContentRule(ctx context.Context, in *DeleteContentRuleRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, ContentPolicyService_DeleteContentRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ContentPolicyServiceServer is the server API for ContentPolicyService service.
+// All implementations must embed UnimplementedContentPolicyServiceServer
+// for forward compatibility.
+//
+// ContentPolicyService правила проверки новых постов и комментариев. Правило —
+// CEL-выражение над переменными:
+//
+//	author      — map: uuid, roles, email_verified, account_state, api_key
+//	account_age — duration с регистрации автора
+//	content     — map: kind ("post", "comment"), title, text, length, links, uppercase_ratio
+//	activity    — map: posts_last_hour, posts_last_day, comments_last_hour,
+//	              comments_last_day, links_last_hour
+//
+// Например: account_age < duration("24h") && activity.links_last_hour + content.links > 3
+type ContentPolicyServiceServer interface {
+	// ListContentRules все правила в порядке проверки
+	ListContentRules(context.Context, *emptypb.Empty) (*ListContentRulesResponse, error)
+	// CreateContentRule создание правила. Выражение проверяется при сохранении
+	CreateContentRule(context.Context, *ContentRuleInput) (*ContentRule, error)
+	// UpdateContentRule замена правила целиком
+	UpdateContentRule(context.Context, *UpdateContentRuleRequest) (*ContentRule, error)
+	// DeleteContentRule удаление правила
+	DeleteContentRule(context.Context, *DeleteContentRuleRequest) (*emptypb.Empty, error)
+	mustEmbedUnimplementedContentPolicyServiceServer()
+}
+
+// UnimplementedContentPolicyServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedContentPolicyServiceServer struct{}
+
+func (UnimplementedContentPolicyServiceServer) ListContentRules(context.Context, *emptypb.Empty) (*ListContentRulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListContentRules not implemented")
+}
+func (UnimplementedContentPolicyServiceServer) CreateContentRule(context.Context, *ContentRuleInput) (*ContentRule, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateContentRule not implemented")
+}
+func (UnimplementedContentPolicyServiceServer) UpdateContentRule(context.Context, *UpdateContentRuleRequest) (*ContentRule, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateContentRule not implemented")
+}
+func (UnimplementedContentPolicyServiceServer) DeleteContentRule(context.Context, *DeleteContentRuleRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteContentRule not implemented")
+}
+func (UnimplementedContentPolicyServiceServer) mustEmbedUnimplementedContentPolicyServiceServer() {}
+func (UnimplementedContentPolicyServiceServer) testEmbeddedByValue()                              {}
+
+// UnsafeContentPolicyServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ContentPolicyServiceServer will
+// result in compilation errors.
+type UnsafeContentPolicyServiceServer interface {
+	mustEmbedUnimplementedContentPolicyServiceServer()
+}
+
+func RegisterContentPolicyServiceServer(s grpc.ServiceRegistrar, srv ContentPolicyServiceServer) {
+	// If the following call pancis, it indicates UnimplementedContentPolicyServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ContentPolicyService_ServiceDesc, srv)
+}
+
+func _ContentPolicyService_ListContentRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContentPolicyServiceServer).ListContentRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ContentPolicyService_ListContentRules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContentPolicyServiceServer).ListContentRules(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ContentPolicyService_CreateContentRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ContentRuleInput)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContentPolicyServiceServer).CreateContentRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ContentPolicyService_CreateContentRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContentPolicyServiceServer).CreateContentRule(ctx, req.(*ContentRuleInput))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ContentPolicyService_UpdateContentRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateContentRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContentPolicyServiceServer).UpdateContentRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ContentPolicyService_UpdateContentRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContentPolicyServiceServer).UpdateContentRule(ctx, req.(*UpdateContentRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ContentPolicyService_DeleteContentRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteContentRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContentPolicyServiceServer).DeleteContentRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ContentPolicyService_DeleteContentRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContentPolicyServiceServer).DeleteContentRule(ctx, req.(*DeleteContentRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ContentPolicyService_ServiceDesc is the grpc.ServiceDesc for ContentPolicyService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ContentPolicyService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "post.ContentPolicyService",
+	HandlerType: (*ContentPolicyServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListContentRules",
+			Handler:    _ContentPolicyService_ListContentRules_Handler,
+		},
+		{
+			MethodName: "CreateContentRule",
+			Handler:    _ContentPolicyService_CreateContentRule_Handler,
+		},
+		{
+			MethodName: "UpdateContentRule",
+			Handler:    _ContentPolicyService_UpdateContentRule_Handler,
+		},
+		{
+			MethodName: "DeleteContentRule",
+			Handler:    _ContentPolicyService_DeleteContentRule_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "baseProject/post/content_policy.proto",
+}
@@ -0,0 +1,209 @@
+package apikey_service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/AdilBaidual/baseProject/internal/auth"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	keyPrefix       = "bpk"
+	prefixBytes     = 6
+	secretBytes     = 32
+	maxNameLength   = 255
+	keyPartsCount   = 3
+	keyPartsDivider = "_"
+)
+
+type apiKeyStore interface {
+	CreateAPIKey(ctx context.Context, key model.APIKey) (model.APIKey, error)
+	GetAPIKeyByPrefix(ctx context.Context, prefix string) (model.APIKey, error)
+	ListAPIKeys(ctx context.Context, userUUID uuid.UUID) ([]model.APIKey, error)
+	RevokeAPIKey(ctx context.Context, userUUID, id uuid.UUID) error
+	TouchAPIKey(ctx context.Context, id uuid.UUID) error
+	GetUserByUUID(ctx context.Context, id uuid.UUID) (model.User, error)
+	GetUserRoles(ctx context.Context, userUUID uuid.UUID) ([]string, error)
+	GetUserPermissions(ctx context.Context, userUUID uuid.UUID) ([]string, error)
+}
+
+type Service struct {
+	logger *zap.Logger
+
+	apiKeyStore apiKeyStore
+}
+
+func NewService(logger *zap.Logger, apiKeyStore apiKeyStore) *Service {
+	return &Service{
+		logger:      logger,
+		apiKeyStore: apiKeyStore,
+	}
+}
+
+// Create выпускает ключ вида bpk_<prefix>_<secret>. В базе хранится только
+// SHA-256 от ключа, поэтому значение возвращается вызывающему единственный раз.
+func (s *Service) Create(ctx context.Context, owner auth.Identity, name string, scopes []string, ttl time.Duration) (model.APIKey, string, error) {
+	if owner.ViaAPIKey() {
+		return model.APIKey{}, "", fmt.Errorf("%w: api keys cannot issue other api keys", model.ErrPermissionDenied)
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > maxNameLength {
+		return model.APIKey{}, "", fmt.Errorf("%w: name must be 1-%d characters", model.ErrInvalidArgument, maxNameLength)
+	}
+
+	if len(scopes) == 0 {
+		return model.APIKey{}, "", fmt.Errorf("%w: at least one scope is required", model.ErrInvalidArgument)
+	}
+	for _, scope := range scopes {
+		if !owner.HasPermission(scope) {
+			return model.APIKey{}, "", fmt.Errorf("%w: scope %q exceeds owner permissions", model.ErrPermissionDenied, scope)
+		}
+	}
+
+	if ttl < 0 {
+		return model.APIKey{}, "", fmt.Errorf("%w: ttl must be positive", model.ErrInvalidArgument)
+	}
+
+	prefix, err := randomString(prefixBytes, hex.EncodeToString)
+	if err != nil {
+		return model.APIKey{}, "", err
+	}
+
+	secret, err := randomString(secretBytes, base64.RawURLEncoding.EncodeToString)
+	if err != nil {
+		return model.APIKey{}, "", err
+	}
+
+	raw := strings.Join([]string{keyPrefix, prefix, secret}, keyPartsDivider)
+
+	scopes = slices.Clone(scopes)
+	slices.Sort(scopes)
+
+	key := model.APIKey{
+		UserUUID: owner.UserUUID,
+		Name:     name,
+		Prefix:   prefix,
+		KeyHash:  hashKey(raw),
+		Scopes:   slices.Compact(scopes),
+	}
+	if ttl > 0 {
+		expiresAt := time.Now().Add(ttl)
+		key.ExpiresAt = &expiresAt
+	}
+
+	created, err := s.apiKeyStore.CreateAPIKey(ctx, key)
+	if err != nil {
+		return model.APIKey{}, "", err
+	}
+
+	return created, raw, nil
+}
+
+func (s *Service) List(ctx context.Context, userUUID uuid.UUID) ([]model.APIKey, error) {
+	return s.apiKeyStore.ListAPIKeys(ctx, userUUID)
+}
+
+func (s *Service) Revoke(ctx context.Context, userUUID, id uuid.UUID) error {
+	return s.apiKeyStore.RevokeAPIKey(ctx, userUUID, id)
+}
+
+// Authenticate находит ключ по префиксу и сверяет хеш за постоянное время.
+// Права запроса — пересечение scope ключа с текущими правами владельца.
+func (s *Service) Authenticate(ctx context.Context, raw string) (auth.Identity, error) {
+	// Секрет в base64url может содержать разделитель, поэтому делится только префикс.
+	parts := strings.SplitN(raw, keyPartsDivider, keyPartsCount)
+	if len(parts) != keyPartsCount || parts[0] != keyPrefix {
+		return auth.Identity{}, fmt.Errorf("%w: malformed api key", model.ErrUnauthenticated)
+	}
+
+	key, err := s.apiKeyStore.GetAPIKeyByPrefix(ctx, parts[1])
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return auth.Identity{}, model.ErrUnauthenticated
+		}
+		return auth.Identity{}, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(key.KeyHash), []byte(hashKey(raw))) != 1 {
+		return auth.Identity{}, model.ErrUnauthenticated
+	}
+
+	if key.RevokedAt != nil || (key.ExpiresAt != nil && time.Now().After(*key.ExpiresAt)) {
+		return auth.Identity{}, fmt.Errorf("%w: api key revoked or expired", model.ErrUnauthenticated)
+	}
+
+	owner, err := s.apiKeyStore.GetUserByUUID(ctx, key.UserUUID)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return auth.Identity{}, model.ErrUnauthenticated
+		}
+		return auth.Identity{}, err
+	}
+
+	// Ключи отзываются при бане, проверка страхует от ключа, созданного одновременно с баном.
+	state := owner.State(time.Now())
+	if state == model.AccountStateBanned {
+		return auth.Identity{}, fmt.Errorf("%w: account is banned", model.ErrUnauthenticated)
+	}
+
+	roles, err := s.apiKeyStore.GetUserRoles(ctx, key.UserUUID)
+	if err != nil {
+		return auth.Identity{}, err
+	}
+
+	ownerPermissions, err := s.apiKeyStore.GetUserPermissions(ctx, key.UserUUID)
+	if err != nil {
+		return auth.Identity{}, err
+	}
+
+	if !owner.EmailVerified() {
+		ownerPermissions = auth.RestrictUnverified(ownerPermissions)
+	}
+
+	permissions := make([]string, 0, len(key.Scopes))
+	for _, scope := range key.Scopes {
+		if slices.Contains(ownerPermissions, scope) {
+			permissions = append(permissions, scope)
+		}
+	}
+
+	if err = s.apiKeyStore.TouchAPIKey(ctx, key.ID); err != nil {
+		s.logger.Warn("failed to update api key last usage", zap.Error(err), zap.String("api_key_id", key.ID.String()))
+	}
+
+	return auth.Identity{
+		UserUUID:      key.UserUUID,
+		APIKeyID:      key.ID,
+		EmailVerified: owner.EmailVerified(),
+		Roles:         roles,
+		Permissions:   permissions,
+		AccountState:  state,
+		StateUntil:    owner.StateUntil,
+	}, nil
+}
+
+func hashKey(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomString(n int, encode func([]byte) string) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("randomString - rand.Read - %w", err)
+	}
+
+	return encode(b), nil
+}
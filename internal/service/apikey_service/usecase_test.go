@@ -0,0 +1,155 @@
+package apikey_service
+
+import (
+	"context"
+	"errors"
+	"github.com/AdilBaidual/baseProject/internal/auth"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"strings"
+	"testing"
+	"time"
+)
+
+// memoryStore хранит ключи в памяти; владелец один, с подтверждённой почтой.
+type memoryStore struct {
+	owner       model.User
+	permissions []string
+	keys        map[string]model.APIKey
+}
+
+func newMemoryStore(owner model.User, permissions []string) *memoryStore {
+	return &memoryStore{owner: owner, permissions: permissions, keys: make(map[string]model.APIKey)}
+}
+
+func (m *memoryStore) CreateAPIKey(_ context.Context, key model.APIKey) (model.APIKey, error) {
+	key.ID = uuid.New()
+	key.CreatedAt = time.Now()
+	m.keys[key.Prefix] = key
+	return key, nil
+}
+
+func (m *memoryStore) GetAPIKeyByPrefix(_ context.Context, prefix string) (model.APIKey, error) {
+	key, ok := m.keys[prefix]
+	if !ok {
+		return model.APIKey{}, model.ErrNotFound
+	}
+	return key, nil
+}
+
+func (m *memoryStore) ListAPIKeys(context.Context, uuid.UUID) ([]model.APIKey, error) {
+	return nil, nil
+}
+
+func (m *memoryStore) RevokeAPIKey(_ context.Context, _, id uuid.UUID) error {
+	for prefix, key := range m.keys {
+		if key.ID == id {
+			now := time.Now()
+			key.RevokedAt = &now
+			m.keys[prefix] = key
+			return nil
+		}
+	}
+	return model.ErrNotFound
+}
+
+func (m *memoryStore) TouchAPIKey(context.Context, uuid.UUID) error {
+	return nil
+}
+
+func (m *memoryStore) GetUserByUUID(_ context.Context, id uuid.UUID) (model.User, error) {
+	if id != m.owner.UUID {
+		return model.User{}, model.ErrNotFound
+	}
+	return m.owner, nil
+}
+
+func (m *memoryStore) GetUserRoles(context.Context, uuid.UUID) ([]string, error) {
+	return []string{model.RoleUser}, nil
+}
+
+func (m *memoryStore) GetUserPermissions(context.Context, uuid.UUID) ([]string, error) {
+	return m.permissions, nil
+}
+
+func TestCreateAuthenticateRoundTrip(t *testing.T) {
+	verifiedAt := time.Now()
+	owner := model.User{UUID: uuid.New(), EmailVerifiedAt: &verifiedAt}
+	permissions := []string{"posts.create", "posts.read"}
+
+	store := newMemoryStore(owner, permissions)
+	service := NewService(zap.NewNop(), store)
+	identity := auth.Identity{UserUUID: owner.UUID, Permissions: permissions}
+
+	// Секрет случаен, поэтому ключей выпускается достаточно, чтобы среди них
+	// наверняка нашлись секреты с "_" и "-".
+	var withDivider int
+	for i := 0; i < 200; i++ {
+		created, raw, err := service.Create(context.Background(), identity, "ci", []string{"posts.read"}, 0)
+		if err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if strings.Count(raw, keyPartsDivider) > 2 {
+			withDivider++
+		}
+
+		got, err := service.Authenticate(context.Background(), raw)
+		if err != nil {
+			t.Fatalf("Authenticate(%q): %v", raw, err)
+		}
+		if got.UserUUID != owner.UUID || got.APIKeyID != created.ID {
+			t.Fatalf("Authenticate(%q) = %+v, want key %s of %s", raw, got, created.ID, owner.UUID)
+		}
+		if len(got.Permissions) != 1 || got.Permissions[0] != "posts.read" {
+			t.Fatalf("Authenticate(%q) permissions = %v, want [posts.read]", raw, got.Permissions)
+		}
+	}
+
+	if withDivider == 0 {
+		t.Fatal("no generated secret contained the divider")
+	}
+}
+
+func TestAuthenticateRejects(t *testing.T) {
+	verifiedAt := time.Now()
+	owner := model.User{UUID: uuid.New(), EmailVerifiedAt: &verifiedAt}
+	permissions := []string{"posts.read"}
+
+	store := newMemoryStore(owner, permissions)
+	service := NewService(zap.NewNop(), store)
+	identity := auth.Identity{UserUUID: owner.UUID, Permissions: permissions}
+
+	created, raw, err := service.Create(context.Background(), identity, "ci", permissions, 0)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	revoked, revokedRaw, err := service.Create(context.Background(), identity, "revoked", permissions, 0)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err = service.Revoke(context.Background(), owner.UUID, revoked.ID); err != nil {
+		t.Fatalf("Revoke: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		raw  string
+	}{
+		{name: "empty", raw: ""},
+		{name: "foreign prefix", raw: strings.Replace(raw, keyPrefix, "xyz", 1)},
+		{name: "missing secret", raw: keyPrefix + keyPartsDivider + created.Prefix},
+		{name: "unknown prefix", raw: keyPrefix + "_000000000000_" + strings.SplitN(raw, keyPartsDivider, keyPartsCount)[2]},
+		{name: "wrong secret", raw: raw + "x"},
+		{name: "revoked", raw: revokedRaw},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := service.Authenticate(context.Background(), tc.raw); !errors.Is(err, model.ErrUnauthenticated) {
+				t.Errorf("Authenticate(%q) error = %v, want ErrUnauthenticated", tc.raw, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package attachment_service
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+)
+
+var errGIFFormat = errors.New("gif: malformed data")
+
+// gifPixels суммирует площади кадров GIF по заголовкам, не распаковывая
+// их: gif.DecodeAll держит в памяти все кадры сразу, и маленький файл с
+// тысячами кадров распаковывается в гигабайты.
+func gifPixels(data []byte) (frames, pixels int, err error) {
+	r := bufio.NewReader(bytes.NewReader(data))
+
+	header := make([]byte, 13)
+	if _, err = io.ReadFull(r, header); err != nil {
+		return 0, 0, errGIFFormat
+	}
+	if packed := header[10]; packed&0x80 != 0 {
+		if _, err = r.Discard(3 << (packed&0x07 + 1)); err != nil {
+			return 0, 0, errGIFFormat
+		}
+	}
+
+	for {
+		block, err := r.ReadByte()
+		if err != nil {
+			return 0, 0, errGIFFormat
+		}
+
+		switch block {
+		case 0x21: // расширение: метка и подблоки
+			if _, err = r.ReadByte(); err != nil {
+				return 0, 0, errGIFFormat
+			}
+		case 0x2C: // кадр: дескриптор, палитра, размер кода LZW и подблоки
+			descriptor := make([]byte, 9)
+			if _, err = io.ReadFull(r, descriptor); err != nil {
+				return 0, 0, errGIFFormat
+			}
+
+			width := int(descriptor[4]) | int(descriptor[5])<<8
+			height := int(descriptor[6]) | int(descriptor[7])<<8
+			frames++
+			pixels += width * height
+
+			if packed := descriptor[8]; packed&0x80 != 0 {
+				if _, err = r.Discard(3 << (packed&0x07 + 1)); err != nil {
+					return 0, 0, errGIFFormat
+				}
+			}
+			if _, err = r.ReadByte(); err != nil {
+				return 0, 0, errGIFFormat
+			}
+		case 0x3B: // конец файла
+			return frames, pixels, nil
+		default:
+			return 0, 0, errGIFFormat
+		}
+
+		if err = skipGIFSubBlocks(r); err != nil {
+			return 0, 0, err
+		}
+	}
+}
+
+func skipGIFSubBlocks(r *bufio.Reader) error {
+	for {
+		size, err := r.ReadByte()
+		if err != nil {
+			return errGIFFormat
+		}
+		if size == 0 {
+			return nil
+		}
+		if _, err = r.Discard(int(size)); err != nil {
+			return errGIFFormat
+		}
+	}
+}
@@ -0,0 +1,328 @@
+package attachment_service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/AdilBaidual/baseProject/pkg/blurhash"
+	"github.com/AdilBaidual/baseProject/pkg/webp"
+	"github.com/disintegration/imaging"
+	"go.uber.org/zap"
+	_ "golang.org/x/image/webp"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"strconv"
+	"time"
+)
+
+// imageTypes типы, которые обрабатываются как изображения.
+var imageTypes = []string{"image/png", "image/jpeg", "image/gif", "image/webp"}
+
+// errUnsupportedImage ошибка в самом файле: повторная обработка не поможет.
+var errUnsupportedImage = errors.New("unsupported image")
+
+type ImageConfig struct {
+	// Interval период, с которым обработчик забирает изображения из очереди.
+	Interval time.Duration `yaml:"interval"`
+	// Batch число изображений, забираемых за раз.
+	Batch int `yaml:"batch"`
+	// Timeout через сколько незавершённую обработку забирает другой экземпляр.
+	// Должен быть заметно больше времени обработки одного изображения.
+	Timeout time.Duration `yaml:"timeout"`
+	// MaxAttempts число попыток обработки, после которого изображение
+	// считается необрабатываемым.
+	MaxAttempts int `yaml:"max_attempts"`
+	// MaxPixels предельное число пикселей: изображение целиком распаковывается
+	// в память, маленький файл может оказаться огромной картинкой. Для GIF
+	// считаются пиксели всех кадров.
+	MaxPixels int `yaml:"max_pixels"`
+	// ThumbnailSizes наибольшие стороны превью. Превью не больше исходного
+	// изображения не создаются.
+	ThumbnailSizes []int `yaml:"thumbnail_sizes"`
+	// WebPSize наибольшая сторона варианта WebP. WebP кодируется без потерь.
+	WebPSize int `yaml:"webp_size"`
+	// JPEGQuality качество JPEG для оригинала без метаданных и превью.
+	JPEGQuality int `yaml:"jpeg_quality"`
+}
+
+func (c ImageConfig) withDefaults() ImageConfig {
+	if c.Interval <= 0 {
+		c.Interval = 5 * time.Second
+	}
+	if c.Batch <= 0 {
+		c.Batch = 10
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = 10 * time.Minute
+	}
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = 3
+	}
+	if c.MaxPixels <= 0 {
+		c.MaxPixels = 25_000_000
+	}
+	if len(c.ThumbnailSizes) == 0 {
+		c.ThumbnailSizes = []int{160, 480, 1080}
+	}
+	if c.WebPSize <= 0 {
+		c.WebPSize = 1080
+	}
+	if c.JPEGQuality <= 0 || c.JPEGQuality > 100 {
+		c.JPEGQuality = 85
+	}
+	return c
+}
+
+// ImageInterval период вызова ProcessImages.
+func (s *Service) ImageInterval() time.Duration {
+	return s.cfg.Images.Interval
+}
+
+// ProcessImages обрабатывает изображения из очереди, пока она не опустеет.
+// Экземпляры делят очередь через блокировки строк.
+func (s *Service) ProcessImages(ctx context.Context) error {
+	for {
+		claimed, err := s.attachmentStore.ClaimImages(ctx, time.Now().Add(-s.cfg.Images.Timeout), s.cfg.Images.Batch)
+		if err != nil {
+			return err
+		}
+
+		for _, attachment := range claimed {
+			if err = s.processImage(ctx, attachment); err != nil {
+				return err
+			}
+		}
+
+		if len(claimed) < s.cfg.Images.Batch {
+			return nil
+		}
+	}
+}
+
+// processImage ошибку обработки записывает во вложение, наружу возвращает
+// только ошибки записи результата.
+func (s *Service) processImage(ctx context.Context, attachment model.Attachment) error {
+	logger := s.logger.With(zap.Int64("attachment_id", attachment.ID), zap.String("sha256", attachment.SHA256))
+
+	var err error
+	if int(attachment.ProcessingAttempts) > s.cfg.Images.MaxAttempts {
+		err = fmt.Errorf("%w: gave up after %d attempts", errUnsupportedImage, s.cfg.Images.MaxAttempts)
+	} else {
+		var processed model.Attachment
+		if processed, err = s.renderImage(ctx, attachment); err == nil {
+			err = s.attachmentStore.CompleteImage(ctx, processed)
+			if errors.Is(err, model.ErrNotFound) {
+				return nil
+			}
+			return err
+		}
+	}
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	retry := !errors.Is(err, errUnsupportedImage) && int(attachment.ProcessingAttempts) < s.cfg.Images.MaxAttempts
+	logger.Warn("image processing failed", zap.Error(err), zap.Bool("retry", retry))
+
+	return s.attachmentStore.FailImage(ctx, attachment.ID, err.Error(), retry)
+}
+
+// renderImage создаёт варианты изображения и записывает их в хранилище.
+// Метаданные не переносятся ни в один вариант: при перекодировании
+// сохраняются только пиксели, повёрнутые по EXIF.
+func (s *Service) renderImage(ctx context.Context, attachment model.Attachment) (model.Attachment, error) {
+	data, err := s.readBlob(ctx, attachment.SHA256)
+	if err != nil {
+		return model.Attachment{}, err
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return model.Attachment{}, fmt.Errorf("%w: %s", errUnsupportedImage, err.Error())
+	}
+	if config.Width*config.Height > s.cfg.Images.MaxPixels {
+		return model.Attachment{}, fmt.Errorf("%w: %dx%d exceeds %d pixels", errUnsupportedImage, config.Width, config.Height, s.cfg.Images.MaxPixels)
+	}
+
+	if attachment.MimeType == "image/gif" {
+		frames, pixels, err := gifPixels(data)
+		if err != nil {
+			return model.Attachment{}, fmt.Errorf("%w: %s", errUnsupportedImage, err.Error())
+		}
+		if pixels > s.cfg.Images.MaxPixels {
+			return model.Attachment{}, fmt.Errorf("%w: %d frames with %d pixels exceed %d pixels", errUnsupportedImage, frames, pixels, s.cfg.Images.MaxPixels)
+		}
+	}
+
+	img, err := imaging.Decode(bytes.NewReader(data), imaging.AutoOrientation(true))
+	if err != nil {
+		return model.Attachment{}, fmt.Errorf("%w: %s", errUnsupportedImage, err.Error())
+	}
+
+	bounds := img.Bounds()
+	attachment.Width, attachment.Height = int32(bounds.Dx()), int32(bounds.Dy())
+	attachment.Variants = attachment.Variants[:0]
+
+	original, err := s.encodeOriginal(attachment.MimeType, data, img)
+	if err != nil {
+		return model.Attachment{}, err
+	}
+	if err = s.addVariant(ctx, &attachment, model.VariantOriginal, original); err != nil {
+		return model.Attachment{}, err
+	}
+
+	longest := max(bounds.Dx(), bounds.Dy())
+	for _, size := range s.cfg.Images.ThumbnailSizes {
+		if size >= longest {
+			continue
+		}
+
+		thumbnail := imaging.Fit(img, size, size, imaging.Lanczos)
+		encoded, err := s.encodeThumbnail(thumbnail)
+		if err != nil {
+			return model.Attachment{}, err
+		}
+		if err = s.addVariant(ctx, &attachment, "thumb_"+strconv.Itoa(size), encoded); err != nil {
+			return model.Attachment{}, err
+		}
+	}
+
+	var webpImage image.Image = img
+	if s.cfg.Images.WebPSize < longest {
+		webpImage = imaging.Fit(img, s.cfg.Images.WebPSize, s.cfg.Images.WebPSize, imaging.Lanczos)
+	}
+	encoded, err := encodeVariant("image/webp", webpImage, func(w io.Writer, img image.Image) error {
+		return webp.Encode(w, img)
+	})
+	if err != nil {
+		return model.Attachment{}, err
+	}
+	if err = s.addVariant(ctx, &attachment, model.VariantWebP, encoded); err != nil {
+		return model.Attachment{}, err
+	}
+
+	xComponents, yComponents := 4, 3
+	if bounds.Dy() > bounds.Dx() {
+		xComponents, yComponents = 3, 4
+	}
+	attachment.Blurhash, err = blurhash.Encode(imaging.Fit(img, 32, 32, imaging.Box), xComponents, yComponents)
+	if err != nil {
+		return model.Attachment{}, fmt.Errorf("attachment_service.renderImage - blurhash.Encode - %w", err)
+	}
+
+	return attachment, nil
+}
+
+// encodedImage закодированный вариант до записи в хранилище.
+type encodedImage struct {
+	data          []byte
+	mimeType      string
+	width, height int
+}
+
+// encodeOriginal перекодирует изображение в исходном формате. GIF
+// перекодируется покадрово, чтобы сохранить анимацию.
+func (s *Service) encodeOriginal(mimeType string, data []byte, img image.Image) (encodedImage, error) {
+	switch mimeType {
+	case "image/gif":
+		g, err := gif.DecodeAll(bytes.NewReader(data))
+		if err != nil {
+			return encodedImage{}, fmt.Errorf("%w: %s", errUnsupportedImage, err.Error())
+		}
+
+		return encodeVariant(mimeType, img, func(w io.Writer, _ image.Image) error {
+			return gif.EncodeAll(w, &gif.GIF{
+				Image:           g.Image,
+				Delay:           g.Delay,
+				LoopCount:       g.LoopCount,
+				Disposal:        g.Disposal,
+				Config:          g.Config,
+				BackgroundIndex: g.BackgroundIndex,
+			})
+		})
+	case "image/png":
+		return encodeVariant(mimeType, img, png.Encode)
+	case "image/webp":
+		return encodeVariant(mimeType, img, func(w io.Writer, img image.Image) error {
+			return webp.Encode(w, img)
+		})
+	default:
+		return encodeVariant("image/jpeg", img, func(w io.Writer, img image.Image) error {
+			return jpeg.Encode(w, img, &jpeg.Options{Quality: s.cfg.Images.JPEGQuality})
+		})
+	}
+}
+
+// encodeThumbnail кодирует превью в JPEG, а с прозрачностью — в PNG.
+func (s *Service) encodeThumbnail(img *image.NRGBA) (encodedImage, error) {
+	if !img.Opaque() {
+		return encodeVariant("image/png", img, png.Encode)
+	}
+
+	return encodeVariant("image/jpeg", img, func(w io.Writer, img image.Image) error {
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: s.cfg.Images.JPEGQuality})
+	})
+}
+
+func encodeVariant(mimeType string, img image.Image, encode func(w io.Writer, img image.Image) error) (encodedImage, error) {
+	var buf bytes.Buffer
+	if err := encode(&buf, img); err != nil {
+		return encodedImage{}, fmt.Errorf("attachment_service.encodeVariant - %s - %w", mimeType, err)
+	}
+
+	bounds := img.Bounds()
+	return encodedImage{data: buf.Bytes(), mimeType: mimeType, width: bounds.Dx(), height: bounds.Dy()}, nil
+}
+
+// addVariant записывает вариант в хранилище так же, как Upload: содержимое,
+// которое уже есть, повторно не записывается.
+func (s *Service) addVariant(ctx context.Context, attachment *model.Attachment, name string, encoded encodedImage) error {
+	hash := sha256.Sum256(encoded.data)
+	variant := model.AttachmentVariant{
+		Name:     name,
+		SHA256:   hex.EncodeToString(hash[:]),
+		Size:     int64(len(encoded.data)),
+		MimeType: encoded.mimeType,
+		Width:    int32(encoded.width),
+		Height:   int32(encoded.height),
+	}
+
+	stored, err := s.attachmentStore.TouchBlob(ctx, variant.SHA256)
+	if err != nil {
+		return err
+	}
+
+	if !stored {
+		err = s.blobs.Put(ctx, blobKey(variant.SHA256), bytes.NewReader(encoded.data), variant.Size, variant.MimeType)
+		if err != nil {
+			return err
+		}
+	}
+
+	attachment.Variants = append(attachment.Variants, variant)
+
+	return nil
+}
+
+func (s *Service) readBlob(ctx context.Context, sha256 string) ([]byte, error) {
+	r, _, err := s.blobs.Get(ctx, blobKey(sha256))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close() //nolint:errcheck
+
+	data, err := io.ReadAll(io.LimitReader(r, s.cfg.MaxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("attachment_service.readBlob - ReadAll - %w", err)
+	}
+
+	return data, nil
+}
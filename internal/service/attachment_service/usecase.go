@@ -0,0 +1,374 @@
+package attachment_service
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/AdilBaidual/baseProject/internal/auth"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/AdilBaidual/baseProject/pkg/storage/blob"
+	"go.uber.org/zap"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	// sniffLength сколько первых байт смотрит http.DetectContentType.
+	sniffLength       = 512
+	maxFilenameLength = 255
+)
+
+type Config struct {
+	// MaxSize предельный размер файла в байтах.
+	MaxSize int64 `yaml:"max_size"`
+	// AllowedTypes допустимые MIME-типы. Тип определяется по содержимому,
+	// имя файла и заявленный клиентом тип не учитываются.
+	AllowedTypes []string `yaml:"allowed_types"`
+	// GCInterval период удаления блобов, на которые не ссылается ни одно вложение.
+	GCInterval time.Duration `yaml:"gc_interval"`
+	// GCGrace сколько неиспользуемый блоб хранится после последней загрузки.
+	// Должен быть заметно больше времени одной загрузки.
+	GCGrace time.Duration `yaml:"gc_grace"`
+	// GCBatch число блобов, удаляемых за одну транзакцию.
+	GCBatch int `yaml:"gc_batch"`
+	// Images обработка загруженных изображений.
+	Images ImageConfig `yaml:"images"`
+}
+
+func (c Config) withDefaults() Config {
+	if c.MaxSize <= 0 {
+		c.MaxSize = 10 << 20
+	}
+	if len(c.AllowedTypes) == 0 {
+		c.AllowedTypes = []string{"image/png", "image/jpeg", "image/gif", "image/webp"}
+	}
+	if c.GCInterval <= 0 {
+		c.GCInterval = time.Hour
+	}
+	if c.GCGrace <= 0 {
+		c.GCGrace = 24 * time.Hour
+	}
+	if c.GCBatch <= 0 {
+		c.GCBatch = 100
+	}
+	c.Images = c.Images.withDefaults()
+	return c
+}
+
+type attachmentStore interface {
+	GetPost(ctx context.Context, id int64) (model.Post, error)
+	TouchBlob(ctx context.Context, sha256 string) (bool, error)
+	CreateAttachment(ctx context.Context, attachment model.Attachment) (model.Attachment, error)
+	GetAttachment(ctx context.Context, id int64) (model.Attachment, error)
+	ListAttachments(ctx context.Context, postID int64) ([]model.Attachment, error)
+	GetAttachmentVariants(ctx context.Context, attachmentIDs []int64) (map[int64][]model.AttachmentVariant, error)
+	DeleteAttachment(ctx context.Context, id int64) error
+	ClaimImages(ctx context.Context, staleBefore time.Time, batch int) ([]model.Attachment, error)
+	CompleteImage(ctx context.Context, attachment model.Attachment) error
+	FailImage(ctx context.Context, id int64, reason string, retry bool) error
+	PurgeUnusedBlobs(ctx context.Context, before time.Time, batch int, remove func(ctx context.Context, sha256 string) error) (int, error)
+}
+
+type Service struct {
+	logger *zap.Logger
+	cfg    Config
+
+	attachmentStore attachmentStore
+	blobs           blob.Storage
+}
+
+func NewService(logger *zap.Logger, cfg Config, attachmentStore attachmentStore, blobs blob.Storage) *Service {
+	return &Service{
+		logger:          logger,
+		cfg:             cfg.withDefaults(),
+		attachmentStore: attachmentStore,
+		blobs:           blobs,
+	}
+}
+
+// MaxSize предельный размер загружаемого файла.
+func (s *Service) MaxSize() int64 {
+	return s.cfg.MaxSize
+}
+
+// Upload загружает файл к посту, который uploader может редактировать. Тип
+// проверяется по первым байтам ещё до чтения остального тела. Файл копится во
+// временном файле, пока считается хеш: содержимое, которое уже есть в
+// хранилище, повторно не записывается.
+func (s *Service) Upload(ctx context.Context, uploader auth.Identity, postID int64, filename string, r io.Reader) (model.Attachment, error) {
+	if _, err := s.editablePost(ctx, uploader, postID); err != nil {
+		return model.Attachment{}, err
+	}
+
+	br := bufio.NewReaderSize(r, sniffLength)
+	head, err := br.Peek(sniffLength)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return model.Attachment{}, fmt.Errorf("attachment_service.Upload - Peek - %w", err)
+	}
+	if len(head) == 0 {
+		return model.Attachment{}, fmt.Errorf("%w: file is empty", model.ErrInvalidArgument)
+	}
+
+	mimeType, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	if !slices.Contains(s.cfg.AllowedTypes, mimeType) {
+		return model.Attachment{}, fmt.Errorf("%w: file type %q is not allowed", model.ErrInvalidArgument, mimeType)
+	}
+
+	tmp, err := os.CreateTemp("", "attachment-*")
+	if err != nil {
+		return model.Attachment{}, fmt.Errorf("attachment_service.Upload - CreateTemp - %w", err)
+	}
+	defer os.Remove(tmp.Name()) //nolint:errcheck
+	defer tmp.Close()           //nolint:errcheck
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hash), io.LimitReader(br, s.cfg.MaxSize+1))
+	if err != nil {
+		return model.Attachment{}, fmt.Errorf("attachment_service.Upload - Copy - %w", err)
+	}
+	if size > s.cfg.MaxSize {
+		return model.Attachment{}, fmt.Errorf("%w: file exceeds %d bytes", model.ErrInvalidArgument, s.cfg.MaxSize)
+	}
+
+	attachment := model.Attachment{
+		PostID:     postID,
+		SHA256:     hex.EncodeToString(hash.Sum(nil)),
+		Filename:   cleanFilename(filename),
+		Size:       size,
+		MimeType:   mimeType,
+		UploadedBy: uploader.UserUUID,
+		ImageState: model.ImageStateNone,
+	}
+	if slices.Contains(imageTypes, mimeType) {
+		attachment.ImageState = model.ImageStatePending
+	}
+
+	stored, err := s.attachmentStore.TouchBlob(ctx, attachment.SHA256)
+	if err != nil {
+		return model.Attachment{}, err
+	}
+
+	if !stored {
+		if _, err = tmp.Seek(0, io.SeekStart); err != nil {
+			return model.Attachment{}, fmt.Errorf("attachment_service.Upload - Seek - %w", err)
+		}
+		if err = s.blobs.Put(ctx, blobKey(attachment.SHA256), tmp, size, mimeType); err != nil {
+			return model.Attachment{}, err
+		}
+	}
+
+	return s.attachmentStore.CreateAttachment(ctx, attachment)
+}
+
+// Open возвращает вложение и содержимое варианта variant, если читатель видит
+// пост. Пустой variant означает сам файл: у изображений это вариант
+// original без метаданных, а загруженный файл не отдаётся вовсе, пока
+// изображение не обработано. Для файла, который отдаётся как загружен,
+// возвращаемый вариант имеет пустое имя. Вызывающий закрывает io.ReadCloser.
+func (s *Service) Open(ctx context.Context, viewer auth.Identity, id int64, variant string) (model.Attachment, model.AttachmentVariant, io.ReadCloser, error) {
+	attachment, err := s.GetAttachment(ctx, viewer, id)
+	if err != nil {
+		return model.Attachment{}, model.AttachmentVariant{}, nil, err
+	}
+
+	content, err := s.contentVariant(attachment, variant)
+	if err != nil {
+		return model.Attachment{}, model.AttachmentVariant{}, nil, err
+	}
+
+	r, _, err := s.blobs.Get(ctx, blobKey(content.SHA256))
+	if err != nil {
+		if errors.Is(err, blob.ErrNotFound) {
+			s.logger.Error("attachment content is missing", zap.Int64("attachment_id", id), zap.String("sha256", content.SHA256))
+			return model.Attachment{}, model.AttachmentVariant{}, nil, model.ErrNotFound
+		}
+		return model.Attachment{}, model.AttachmentVariant{}, nil, err
+	}
+
+	return attachment, content, r, nil
+}
+
+func (s *Service) contentVariant(attachment model.Attachment, name string) (model.AttachmentVariant, error) {
+	if name == "" {
+		switch attachment.ImageState {
+		case model.ImageStateNone:
+			return model.AttachmentVariant{SHA256: attachment.SHA256, Size: attachment.Size, MimeType: attachment.MimeType}, nil
+		case model.ImageStateFailed:
+			return model.AttachmentVariant{}, fmt.Errorf("%w: image could not be processed", model.ErrFailedPrecondition)
+		case model.ImageStateReady:
+			name = model.VariantOriginal
+		default:
+			return model.AttachmentVariant{}, fmt.Errorf("%w: image is still being processed", model.ErrFailedPrecondition)
+		}
+	}
+
+	variant, ok := attachment.Variant(name)
+	if !ok {
+		return model.AttachmentVariant{}, fmt.Errorf("%w: variant %q", model.ErrNotFound, name)
+	}
+
+	return variant, nil
+}
+
+func (s *Service) GetAttachment(ctx context.Context, viewer auth.Identity, id int64) (model.Attachment, error) {
+	attachment, err := s.attachmentStore.GetAttachment(ctx, id)
+	if err != nil {
+		return model.Attachment{}, err
+	}
+
+	if _, err = s.visiblePost(ctx, viewer, attachment.PostID); err != nil {
+		return model.Attachment{}, err
+	}
+
+	attachments, err := s.withVariants(ctx, []model.Attachment{attachment})
+	if err != nil {
+		return model.Attachment{}, err
+	}
+
+	return attachments[0], nil
+}
+
+func (s *Service) ListAttachments(ctx context.Context, viewer auth.Identity, postID int64) ([]model.Attachment, error) {
+	if _, err := s.visiblePost(ctx, viewer, postID); err != nil {
+		return nil, err
+	}
+
+	attachments, err := s.attachmentStore.ListAttachments(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.withVariants(ctx, attachments)
+}
+
+func (s *Service) withVariants(ctx context.Context, attachments []model.Attachment) ([]model.Attachment, error) {
+	ids := make([]int64, 0, len(attachments))
+	for _, a := range attachments {
+		if a.ImageState == model.ImageStateReady {
+			ids = append(ids, a.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return attachments, nil
+	}
+
+	variants, err := s.attachmentStore.GetAttachmentVariants(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range attachments {
+		attachments[i].Variants = variants[attachments[i].ID]
+	}
+
+	return attachments, nil
+}
+
+// DeleteAttachment удаляет вложение по тем же правилам, что и правку поста.
+func (s *Service) DeleteAttachment(ctx context.Context, editor auth.Identity, id int64) error {
+	attachment, err := s.attachmentStore.GetAttachment(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if _, err = s.editablePost(ctx, editor, attachment.PostID); err != nil {
+		return err
+	}
+
+	return s.attachmentStore.DeleteAttachment(ctx, id)
+}
+
+// GCInterval период запуска CollectGarbage.
+func (s *Service) GCInterval() time.Duration {
+	return s.cfg.GCInterval
+}
+
+// CollectGarbage удаляет из хранилища содержимое, на которое не ссылается ни
+// одно вложение дольше GCGrace: удалённые вложения и вложения очищенных постов.
+func (s *Service) CollectGarbage(ctx context.Context) error {
+	before := time.Now().Add(-s.cfg.GCGrace)
+
+	for {
+		removed, err := s.attachmentStore.PurgeUnusedBlobs(ctx, before, s.cfg.GCBatch, func(ctx context.Context, sha256 string) error {
+			return s.blobs.Delete(ctx, blobKey(sha256))
+		})
+		if err != nil {
+			return err
+		}
+
+		if removed == 0 {
+			return nil
+		}
+
+		s.logger.Info("unused blobs removed", zap.Int("blobs", removed))
+
+		if removed < s.cfg.GCBatch {
+			return nil
+		}
+	}
+}
+
+// visiblePost возвращает пост, если читатель может его видеть, по тем же
+// правилам, что и в post_service.
+func (s *Service) visiblePost(ctx context.Context, viewer auth.Identity, id int64) (model.Post, error) {
+	post, err := s.attachmentStore.GetPost(ctx, id)
+	if err != nil {
+		return model.Post{}, err
+	}
+
+	if !post.VisibleTo(viewer.UserUUID, viewer.HasPermission(model.PermissionModerationManage)) {
+		return model.Post{}, model.ErrNotFound
+	}
+
+	return post, nil
+}
+
+func (s *Service) editablePost(ctx context.Context, editor auth.Identity, id int64) (model.Post, error) {
+	post, err := s.visiblePost(ctx, editor, id)
+	if err != nil {
+		return model.Post{}, err
+	}
+
+	if post.AuthorUUID != editor.UserUUID && !editor.HasPermission(model.PermissionPostsUpdateAny) {
+		return model.Post{}, fmt.Errorf("%w: only the author can manage attachments of this post", model.ErrPermissionDenied)
+	}
+
+	return post, nil
+}
+
+// blobKey раскладывает блобы по каталогам по первым символам хеша.
+func blobKey(sha256 string) string {
+	return "sha256/" + sha256[:2] + "/" + sha256
+}
+
+// cleanFilename оставляет от имени файла только последний элемент пути без
+// управляющих символов. Имя нужно только для Content-Disposition.
+func cleanFilename(name string) string {
+	name = path.Base(strings.ReplaceAll(name, `\`, "/"))
+	name = strings.TrimSpace(strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == '"' {
+			return -1
+		}
+		return r
+	}, name))
+
+	if name == "." || name == "/" {
+		return ""
+	}
+
+	if runes := []rune(name); len(runes) > maxFilenameLength {
+		name = string(runes[len(runes)-maxFilenameLength:])
+	}
+
+	return name
+}
@@ -0,0 +1,147 @@
+package bookmark_service
+
+import (
+	"context"
+	"fmt"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"strings"
+	"unicode/utf8"
+)
+
+const maxCollectionNameLength = 64
+
+type Config struct {
+	// MaxCollections сколько коллекций закладок может завести пользователь.
+	MaxCollections int `yaml:"max_collections"`
+}
+
+func (c Config) withDefaults() Config {
+	if c.MaxCollections <= 0 {
+		c.MaxCollections = 100
+	}
+	return c
+}
+
+type bookmarkStore interface {
+	AddBookmark(ctx context.Context, bookmark model.Bookmark) (model.Bookmark, error)
+	MoveBookmark(ctx context.Context, userUUID uuid.UUID, postID, collectionID int64) (model.Bookmark, error)
+	RemoveBookmark(ctx context.Context, userUUID uuid.UUID, postID int64) (bool, error)
+	CreateBookmarkCollection(ctx context.Context, collection model.BookmarkCollection) (model.BookmarkCollection, error)
+	RenameBookmarkCollection(ctx context.Context, userUUID uuid.UUID, id int64, name string) (model.BookmarkCollection, error)
+	DeleteBookmarkCollection(ctx context.Context, userUUID uuid.UUID, id int64) error
+	GetBookmarkCollection(ctx context.Context, userUUID uuid.UUID, id int64) (model.BookmarkCollection, error)
+	ListBookmarkCollections(ctx context.Context, userUUID uuid.UUID) ([]model.BookmarkCollection, error)
+	CountBookmarkCollections(ctx context.Context, userUUID uuid.UUID) (int, error)
+	GetPost(ctx context.Context, id int64) (model.Post, error)
+}
+
+type Service struct {
+	logger *zap.Logger
+	cfg    Config
+
+	bookmarkStore bookmarkStore
+}
+
+func NewService(logger *zap.Logger, cfg Config, bookmarkStore bookmarkStore) *Service {
+	return &Service{
+		logger:        logger,
+		cfg:           cfg.withDefaults(),
+		bookmarkStore: bookmarkStore,
+	}
+}
+
+// Add добавляет пост в закладки, collectionID = 0 — вне коллекций. Пост,
+// который уже в закладках, переносится в collectionID.
+func (s *Service) Add(ctx context.Context, userUUID uuid.UUID, postID, collectionID int64) (model.Bookmark, error) {
+	post, err := s.bookmarkStore.GetPost(ctx, postID)
+	if err != nil {
+		return model.Bookmark{}, err
+	}
+	if !post.VisibleTo(userUUID, false) {
+		return model.Bookmark{}, model.ErrNotFound
+	}
+
+	if err = s.checkCollection(ctx, userUUID, collectionID); err != nil {
+		return model.Bookmark{}, err
+	}
+
+	return s.bookmarkStore.AddBookmark(ctx, model.Bookmark{
+		UserUUID:     userUUID,
+		PostID:       postID,
+		CollectionID: collectionID,
+	})
+}
+
+// Remove убирает пост из закладок. Удаление отсутствующей закладки не считается ошибкой.
+func (s *Service) Remove(ctx context.Context, userUUID uuid.UUID, postID int64) error {
+	_, err := s.bookmarkStore.RemoveBookmark(ctx, userUUID, postID)
+	return err
+}
+
+// Move переносит закладку в коллекцию collectionID, 0 — в общий список.
+func (s *Service) Move(ctx context.Context, userUUID uuid.UUID, postID, collectionID int64) (model.Bookmark, error) {
+	if err := s.checkCollection(ctx, userUUID, collectionID); err != nil {
+		return model.Bookmark{}, err
+	}
+
+	return s.bookmarkStore.MoveBookmark(ctx, userUUID, postID, collectionID)
+}
+
+func (s *Service) CreateCollection(ctx context.Context, userUUID uuid.UUID, name string) (model.BookmarkCollection, error) {
+	name, err := normalizeName(name)
+	if err != nil {
+		return model.BookmarkCollection{}, err
+	}
+
+	count, err := s.bookmarkStore.CountBookmarkCollections(ctx, userUUID)
+	if err != nil {
+		return model.BookmarkCollection{}, err
+	}
+	if count >= s.cfg.MaxCollections {
+		return model.BookmarkCollection{}, fmt.Errorf("%w: at most %d collections", model.ErrFailedPrecondition, s.cfg.MaxCollections)
+	}
+
+	return s.bookmarkStore.CreateBookmarkCollection(ctx, model.BookmarkCollection{
+		UserUUID: userUUID,
+		Name:     name,
+	})
+}
+
+func (s *Service) RenameCollection(ctx context.Context, userUUID uuid.UUID, id int64, name string) (model.BookmarkCollection, error) {
+	name, err := normalizeName(name)
+	if err != nil {
+		return model.BookmarkCollection{}, err
+	}
+
+	return s.bookmarkStore.RenameBookmarkCollection(ctx, userUUID, id, name)
+}
+
+// DeleteCollection удаляет коллекцию, её закладки остаются в общем списке.
+func (s *Service) DeleteCollection(ctx context.Context, userUUID uuid.UUID, id int64) error {
+	return s.bookmarkStore.DeleteBookmarkCollection(ctx, userUUID, id)
+}
+
+func (s *Service) ListCollections(ctx context.Context, userUUID uuid.UUID) ([]model.BookmarkCollection, error) {
+	return s.bookmarkStore.ListBookmarkCollections(ctx, userUUID)
+}
+
+// checkCollection коллекция существует и принадлежит пользователю. Чужая
+// коллекция неотличима от несуществующей.
+func (s *Service) checkCollection(ctx context.Context, userUUID uuid.UUID, collectionID int64) error {
+	if collectionID == 0 {
+		return nil
+	}
+
+	_, err := s.bookmarkStore.GetBookmarkCollection(ctx, userUUID, collectionID)
+	return err
+}
+
+func normalizeName(name string) (string, error) {
+	name = strings.Join(strings.Fields(name), " ")
+	if name == "" || utf8.RuneCountInString(name) > maxCollectionNameLength {
+		return "", fmt.Errorf("%w: name must be 1..%d characters", model.ErrInvalidArgument, maxCollectionNameLength)
+	}
+	return name, nil
+}
@@ -1,21 +1,151 @@
 package service
 
 import (
+	"github.com/AdilBaidual/baseProject/internal/auth"
+	"github.com/AdilBaidual/baseProject/internal/service/apikey_service"
+	"github.com/AdilBaidual/baseProject/internal/service/attachment_service"
+	"github.com/AdilBaidual/baseProject/internal/service/bookmark_service"
+	"github.com/AdilBaidual/baseProject/internal/service/feed_service"
+	"github.com/AdilBaidual/baseProject/internal/service/follow_service"
+	"github.com/AdilBaidual/baseProject/internal/service/moderation_service"
+	"github.com/AdilBaidual/baseProject/internal/service/notification_service"
+	"github.com/AdilBaidual/baseProject/internal/service/policy_service"
+	"github.com/AdilBaidual/baseProject/internal/service/post_service"
+	"github.com/AdilBaidual/baseProject/internal/service/reaction_service"
+	"github.com/AdilBaidual/baseProject/internal/service/sso_service"
 	"github.com/AdilBaidual/baseProject/internal/service/test_service"
+	"github.com/AdilBaidual/baseProject/internal/service/trash_service"
+	"github.com/AdilBaidual/baseProject/internal/service/user_service"
 	"github.com/AdilBaidual/baseProject/internal/store"
+	"github.com/AdilBaidual/baseProject/pkg/mailer"
+	"github.com/AdilBaidual/baseProject/pkg/secretbox"
+	"github.com/AdilBaidual/baseProject/pkg/sso"
+	"github.com/AdilBaidual/baseProject/pkg/storage/blob"
 	"go.uber.org/zap"
 )
 
 type ServiceContainer struct {
-	testService *test_service.Service
+	testService   *test_service.Service
+	userService   *user_service.Service
+	apiKeyService *apikey_service.Service
+	ssoService    *sso_service.Service
+
+	postService       *post_service.Service
+	reactionService   *reaction_service.Service
+	trashService      *trash_service.Service
+	moderationService *moderation_service.Service
+	policyService     *policy_service.Service
+	attachmentService *attachment_service.Service
+	feedService       *feed_service.Service
+	followService     *follow_service.Service
+	bookmarkService   *bookmark_service.Service
+
+	notificationService *notification_service.Service
 }
 
-func NewServiceContainer(logger *zap.Logger, testStore *store.Store) *ServiceContainer {
-	return &ServiceContainer{
-		testService: test_service.NewService(logger, testStore),
+func NewServiceContainer(
+	logger *zap.Logger,
+	testStore *store.Store,
+	tokens *auth.TokenManager,
+	userCfg user_service.Config,
+	mail mailer.Mailer,
+	templates *mailer.Templates,
+	box *secretbox.Box,
+	ssoCfg sso.Config,
+	registry *sso.Registry,
+	postCfg post_service.Config,
+	trashCfg trash_service.Config,
+	policyCfg policy_service.Config,
+	attachmentCfg attachment_service.Config,
+	blobs blob.Storage,
+	feedCfg feed_service.Config,
+	followCfg follow_service.Config,
+	notificationCfg notification_service.Config,
+	bookmarkCfg bookmark_service.Config,
+) (*ServiceContainer, error) {
+	policyService, err := policy_service.NewService(logger, policyCfg, testStore)
+	if err != nil {
+		return nil, err
 	}
+
+	trashService := trash_service.NewService(logger, trashCfg, testStore)
+	userService := user_service.NewService(logger, userCfg, testStore, tokens, mail, templates, box, trashService)
+	reactionService := reaction_service.NewService(logger, testStore)
+	notificationService := notification_service.NewService(logger, notificationCfg, testStore)
+	followService := follow_service.NewService(logger, followCfg, testStore, notificationService)
+	postService := post_service.NewService(logger, postCfg, testStore, reactionService, trashService, policyService, followService, notificationService)
+
+	return &ServiceContainer{
+		testService:       test_service.NewService(logger, testStore),
+		userService:       userService,
+		apiKeyService:     apikey_service.NewService(logger, testStore),
+		ssoService:        sso_service.NewService(logger, ssoCfg, testStore, registry, userService),
+		postService:       postService,
+		reactionService:   reactionService,
+		trashService:      trashService,
+		moderationService: moderation_service.NewService(logger, testStore),
+		policyService:     policyService,
+		attachmentService: attachment_service.NewService(logger, attachmentCfg, testStore, blobs),
+		feedService:       feed_service.NewService(logger, feedCfg, testStore, postService),
+		followService:     followService,
+		bookmarkService:   bookmark_service.NewService(logger, bookmarkCfg, testStore),
+
+		notificationService: notificationService,
+	}, nil
 }
 
 func (s *ServiceContainer) GetTestService() *test_service.Service {
 	return s.testService
 }
+
+func (s *ServiceContainer) GetUserService() *user_service.Service {
+	return s.userService
+}
+
+func (s *ServiceContainer) GetAPIKeyService() *apikey_service.Service {
+	return s.apiKeyService
+}
+
+func (s *ServiceContainer) GetSSOService() *sso_service.Service {
+	return s.ssoService
+}
+
+func (s *ServiceContainer) GetPostService() *post_service.Service {
+	return s.postService
+}
+
+func (s *ServiceContainer) GetReactionService() *reaction_service.Service {
+	return s.reactionService
+}
+
+func (s *ServiceContainer) GetTrashService() *trash_service.Service {
+	return s.trashService
+}
+
+func (s *ServiceContainer) GetModerationService() *moderation_service.Service {
+	return s.moderationService
+}
+
+func (s *ServiceContainer) GetPolicyService() *policy_service.Service {
+	return s.policyService
+}
+
+func (s *ServiceContainer) GetAttachmentService() *attachment_service.Service {
+	return s.attachmentService
+}
+
+func (s *ServiceContainer) GetFeedService() *feed_service.Service {
+	return s.feedService
+}
+
+func (s *ServiceContainer) GetFollowService() *follow_service.Service {
+	return s.followService
+}
+
+func (s *ServiceContainer) GetBookmarkService() *bookmark_service.Service {
+	return s.bookmarkService
+}
+
+func (s *ServiceContainer) GetNotificationService() *notification_service.Service {
+	return s.notificationService
+}
@@ -0,0 +1,313 @@
+package feed_service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/AdilBaidual/baseProject/internal/auth"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/AdilBaidual/baseProject/pkg/feed"
+	"github.com/AdilBaidual/baseProject/pkg/slug"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// relativeURLRe ссылки и картинки с путём от корня сайта: читалки лент
+// разрешают их относительно адреса ленты не всегда, поэтому они
+// переписываются в абсолютные.
+var relativeURLRe = regexp.MustCompile(`(\s(?:href|src)=")/([^/])`)
+
+type Config struct {
+	// BaseURL внешний адрес API, из него строятся ссылки в лентах.
+	BaseURL string `yaml:"base_url" env:"FEED_BASE_URL"`
+	// Title заголовок ленты всех постов, ленты авторов и тегов его дополняют.
+	Title       string `yaml:"title"`
+	Description string `yaml:"description"`
+	// Size число последних постов в ленте.
+	Size int `yaml:"size"`
+	// CacheSize сколько сформированных лент хранится в памяти.
+	CacheSize int `yaml:"cache_size"`
+	// MaxAge предельный возраст ленты в кэше. Изменения постов сбрасывают кэш
+	// сразу, а возраст ограничивает то, что меняется со временем само:
+	// истечение ограничений аккаунтов авторов, и изменения, пропущенные, пока
+	// слушатель переподключался.
+	MaxAge time.Duration `yaml:"max_age"`
+	// ListenRetry пауза перед переподключением слушателя изменений.
+	ListenRetry time.Duration `yaml:"listen_retry"`
+}
+
+func (c Config) withDefaults() Config {
+	c.BaseURL = strings.TrimRight(c.BaseURL, "/")
+	if c.BaseURL == "" {
+		c.BaseURL = "http://localhost:8080"
+	}
+	if c.Title == "" {
+		c.Title = "baseProject"
+	}
+	if c.Size <= 0 {
+		c.Size = 20
+	}
+	if c.CacheSize <= 0 {
+		c.CacheSize = 1000
+	}
+	if c.MaxAge <= 0 {
+		c.MaxAge = 10 * time.Minute
+	}
+	if c.ListenRetry <= 0 {
+		c.ListenRetry = 5 * time.Second
+	}
+	return c
+}
+
+type feedStore interface {
+	ListenFeedChanges(ctx context.Context, listening func(), notify func()) error
+	GetUserByUUID(ctx context.Context, id uuid.UUID) (model.User, error)
+}
+
+type postService interface {
+	ListPosts(ctx context.Context, viewer auth.Identity, filter model.PostFilter, pageSize int32, pageToken string) ([]model.Post, string, error)
+}
+
+type cacheEntry struct {
+	generation int64
+	builtAt    time.Time
+	doc        model.FeedDocument
+}
+
+type Service struct {
+	logger *zap.Logger
+	cfg    Config
+
+	feedStore   feedStore
+	postService postService
+
+	mu    sync.Mutex
+	state model.FeedState
+	cache map[string]cacheEntry
+	group singleflight.Group
+}
+
+func NewService(logger *zap.Logger, cfg Config, feedStore feedStore, postService postService) *Service {
+	return &Service{
+		logger:      logger,
+		cfg:         cfg.withDefaults(),
+		feedStore:   feedStore,
+		postService: postService,
+		state:       model.FeedState{ChangedAt: time.Now()},
+		cache:       make(map[string]cacheEntry),
+	}
+}
+
+// Listen слушает изменения постов в Postgres и начинает новое поколение лент
+// на каждое из них, пока не отменён ctx. После переподключения поколение
+// тоже меняется: изменения, пришедшие без соединения, потеряны.
+func (s *Service) Listen(ctx context.Context) {
+	for {
+		err := s.feedStore.ListenFeedChanges(ctx, s.changed, s.changed)
+		if ctx.Err() != nil {
+			return
+		}
+		s.logger.Error("feed listener disconnected", zap.Error(err))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.cfg.ListenRetry):
+		}
+	}
+}
+
+func (s *Service) changed() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.state = model.FeedState{Generation: s.state.Generation + 1, ChangedAt: time.Now()}
+}
+
+// Feed возвращает ленту последних опубликованных постов в формате format,
+// какой её видит анонимный читатель. Лента берётся из кэша, пока поколение
+// содержимого в Postgres не изменилось. Одновременные запросы одной ленты
+// формируют её один раз.
+func (s *Service) Feed(ctx context.Context, scope model.FeedScope, format string) (model.FeedDocument, error) {
+	if !slices.Contains(model.FeedFormats, format) {
+		return model.FeedDocument{}, fmt.Errorf("%w: unknown feed format %q", model.ErrNotFound, format)
+	}
+
+	if scope.TagSlug != "" {
+		scope.TagSlug = slug.Make(scope.TagSlug)
+		if scope.TagSlug == "" {
+			return model.FeedDocument{}, fmt.Errorf("%w: invalid tag", model.ErrInvalidArgument)
+		}
+	}
+
+	key := format + "|" + scope.AuthorUUID.String() + "|" + scope.TagSlug
+
+	// Поколение берётся до чтения постов: изменение, пришедшее во время
+	// формирования, не даст закэшировать ленту под новым поколением.
+	s.mu.Lock()
+	state := s.state
+	entry, ok := s.cache[key]
+	s.mu.Unlock()
+
+	if ok && entry.generation == state.Generation && time.Since(entry.builtAt) < s.cfg.MaxAge {
+		return entry.doc, nil
+	}
+
+	// Формирование не прерывается отменой запроса, который его начал: результат
+	// ждут и другие запросы.
+	v, err, _ := s.group.Do(key+"|"+strconv.FormatInt(state.Generation, 10), func() (any, error) {
+		return s.build(context.WithoutCancel(ctx), key, scope, format, state)
+	})
+	if err != nil {
+		return model.FeedDocument{}, err
+	}
+
+	return v.(model.FeedDocument), nil
+}
+
+func (s *Service) build(ctx context.Context, key string, scope model.FeedScope, format string, state model.FeedState) (model.FeedDocument, error) {
+	f, err := s.feed(ctx, scope, format)
+	if err != nil {
+		return model.FeedDocument{}, err
+	}
+
+	doc := model.FeedDocument{ContentType: feed.ContentTypeRSS}
+	if format == model.FeedFormatAtom {
+		doc.ContentType = feed.ContentTypeAtom
+		doc.Body, err = f.Atom()
+	} else {
+		doc.Body, err = f.RSS()
+	}
+	if err != nil {
+		return model.FeedDocument{}, fmt.Errorf("feed_service.build - marshal - %w", err)
+	}
+
+	sum := sha256.Sum256(doc.Body)
+	doc.ETag = `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Время изменения ленты — время изменения поколения, в котором она
+	// изменилась. Если содержимое не изменилось, время сохраняется, и
+	// If-Modified-Since продолжает совпадать.
+	doc.ModifiedAt = state.ChangedAt
+	if previous, ok := s.cache[key]; ok {
+		switch {
+		case previous.doc.ETag == doc.ETag:
+			doc.ModifiedAt = previous.doc.ModifiedAt
+		case previous.generation == state.Generation:
+			doc.ModifiedAt = time.Now()
+		}
+	}
+
+	if _, ok := s.cache[key]; !ok && len(s.cache) >= s.cfg.CacheSize {
+		s.evictLocked(state.Generation)
+	}
+	s.cache[key] = cacheEntry{generation: state.Generation, builtAt: time.Now(), doc: doc}
+
+	return doc, nil
+}
+
+// evictLocked освобождает место в кэше: сначала удаляются ленты прошлых
+// поколений, если таких нет — произвольная.
+func (s *Service) evictLocked(generation int64) {
+	for key, entry := range s.cache {
+		if entry.generation != generation {
+			delete(s.cache, key)
+		}
+	}
+	if len(s.cache) < s.cfg.CacheSize {
+		return
+	}
+
+	for key := range s.cache {
+		delete(s.cache, key)
+		return
+	}
+}
+
+func (s *Service) feed(ctx context.Context, scope model.FeedScope, format string) (feed.Feed, error) {
+	f := feed.Feed{
+		Title:       s.cfg.Title,
+		Description: s.cfg.Description,
+		Link:        s.cfg.BaseURL + "/v1/posts",
+		SelfLink:    s.cfg.BaseURL + "/v1/feeds/" + format,
+	}
+
+	authors := make(map[uuid.UUID]string)
+
+	switch {
+	case scope.AuthorUUID != uuid.Nil:
+		author, err := s.feedStore.GetUserByUUID(ctx, scope.AuthorUUID)
+		if err != nil {
+			return feed.Feed{}, err
+		}
+		authors[author.UUID] = author.FirstName
+
+		f.Title += ": " + author.FirstName
+		f.Link += "?author_uuid=" + author.UUID.String()
+		f.SelfLink = s.cfg.BaseURL + "/v1/feeds/authors/" + author.UUID.String() + "/" + format
+	case scope.TagSlug != "":
+		f.Title += ": #" + scope.TagSlug
+		f.Link = s.cfg.BaseURL + "/v1/tags/" + scope.TagSlug + "/posts"
+		f.SelfLink = s.cfg.BaseURL + "/v1/feeds/tags/" + scope.TagSlug + "/" + format
+	}
+
+	if f.Description == "" {
+		f.Description = f.Title
+	}
+
+	posts, _, err := s.postService.ListPosts(ctx, auth.Identity{}, model.PostFilter{
+		Status:     model.PostStatusPublished,
+		AuthorUUID: scope.AuthorUUID,
+		TagSlug:    scope.TagSlug,
+	}, int32(s.cfg.Size), "")
+	if err != nil {
+		return feed.Feed{}, err
+	}
+
+	f.Items = make([]feed.Item, 0, len(posts))
+	for _, post := range posts {
+		name, ok := authors[post.AuthorUUID]
+		if !ok {
+			author, err := s.feedStore.GetUserByUUID(ctx, post.AuthorUUID)
+			if err != nil {
+				return feed.Feed{}, err
+			}
+			name = author.FirstName
+			authors[post.AuthorUUID] = name
+		}
+
+		link := s.cfg.BaseURL + "/v1/posts/" + strconv.FormatInt(post.ID, 10)
+		item := feed.Item{
+			ID:          link,
+			Title:       post.Title,
+			Link:        link,
+			Author:      name,
+			ContentHTML: relativeURLRe.ReplaceAllString(post.ContentHTML, "${1}"+s.cfg.BaseURL+"/${2}"),
+		}
+		if post.PublishedAt != nil {
+			item.Published = *post.PublishedAt
+		}
+		for _, tag := range post.Tags {
+			item.Categories = append(item.Categories, tag.Name)
+		}
+
+		if item.Published.After(f.Updated) {
+			f.Updated = item.Published
+		}
+
+		f.Items = append(f.Items, item)
+	}
+
+	return f, nil
+}
@@ -0,0 +1,207 @@
+package follow_service
+
+import (
+	"context"
+	"fmt"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/AdilBaidual/baseProject/pkg/pagination"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"time"
+)
+
+type Config struct {
+	// ProlificPosts число постов за ProlificWindow, начиная с которого посты
+	// автора раскладываются по лентам подписчиков при публикации. Посты
+	// остальных авторов лента собирает при чтении.
+	ProlificPosts int `yaml:"prolific_posts"`
+	// ProlificWindow период, за который считаются посты автора.
+	ProlificWindow time.Duration `yaml:"prolific_window"`
+	// TimelineRetention глубина ленты: более старые посты в неё не попадают.
+	TimelineRetention time.Duration `yaml:"timeline_retention"`
+	// RefreshInterval период пересчёта активных авторов и очистки лент.
+	RefreshInterval time.Duration `yaml:"refresh_interval"`
+}
+
+func (c Config) withDefaults() Config {
+	if c.ProlificPosts <= 0 {
+		c.ProlificPosts = 20
+	}
+	if c.ProlificWindow <= 0 {
+		c.ProlificWindow = 24 * time.Hour
+	}
+	if c.TimelineRetention <= 0 {
+		c.TimelineRetention = 30 * 24 * time.Hour
+	}
+	if c.RefreshInterval <= 0 {
+		c.RefreshInterval = time.Minute
+	}
+	return c
+}
+
+type followStore interface {
+	Follow(ctx context.Context, followerUUID, followeeUUID uuid.UUID, since time.Time) (bool, error)
+	Unfollow(ctx context.Context, followerUUID, followeeUUID uuid.UUID) (bool, error)
+	ListFollowers(ctx context.Context, userUUID uuid.UUID, before *time.Time, beforeUser uuid.UUID, limit int) ([]model.Follow, error)
+	ListFollowing(ctx context.Context, userUUID uuid.UUID, before *time.Time, beforeUser uuid.UUID, limit int) ([]model.Follow, error)
+	ListProlificChanges(ctx context.Context, threshold int, window time.Duration) (entering, leaving []uuid.UUID, err error)
+	SetProlificAuthor(ctx context.Context, authorUUID uuid.UUID, prolific bool, since time.Time) error
+	PruneTimeline(ctx context.Context, since time.Time) (int64, error)
+	GetUserByUUID(ctx context.Context, id uuid.UUID) (model.User, error)
+}
+
+type notificationService interface {
+	NotifyFollow(ctx context.Context, followerUUID, followeeUUID uuid.UUID) error
+}
+
+type cursor struct {
+	CreatedAt time.Time `json:"t"`
+	UserUUID  uuid.UUID `json:"u"`
+}
+
+type Service struct {
+	logger *zap.Logger
+	cfg    Config
+
+	followStore         followStore
+	notificationService notificationService
+}
+
+func NewService(logger *zap.Logger, cfg Config, followStore followStore, notificationService notificationService) *Service {
+	return &Service{
+		logger:              logger,
+		cfg:                 cfg.withDefaults(),
+		followStore:         followStore,
+		notificationService: notificationService,
+	}
+}
+
+// Follow подписывает пользователя на автора. Повторная подписка ничего не меняет.
+func (s *Service) Follow(ctx context.Context, followerUUID, followeeUUID uuid.UUID) error {
+	if followerUUID == followeeUUID {
+		return fmt.Errorf("%w: cannot follow yourself", model.ErrInvalidArgument)
+	}
+
+	if _, err := s.followStore.GetUserByUUID(ctx, followeeUUID); err != nil {
+		return err
+	}
+
+	followed, err := s.followStore.Follow(ctx, followerUUID, followeeUUID, s.TimelineSince())
+	if err != nil || !followed {
+		return err
+	}
+
+	if err = s.notificationService.NotifyFollow(ctx, followerUUID, followeeUUID); err != nil {
+		s.logger.Error("failed to create follow notification", zap.Error(err))
+	}
+
+	return nil
+}
+
+// Unfollow отменяет подписку. Отмена отсутствующей подписки не считается ошибкой.
+func (s *Service) Unfollow(ctx context.Context, followerUUID, followeeUUID uuid.UUID) error {
+	_, err := s.followStore.Unfollow(ctx, followerUUID, followeeUUID)
+	return err
+}
+
+// ListFollowers подписчики пользователя от новых к старым.
+func (s *Service) ListFollowers(ctx context.Context, userUUID uuid.UUID, pageSize int32, pageToken string) ([]model.Follow, string, error) {
+	return s.list(ctx, s.followStore.ListFollowers, userUUID, pageSize, pageToken, func(f model.Follow) uuid.UUID {
+		return f.FollowerUUID
+	})
+}
+
+// ListFollowing авторы, на которых подписан пользователь, от новых подписок к старым.
+func (s *Service) ListFollowing(ctx context.Context, userUUID uuid.UUID, pageSize int32, pageToken string) ([]model.Follow, string, error) {
+	return s.list(ctx, s.followStore.ListFollowing, userUUID, pageSize, pageToken, func(f model.Follow) uuid.UUID {
+		return f.FolloweeUUID
+	})
+}
+
+type listFunc func(ctx context.Context, userUUID uuid.UUID, before *time.Time, beforeUser uuid.UUID, limit int) ([]model.Follow, error)
+
+// list общая пагинация списков подписок: other возвращает из подписки того,
+// кто перечисляется в списке.
+func (s *Service) list(ctx context.Context, fetch listFunc, userUUID uuid.UUID, pageSize int32, pageToken string, other func(model.Follow) uuid.UUID) ([]model.Follow, string, error) {
+	if _, err := s.followStore.GetUserByUUID(ctx, userUUID); err != nil {
+		return nil, "", err
+	}
+
+	var c cursor
+	if err := pagination.DecodeToken(pageToken, &c); err != nil {
+		return nil, "", fmt.Errorf("%w: %s", model.ErrInvalidArgument, err.Error())
+	}
+
+	var before *time.Time
+	if !c.CreatedAt.IsZero() {
+		before = &c.CreatedAt
+	}
+
+	limit := pagination.PageSize(pageSize)
+
+	follows, err := fetch(ctx, userUUID, before, c.UserUUID, limit+1)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var nextToken string
+	if len(follows) > limit {
+		follows = follows[:limit]
+		last := follows[limit-1]
+
+		if nextToken, err = pagination.EncodeToken(cursor{CreatedAt: last.CreatedAt, UserUUID: other(last)}); err != nil {
+			return nil, "", err
+		}
+	}
+
+	return follows, nextToken, nil
+}
+
+// TimelineSince граница ленты: посты, опубликованные раньше, в неё не попадают.
+func (s *Service) TimelineSince() time.Time {
+	return time.Now().Add(-s.cfg.TimelineRetention)
+}
+
+// RefreshInterval период вызова RefreshTimelines.
+func (s *Service) RefreshInterval() time.Duration {
+	return s.cfg.RefreshInterval
+}
+
+// RefreshTimelines переводит авторов между чтением при запросе и
+// предвычисленной лентой по числу их недавних постов и удаляет из
+// предвычисленных лент устаревшие записи. Каждый автор переключается в своей
+// транзакции, поэтому параллельный запуск на нескольких экземплярах безопасен.
+func (s *Service) RefreshTimelines(ctx context.Context) error {
+	entering, leaving, err := s.followStore.ListProlificChanges(ctx, s.cfg.ProlificPosts, s.cfg.ProlificWindow)
+	if err != nil {
+		return err
+	}
+
+	since := s.TimelineSince()
+
+	for _, author := range entering {
+		if err = s.followStore.SetProlificAuthor(ctx, author, true, since); err != nil {
+			return err
+		}
+	}
+	for _, author := range leaving {
+		if err = s.followStore.SetProlificAuthor(ctx, author, false, since); err != nil {
+			return err
+		}
+	}
+
+	pruned, err := s.followStore.PruneTimeline(ctx, since)
+	if err != nil {
+		return err
+	}
+
+	if len(entering) > 0 || len(leaving) > 0 || pruned > 0 {
+		s.logger.Info("timelines refreshed",
+			zap.Int("prolific_authors_added", len(entering)),
+			zap.Int("prolific_authors_removed", len(leaving)),
+			zap.Int64("entries_pruned", pruned),
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,237 @@
+package moderation_service
+
+import (
+	"context"
+	"fmt"
+	"github.com/AdilBaidual/baseProject/internal/auth"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/AdilBaidual/baseProject/pkg/pagination"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"slices"
+	"time"
+	"unicode/utf8"
+)
+
+const maxNoteLength = 1000
+
+type moderationStore interface {
+	CreateReport(ctx context.Context, report model.Report) (bool, error)
+	ListModerationQueue(ctx context.Context, targetType string, after *model.ModerationItem, limit int) ([]model.ModerationItem, error)
+	ApplyModerationAction(ctx context.Context, entry model.ModerationLogEntry) (model.ModerationLogEntry, error)
+	ListModerationLog(ctx context.Context, filter model.ModerationLogFilter, beforeID int64, limit int) ([]model.ModerationLogEntry, error)
+	GetPost(ctx context.Context, id int64) (model.Post, error)
+	GetComment(ctx context.Context, id int64) (model.Comment, error)
+}
+
+type cursor struct {
+	ID int64 `json:"id"`
+}
+
+// queueCursor позиция в очереди, упорядоченной по числу жалоб и времени первой жалобы.
+type queueCursor struct {
+	Reports         int64     `json:"n"`
+	FirstReportedAt time.Time `json:"t"`
+	TargetType      string    `json:"tt"`
+	TargetID        int64     `json:"id"`
+}
+
+type Service struct {
+	logger *zap.Logger
+
+	moderationStore moderationStore
+}
+
+func NewService(logger *zap.Logger, moderationStore moderationStore) *Service {
+	return &Service{
+		logger:          logger,
+		moderationStore: moderationStore,
+	}
+}
+
+// Report сохраняет жалобу читателя. Повторная жалоба на тот же контент, пока
+// предыдущая не рассмотрена, ничего не меняет.
+func (s *Service) Report(ctx context.Context, reporter auth.Identity, target model.ModerationTarget, reason string) error {
+	if !slices.Contains(model.ReportReasons, reason) {
+		return fmt.Errorf("%w: unknown report reason %q", model.ErrInvalidArgument, reason)
+	}
+
+	author, err := s.targetAuthor(ctx, target, reporter.UserUUID)
+	if err != nil {
+		return err
+	}
+
+	if author == reporter.UserUUID {
+		return fmt.Errorf("%w: you cannot report your own content", model.ErrInvalidArgument)
+	}
+
+	_, err = s.moderationStore.CreateReport(ctx, model.Report{
+		Target:       target,
+		ReporterUUID: reporter.UserUUID,
+		Reason:       reason,
+	})
+
+	return err
+}
+
+// ListQueue очередь модерации: контент с открытыми жалобами, сначала самый
+// обжалованный, при равенстве — ожидающий дольше. targetType = "" — посты и комментарии.
+func (s *Service) ListQueue(ctx context.Context, targetType string, pageSize int32, pageToken string) ([]model.ModerationItem, string, error) {
+	if targetType != "" {
+		if err := validateTargetType(targetType); err != nil {
+			return nil, "", err
+		}
+	}
+
+	var c queueCursor
+	if err := pagination.DecodeToken(pageToken, &c); err != nil {
+		return nil, "", fmt.Errorf("%w: %s", model.ErrInvalidArgument, err.Error())
+	}
+
+	var after *model.ModerationItem
+	if c.Reports > 0 {
+		after = &model.ModerationItem{
+			Target:          model.ModerationTarget{Type: c.TargetType, ID: c.TargetID},
+			Reports:         c.Reports,
+			FirstReportedAt: c.FirstReportedAt,
+		}
+	}
+
+	limit := pagination.PageSize(pageSize)
+
+	items, err := s.moderationStore.ListModerationQueue(ctx, targetType, after, limit+1)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var nextToken string
+	if len(items) > limit {
+		items = items[:limit]
+		last := items[limit-1]
+
+		next := queueCursor{
+			Reports:         last.Reports,
+			FirstReportedAt: last.FirstReportedAt,
+			TargetType:      last.Target.Type,
+			TargetID:        last.Target.ID,
+		}
+		if nextToken, err = pagination.EncodeToken(next); err != nil {
+			return nil, "", err
+		}
+	}
+
+	return items, nextToken, nil
+}
+
+// Moderate применяет действие модератора к посту или комментарию, закрывает
+// открытые жалобы на него и записывает действие в журнал модерации.
+func (s *Service) Moderate(ctx context.Context, moderator auth.Identity, target model.ModerationTarget, action, note string) (model.ModerationLogEntry, error) {
+	if !slices.Contains(model.ModerationActions, action) {
+		return model.ModerationLogEntry{}, fmt.Errorf("%w: unknown moderation action %q", model.ErrInvalidArgument, action)
+	}
+
+	if utf8.RuneCountInString(note) > maxNoteLength {
+		return model.ModerationLogEntry{}, fmt.Errorf("%w: note must be at most %d characters", model.ErrInvalidArgument, maxNoteLength)
+	}
+
+	author, err := s.targetAuthor(ctx, target, uuid.Nil)
+	if err != nil {
+		return model.ModerationLogEntry{}, err
+	}
+
+	entry, err := s.moderationStore.ApplyModerationAction(ctx, model.ModerationLogEntry{
+		Target:        target,
+		Action:        action,
+		ModeratorUUID: moderator.UserUUID,
+		AuthorUUID:    author,
+		Note:          note,
+	})
+	if err != nil {
+		return model.ModerationLogEntry{}, err
+	}
+
+	s.logger.Info("moderation action",
+		zap.String("action", action),
+		zap.String("target_type", target.Type),
+		zap.Int64("target_id", target.ID),
+		zap.String("moderator_uuid", moderator.UserUUID.String()),
+	)
+
+	return entry, nil
+}
+
+// ListLog журнал модерации от новых записей к старым.
+func (s *Service) ListLog(ctx context.Context, filter model.ModerationLogFilter, pageSize int32, pageToken string) ([]model.ModerationLogEntry, string, error) {
+	if filter.Target.Type != "" {
+		if err := validateTargetType(filter.Target.Type); err != nil {
+			return nil, "", err
+		}
+	}
+
+	var c cursor
+	if err := pagination.DecodeToken(pageToken, &c); err != nil {
+		return nil, "", fmt.Errorf("%w: %s", model.ErrInvalidArgument, err.Error())
+	}
+
+	limit := pagination.PageSize(pageSize)
+
+	entries, err := s.moderationStore.ListModerationLog(ctx, filter, c.ID, limit+1)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var nextToken string
+	if len(entries) > limit {
+		entries = entries[:limit]
+		if nextToken, err = pagination.EncodeToken(cursor{ID: entries[limit-1].ID}); err != nil {
+			return nil, "", err
+		}
+	}
+
+	return entries, nextToken, nil
+}
+
+// targetAuthor возвращает автора поста или комментария. Если задан viewer,
+// контент должен быть ему виден: на чужие черновики и скрытое модерацией
+// пожаловаться нельзя.
+func (s *Service) targetAuthor(ctx context.Context, target model.ModerationTarget, viewer uuid.UUID) (uuid.UUID, error) {
+	if err := validateTargetType(target.Type); err != nil {
+		return uuid.Nil, err
+	}
+
+	postID := target.ID
+
+	var author uuid.UUID
+	if target.Type == model.ReactionTargetComment {
+		comment, err := s.moderationStore.GetComment(ctx, target.ID)
+		if err != nil {
+			return uuid.Nil, err
+		}
+		if viewer != uuid.Nil && !comment.VisibleTo(viewer, false) {
+			return uuid.Nil, model.ErrNotFound
+		}
+		postID, author = comment.PostID, comment.AuthorUUID
+	}
+
+	post, err := s.moderationStore.GetPost(ctx, postID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	if viewer != uuid.Nil && !post.VisibleTo(viewer, false) {
+		return uuid.Nil, model.ErrNotFound
+	}
+
+	if target.Type == model.ReactionTargetPost {
+		author = post.AuthorUUID
+	}
+
+	return author, nil
+}
+
+func validateTargetType(targetType string) error {
+	if targetType != model.ReactionTargetPost && targetType != model.ReactionTargetComment {
+		return fmt.Errorf("%w: unknown moderation target %q", model.ErrInvalidArgument, targetType)
+	}
+	return nil
+}
@@ -0,0 +1,371 @@
+package notification_service
+
+import (
+	"context"
+	"fmt"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/AdilBaidual/baseProject/pkg/pagination"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"slices"
+	"sync"
+	"time"
+)
+
+// watchBatch сколько уведомлений поток WatchNotifications читает за раз.
+const watchBatch = 100
+
+type Config struct {
+	// PollInterval как часто открытый поток перечитывает уведомления сам, без
+	// сигнала из Postgres: страховка на время переподключения слушателя.
+	PollInterval time.Duration `yaml:"poll_interval"`
+	// ListenRetry пауза перед переподключением слушателя LISTEN.
+	ListenRetry time.Duration `yaml:"listen_retry"`
+}
+
+func (c Config) withDefaults() Config {
+	if c.PollInterval <= 0 {
+		c.PollInterval = 30 * time.Second
+	}
+	if c.ListenRetry <= 0 {
+		c.ListenRetry = 5 * time.Second
+	}
+	return c
+}
+
+type notificationStore interface {
+	CreateNotifications(ctx context.Context, notifications []model.Notification) (int64, error)
+	ListNotifications(ctx context.Context, userUUID uuid.UUID, unreadOnly bool, beforeID int64, limit int) ([]model.Notification, error)
+	ListNotificationsAfter(ctx context.Context, userUUID uuid.UUID, afterID int64, limit int) ([]model.Notification, error)
+	GetLatestNotificationID(ctx context.Context, userUUID uuid.UUID) (int64, error)
+	CountUnreadNotifications(ctx context.Context, userUUID uuid.UUID) (int64, error)
+	MarkNotificationsRead(ctx context.Context, userUUID uuid.UUID, ids []int64, all bool) (int64, error)
+	GetNotificationMutes(ctx context.Context, userUUID uuid.UUID) ([]string, error)
+	SetNotificationMutes(ctx context.Context, userUUID uuid.UUID, types []string) error
+	ListenNotifications(ctx context.Context, listening func(), notify func(userUUID uuid.UUID)) error
+	GetComment(ctx context.Context, id int64) (model.Comment, error)
+}
+
+type cursor struct {
+	ID int64 `json:"id"`
+}
+
+type Service struct {
+	logger *zap.Logger
+	cfg    Config
+
+	notificationStore notificationStore
+
+	// watchers открытые потоки WatchNotifications этого экземпляра по получателям.
+	mu       sync.Mutex
+	watchers map[uuid.UUID]map[chan struct{}]struct{}
+
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func NewService(logger *zap.Logger, cfg Config, notificationStore notificationStore) *Service {
+	return &Service{
+		logger:            logger,
+		cfg:               cfg.withDefaults(),
+		notificationStore: notificationStore,
+		watchers:          make(map[uuid.UUID]map[chan struct{}]struct{}),
+		closed:            make(chan struct{}),
+	}
+}
+
+// NotifyComment уведомляет о новом комментарии автора комментария, на
+// который ответили, и автора поста. Комментарии, скрытые модерацией или
+// написанные в теневом бане, уведомлений не создают.
+func (s *Service) NotifyComment(ctx context.Context, post model.Post, comment model.Comment) error {
+	if comment.HiddenAt != nil || comment.AuthorShadowBanned {
+		return nil
+	}
+
+	var notifications []model.Notification
+
+	replyTo := uuid.Nil
+	if comment.ParentID != 0 {
+		parent, err := s.notificationStore.GetComment(ctx, comment.ParentID)
+		if err != nil {
+			return err
+		}
+		replyTo = parent.AuthorUUID
+
+		notifications = append(notifications, model.Notification{
+			UserUUID:  replyTo,
+			Type:      model.NotificationReply,
+			ActorUUID: comment.AuthorUUID,
+			PostID:    post.ID,
+			CommentID: comment.ID,
+		})
+	}
+
+	// Автор поста, которому ответили на его же комментарий, получает только ответ.
+	if post.AuthorUUID != replyTo {
+		notifications = append(notifications, model.Notification{
+			UserUUID:  post.AuthorUUID,
+			Type:      model.NotificationComment,
+			ActorUUID: comment.AuthorUUID,
+			PostID:    post.ID,
+			CommentID: comment.ID,
+		})
+	}
+
+	return s.notify(ctx, notifications)
+}
+
+// NotifyMentions уведомляет упомянутых пользователей. commentID = 0 —
+// упоминание в самом посте.
+func (s *Service) NotifyMentions(ctx context.Context, actor uuid.UUID, users []uuid.UUID, postID, commentID int64) error {
+	notifications := make([]model.Notification, 0, len(users))
+	for _, user := range users {
+		notifications = append(notifications, model.Notification{
+			UserUUID:  user,
+			Type:      model.NotificationMention,
+			ActorUUID: actor,
+			PostID:    postID,
+			CommentID: commentID,
+		})
+	}
+
+	return s.notify(ctx, notifications)
+}
+
+// NotifyFollow уведомляет автора о новом подписчике.
+func (s *Service) NotifyFollow(ctx context.Context, follower, followee uuid.UUID) error {
+	return s.notify(ctx, []model.Notification{{
+		UserUUID:  followee,
+		Type:      model.NotificationFollow,
+		ActorUUID: follower,
+	}})
+}
+
+// notify сохраняет уведомления, кроме уведомлений о собственных действиях
+// и повторов одному получателю.
+func (s *Service) notify(ctx context.Context, notifications []model.Notification) error {
+	seen := make(map[uuid.UUID]struct{}, len(notifications))
+	filtered := notifications[:0]
+	for _, n := range notifications {
+		if _, ok := seen[n.UserUUID]; ok || n.UserUUID == uuid.Nil || n.UserUUID == n.ActorUUID {
+			continue
+		}
+		seen[n.UserUUID] = struct{}{}
+		filtered = append(filtered, n)
+	}
+
+	if len(filtered) == 0 {
+		return nil
+	}
+
+	_, err := s.notificationStore.CreateNotifications(ctx, filtered)
+	return err
+}
+
+// List возвращает уведомления от новых к старым и число непрочитанных.
+func (s *Service) List(ctx context.Context, userUUID uuid.UUID, unreadOnly bool, pageSize int32, pageToken string) ([]model.Notification, int64, string, error) {
+	var c cursor
+	if err := pagination.DecodeToken(pageToken, &c); err != nil {
+		return nil, 0, "", fmt.Errorf("%w: %s", model.ErrInvalidArgument, err.Error())
+	}
+
+	limit := pagination.PageSize(pageSize)
+
+	notifications, err := s.notificationStore.ListNotifications(ctx, userUUID, unreadOnly, c.ID, limit+1)
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	var nextToken string
+	if len(notifications) > limit {
+		notifications = notifications[:limit]
+
+		if nextToken, err = pagination.EncodeToken(cursor{ID: notifications[limit-1].ID}); err != nil {
+			return nil, 0, "", err
+		}
+	}
+
+	unread, err := s.notificationStore.CountUnreadNotifications(ctx, userUUID)
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	return notifications, unread, nextToken, nil
+}
+
+// MarkRead отмечает уведомления прочитанными и возвращает число оставшихся
+// непрочитанных. all — все уведомления пользователя.
+func (s *Service) MarkRead(ctx context.Context, userUUID uuid.UUID, ids []int64, all bool) (int64, error) {
+	if !all && len(ids) == 0 {
+		return 0, fmt.Errorf("%w: ids or all is required", model.ErrInvalidArgument)
+	}
+	if len(ids) > pagination.MaxPageSize {
+		return 0, fmt.Errorf("%w: at most %d ids", model.ErrInvalidArgument, pagination.MaxPageSize)
+	}
+
+	if _, err := s.notificationStore.MarkNotificationsRead(ctx, userUUID, ids, all); err != nil {
+		return 0, err
+	}
+
+	return s.notificationStore.CountUnreadNotifications(ctx, userUUID)
+}
+
+// Muted отключённые пользователем типы уведомлений.
+func (s *Service) Muted(ctx context.Context, userUUID uuid.UUID) ([]string, error) {
+	return s.notificationStore.GetNotificationMutes(ctx, userUUID)
+}
+
+// SetMuted заменяет набор отключённых типов. Уже созданные уведомления
+// отключённых типов остаются.
+func (s *Service) SetMuted(ctx context.Context, userUUID uuid.UUID, types []string) ([]string, error) {
+	muted := make([]string, 0, len(types))
+	for _, t := range types {
+		if !slices.Contains(model.NotificationTypes, t) {
+			return nil, fmt.Errorf("%w: unknown notification type %q", model.ErrInvalidArgument, t)
+		}
+		if !slices.Contains(muted, t) {
+			muted = append(muted, t)
+		}
+	}
+
+	if err := s.notificationStore.SetNotificationMutes(ctx, userUUID, muted); err != nil {
+		return nil, err
+	}
+
+	slices.Sort(muted)
+	return muted, nil
+}
+
+// Watch передаёт в send уведомления пользователя новее afterID по мере их
+// появления, пока не отменён ctx. afterID = 0 — только уведомления,
+// созданные после начала наблюдения. Перед каждым перечитыванием вызывается
+// check: его ошибка, например из-за отозванной сессии, завершает поток.
+func (s *Service) Watch(ctx context.Context, userUUID uuid.UUID, afterID int64, check func(context.Context) error, send func(model.Notification) error) error {
+	// Подписка оформляется до чтения, чтобы не пропустить сигнал между ними.
+	wake, unsubscribe := s.subscribe(userUUID)
+	defer unsubscribe()
+
+	lastID := afterID
+	if lastID <= 0 {
+		latest, err := s.notificationStore.GetLatestNotificationID(ctx, userUUID)
+		if err != nil {
+			return err
+		}
+		lastID = latest
+	}
+
+	ticker := time.NewTicker(s.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			notifications, err := s.notificationStore.ListNotificationsAfter(ctx, userUUID, lastID, watchBatch)
+			if err != nil {
+				return err
+			}
+
+			for _, n := range notifications {
+				if err = send(n); err != nil {
+					return err
+				}
+				lastID = n.ID
+			}
+
+			if len(notifications) < watchBatch {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-s.closed:
+			return nil
+		case <-wake:
+		case <-ticker.C:
+		}
+
+		if err := check(ctx); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// Listen слушает новые уведомления в Postgres и будит потоки их получателей,
+// пока не отменён ctx. После переподключения будятся все потоки: сигналы,
+// пришедшие без соединения, потеряны.
+func (s *Service) Listen(ctx context.Context) {
+	for {
+		err := s.notificationStore.ListenNotifications(ctx, s.wakeAll, s.wake)
+		if ctx.Err() != nil {
+			return
+		}
+		s.logger.Error("notification listener disconnected", zap.Error(err))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.cfg.ListenRetry):
+		}
+	}
+}
+
+// Close завершает открытые потоки WatchNotifications. Потоки бессрочные,
+// поэтому без этого плавная остановка сервера ждала бы их до таймаута.
+func (s *Service) Close() {
+	s.closeOnce.Do(func() {
+		close(s.closed)
+	})
+}
+
+func (s *Service) subscribe(userUUID uuid.UUID) (<-chan struct{}, func()) {
+	// Буфер в один сигнал: несколько уведомлений подряд будят поток один раз.
+	ch := make(chan struct{}, 1)
+
+	s.mu.Lock()
+	if s.watchers[userUUID] == nil {
+		s.watchers[userUUID] = make(map[chan struct{}]struct{})
+	}
+	s.watchers[userUUID][ch] = struct{}{}
+	s.mu.Unlock()
+
+	return ch, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		delete(s.watchers[userUUID], ch)
+		if len(s.watchers[userUUID]) == 0 {
+			delete(s.watchers, userUUID)
+		}
+	}
+}
+
+func (s *Service) wake(userUUID uuid.UUID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for ch := range s.watchers[userUUID] {
+		signal(ch)
+	}
+}
+
+func (s *Service) wakeAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, chs := range s.watchers {
+		for ch := range chs {
+			signal(ch)
+		}
+	}
+}
+
+func signal(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,333 @@
+package policy_service
+
+import (
+	"context"
+	"fmt"
+	"github.com/AdilBaidual/baseProject/internal/auth"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/ext"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	maxRuleNameLength        = 100
+	maxRuleDescriptionLength = 1000
+	maxRuleExpressionLength  = 4000
+)
+
+var linkRe = regexp.MustCompile(`(?i)` + model.LinkPattern)
+
+type Config struct {
+	// ReloadInterval период перечитывания правил из Postgres. Изменения через API
+	// применяются сразу на том экземпляре, который их принял.
+	ReloadInterval time.Duration `yaml:"reload_interval"`
+	// CostLimit предел стоимости вычисления одного правила, защищает от тяжёлых выражений.
+	CostLimit uint64 `yaml:"cost_limit"`
+}
+
+func (c Config) withDefaults() Config {
+	if c.ReloadInterval <= 0 {
+		c.ReloadInterval = 30 * time.Second
+	}
+	if c.CostLimit == 0 {
+		c.CostLimit = 10_000
+	}
+	return c
+}
+
+type policyStore interface {
+	ListContentRules(ctx context.Context, enabledOnly bool) ([]model.ContentRule, error)
+	CreateContentRule(ctx context.Context, rule model.ContentRule) (model.ContentRule, error)
+	UpdateContentRule(ctx context.Context, rule model.ContentRule) (model.ContentRule, error)
+	DeleteContentRule(ctx context.Context, id int64) error
+	GetUserByUUID(ctx context.Context, id uuid.UUID) (model.User, error)
+	GetAuthorActivity(ctx context.Context, authorUUID uuid.UUID) (model.AuthorActivity, error)
+}
+
+type compiledRule struct {
+	rule    model.ContentRule
+	program cel.Program
+}
+
+type Service struct {
+	logger *zap.Logger
+	cfg    Config
+
+	policyStore policyStore
+	env         *cel.Env
+	rules       atomic.Pointer[[]compiledRule]
+	// reloadMu не даёт перечитываниям по таймеру и после изменения через API
+	// разойтись: иначе более медленное сохранит устаревший набор поверх нового.
+	reloadMu sync.Mutex
+}
+
+func NewService(logger *zap.Logger, cfg Config, policyStore policyStore) (*Service, error) {
+	env, err := newEnv()
+	if err != nil {
+		return nil, fmt.Errorf("policy_service.NewService - newEnv - %w", err)
+	}
+
+	s := &Service{
+		logger:      logger,
+		cfg:         cfg.withDefaults(),
+		policyStore: policyStore,
+		env:         env,
+	}
+	s.rules.Store(&[]compiledRule{})
+
+	return s, nil
+}
+
+// newEnv описывает переменные, доступные в выражениях правил:
+//
+//	author      — uuid, roles, email_verified, account_state, api_key
+//	account_age — время с регистрации автора (duration)
+//	content     — kind ("post" или "comment"), title, text, length, links, uppercase_ratio
+//	activity    — posts_last_hour, posts_last_day, comments_last_hour, comments_last_day, links_last_hour
+func newEnv() (*cel.Env, error) {
+	return cel.NewEnv(
+		cel.Variable("author", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("account_age", cel.DurationType),
+		cel.Variable("content", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("activity", cel.MapType(cel.StringType, cel.IntType)),
+		ext.Strings(),
+	)
+}
+
+// ReloadInterval период вызова Reload.
+func (s *Service) ReloadInterval() time.Duration {
+	return s.cfg.ReloadInterval
+}
+
+// Reload перечитывает включённые правила и атомарно заменяет действующий набор.
+// Правило, которое не удалось скомпилировать, пропускается.
+func (s *Service) Reload(ctx context.Context) error {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	rules, err := s.policyStore.ListContentRules(ctx, true)
+	if err != nil {
+		return err
+	}
+
+	compiled := make([]compiledRule, 0, len(rules))
+	for _, rule := range rules {
+		program, err := s.compile(rule.Expression)
+		if err != nil {
+			s.logger.Error("skipping content rule", zap.Error(err), zap.Int64("rule_id", rule.ID), zap.String("rule", rule.Name))
+			continue
+		}
+		compiled = append(compiled, compiledRule{rule: rule, program: program})
+	}
+
+	s.rules.Store(&compiled)
+
+	return nil
+}
+
+// Evaluate проверяет контент правилами в порядке приоритета. Решение принимает
+// первое сработавшее правило, если не сработало ни одно — контент пропускается.
+// Правило, вычисление которого завершилось ошибкой, считается несработавшим.
+func (s *Service) Evaluate(ctx context.Context, author auth.Identity, content model.PolicyContent) (model.PolicyDecision, error) {
+	rules := *s.rules.Load()
+	if len(rules) == 0 {
+		return model.PolicyDecision{Action: model.PolicyActionAllow}, nil
+	}
+
+	vars, err := s.variables(ctx, author, content)
+	if err != nil {
+		return model.PolicyDecision{}, err
+	}
+
+	for _, r := range rules {
+		out, _, err := r.program.ContextEval(ctx, vars)
+		if err != nil {
+			if ctx.Err() != nil {
+				return model.PolicyDecision{}, ctx.Err()
+			}
+			s.logger.Warn("content rule evaluation failed", zap.Error(err), zap.Int64("rule_id", r.rule.ID), zap.String("rule", r.rule.Name))
+			continue
+		}
+
+		if out == types.True {
+			return model.PolicyDecision{Action: r.rule.Action, RuleID: r.rule.ID, RuleName: r.rule.Name}, nil
+		}
+	}
+
+	return model.PolicyDecision{Action: model.PolicyActionAllow}, nil
+}
+
+func (s *Service) variables(ctx context.Context, author auth.Identity, content model.PolicyContent) (map[string]any, error) {
+	user, err := s.policyStore.GetUserByUUID(ctx, author.UserUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	activity, err := s.policyStore.GetAuthorActivity(ctx, author.UserUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	text := content.Title + " " + content.Text
+
+	return map[string]any{
+		"author": map[string]any{
+			"uuid":           author.UserUUID.String(),
+			"roles":          author.Roles,
+			"email_verified": author.EmailVerified,
+			"account_state":  author.AccountState,
+			"api_key":        author.ViaAPIKey(),
+		},
+		"account_age": time.Since(user.CreatedAt),
+		"content": map[string]any{
+			"kind":            content.Kind,
+			"title":           content.Title,
+			"text":            content.Text,
+			"length":          int64(utf8.RuneCountInString(content.Text)),
+			"links":           int64(len(linkRe.FindAllStringIndex(text, -1))),
+			"uppercase_ratio": uppercaseRatio(content.Text),
+		},
+		"activity": map[string]int64{
+			"posts_last_hour":    activity.PostsLastHour,
+			"posts_last_day":     activity.PostsLastDay,
+			"comments_last_hour": activity.CommentsLastHour,
+			"comments_last_day":  activity.CommentsLastDay,
+			"links_last_hour":    activity.LinksLastHour,
+		},
+	}, nil
+}
+
+// ListRules возвращает все правила, включая выключенные, в порядке проверки.
+func (s *Service) ListRules(ctx context.Context) ([]model.ContentRule, error) {
+	return s.policyStore.ListContentRules(ctx, false)
+}
+
+// CreateRule проверяет выражение и сохраняет правило. Набор правил
+// перечитывается сразу, остальные экземпляры подхватят его по таймеру.
+func (s *Service) CreateRule(ctx context.Context, actorUUID uuid.UUID, rule model.ContentRule) (model.ContentRule, error) {
+	rule, err := s.validateRule(rule)
+	if err != nil {
+		return model.ContentRule{}, err
+	}
+
+	rule.CreatedBy = actorUUID
+
+	created, err := s.policyStore.CreateContentRule(ctx, rule)
+	if err != nil {
+		return model.ContentRule{}, err
+	}
+
+	s.reloadAfterChange(ctx)
+
+	return created, nil
+}
+
+func (s *Service) UpdateRule(ctx context.Context, rule model.ContentRule) (model.ContentRule, error) {
+	rule, err := s.validateRule(rule)
+	if err != nil {
+		return model.ContentRule{}, err
+	}
+
+	updated, err := s.policyStore.UpdateContentRule(ctx, rule)
+	if err != nil {
+		return model.ContentRule{}, err
+	}
+
+	s.reloadAfterChange(ctx)
+
+	return updated, nil
+}
+
+func (s *Service) DeleteRule(ctx context.Context, id int64) error {
+	if err := s.policyStore.DeleteContentRule(ctx, id); err != nil {
+		return err
+	}
+
+	s.reloadAfterChange(ctx)
+
+	return nil
+}
+
+// reloadAfterChange изменение уже сохранено, поэтому ошибка перечитывания
+// только журналируется: правила подхватит следующий Reload по таймеру.
+func (s *Service) reloadAfterChange(ctx context.Context) {
+	if err := s.Reload(context.WithoutCancel(ctx)); err != nil {
+		s.logger.Error("error reloading content rules", zap.Error(err))
+	}
+}
+
+func (s *Service) validateRule(rule model.ContentRule) (model.ContentRule, error) {
+	rule.Name = strings.TrimSpace(rule.Name)
+	rule.Description = strings.TrimSpace(rule.Description)
+	rule.Expression = strings.TrimSpace(rule.Expression)
+
+	if rule.Name == "" || utf8.RuneCountInString(rule.Name) > maxRuleNameLength {
+		return model.ContentRule{}, fmt.Errorf("%w: name must be 1..%d characters", model.ErrInvalidArgument, maxRuleNameLength)
+	}
+	if utf8.RuneCountInString(rule.Description) > maxRuleDescriptionLength {
+		return model.ContentRule{}, fmt.Errorf("%w: description must be at most %d characters", model.ErrInvalidArgument, maxRuleDescriptionLength)
+	}
+	if !slices.Contains(model.PolicyActions, rule.Action) {
+		return model.ContentRule{}, fmt.Errorf("%w: unknown action %q", model.ErrInvalidArgument, rule.Action)
+	}
+	if rule.Expression == "" || len(rule.Expression) > maxRuleExpressionLength {
+		return model.ContentRule{}, fmt.Errorf("%w: expression must be 1..%d bytes", model.ErrInvalidArgument, maxRuleExpressionLength)
+	}
+
+	if _, err := s.compile(rule.Expression); err != nil {
+		return model.ContentRule{}, err
+	}
+
+	return rule, nil
+}
+
+// compile проверяет типы выражения: результат должен быть bool.
+func (s *Service) compile(expression string) (cel.Program, error) {
+	ast, issues := s.env.Compile(expression)
+	if issues.Err() != nil {
+		return nil, fmt.Errorf("%w: invalid expression: %s", model.ErrInvalidArgument, issues.Err().Error())
+	}
+
+	if out := ast.OutputType(); !out.IsExactType(cel.BoolType) && !out.IsExactType(cel.DynType) {
+		return nil, fmt.Errorf("%w: expression must evaluate to bool, got %s", model.ErrInvalidArgument, out)
+	}
+
+	program, err := s.env.Program(ast,
+		cel.CostLimit(s.cfg.CostLimit),
+		cel.InterruptCheckFrequency(100),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid expression: %s", model.ErrInvalidArgument, err.Error())
+	}
+
+	return program, nil
+}
+
+// uppercaseRatio доля заглавных среди букв текста.
+func uppercaseRatio(text string) float64 {
+	var letters, upper int
+	for _, r := range text {
+		if unicode.IsLetter(r) {
+			letters++
+			if unicode.IsUpper(r) {
+				upper++
+			}
+		}
+	}
+
+	if letters == 0 {
+		return 0
+	}
+	return float64(upper) / float64(letters)
+}
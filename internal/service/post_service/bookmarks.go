@@ -0,0 +1,54 @@
+package post_service
+
+import (
+	"context"
+	"fmt"
+	"github.com/AdilBaidual/baseProject/internal/auth"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/AdilBaidual/baseProject/pkg/pagination"
+	"time"
+)
+
+// bookmarkCursor позиция в списке закладок, который упорядочен по времени добавления.
+type bookmarkCursor struct {
+	BookmarkedAt *time.Time `json:"t,omitempty"`
+	ID           int64      `json:"id"`
+}
+
+// Bookmarks посты из закладок читателя от новых закладок к старым,
+// collectionID = 0 — из всех коллекций.
+func (s *Service) Bookmarks(ctx context.Context, viewer auth.Identity, collectionID int64, pageSize int32, pageToken string) ([]model.Post, string, error) {
+	var c bookmarkCursor
+	if err := pagination.DecodeToken(pageToken, &c); err != nil {
+		return nil, "", fmt.Errorf("%w: %s", model.ErrInvalidArgument, err.Error())
+	}
+
+	if collectionID != 0 {
+		if _, err := s.postStore.GetBookmarkCollection(ctx, viewer.UserUUID, collectionID); err != nil {
+			return nil, "", err
+		}
+	}
+
+	limit := pagination.PageSize(pageSize)
+
+	posts, err := s.postStore.ListBookmarkedPosts(ctx, viewer.UserUUID, collectionID, c.BookmarkedAt, c.ID, limit+1)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var nextToken string
+	if len(posts) > limit {
+		posts = posts[:limit]
+		last := posts[limit-1]
+
+		if nextToken, err = pagination.EncodeToken(bookmarkCursor{BookmarkedAt: last.BookmarkedAt, ID: last.ID}); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if err = s.enrichPosts(ctx, viewer.UserUUID, posts); err != nil {
+		return nil, "", err
+	}
+
+	return posts, nextToken, nil
+}
@@ -0,0 +1,142 @@
+package post_service
+
+import (
+	"context"
+	"fmt"
+	"github.com/AdilBaidual/baseProject/internal/auth"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"go.uber.org/zap"
+	"time"
+)
+
+// Publication определяет, когда пост становится виден всем. Draft оставляет пост
+// черновиком, PublishAt в будущем откладывает публикацию, UnpublishAt снимает пост
+// с публикации в заданное время.
+type Publication struct {
+	Draft       bool
+	PublishAt   *time.Time
+	UnpublishAt *time.Time
+}
+
+func (p Publication) status(now time.Time) (string, error) {
+	if p.Draft {
+		if p.PublishAt != nil || p.UnpublishAt != nil {
+			return "", fmt.Errorf("%w: draft cannot have publish or unpublish time", model.ErrInvalidArgument)
+		}
+		return model.PostStatusDraft, nil
+	}
+
+	if p.UnpublishAt != nil {
+		if !p.UnpublishAt.After(now) {
+			return "", fmt.Errorf("%w: unpublish time must be in the future", model.ErrInvalidArgument)
+		}
+		if p.PublishAt != nil && !p.UnpublishAt.After(*p.PublishAt) {
+			return "", fmt.Errorf("%w: unpublish time must be after publish time", model.ErrInvalidArgument)
+		}
+	}
+
+	if p.PublishAt != nil && p.PublishAt.After(now) {
+		return model.PostStatusScheduled, nil
+	}
+
+	return model.PostStatusPublished, nil
+}
+
+// PublishPost публикует черновик или архивный пост сразу либо в publishAt.
+// Для запланированного поста меняет время публикации.
+func (s *Service) PublishPost(ctx context.Context, editor auth.Identity, id int64, publishAt, unpublishAt *time.Time) (model.Post, error) {
+	if _, err := s.editablePost(ctx, editor, id); err != nil {
+		return model.Post{}, err
+	}
+
+	publication := Publication{PublishAt: publishAt, UnpublishAt: unpublishAt}
+
+	status, err := publication.status(time.Now())
+	if err != nil {
+		return model.Post{}, err
+	}
+
+	if status == model.PostStatusPublished {
+		publishAt = nil
+	}
+
+	post, err := s.postStore.TransitionPost(ctx, id,
+		[]string{model.PostStatusDraft, model.PostStatusScheduled, model.PostStatusArchived},
+		model.Post{Status: status, PublishAt: publishAt, UnpublishAt: unpublishAt},
+	)
+	if err != nil {
+		return model.Post{}, err
+	}
+
+	s.notifyPostMentions(ctx, post.ID)
+
+	posts := []model.Post{post}
+	if err = s.enrichPosts(ctx, editor.UserUUID, posts); err != nil {
+		return model.Post{}, err
+	}
+
+	return posts[0], nil
+}
+
+// UnpublishPost снимает опубликованный пост в архив, а запланированный
+// возвращает в черновики.
+func (s *Service) UnpublishPost(ctx context.Context, editor auth.Identity, id int64) (model.Post, error) {
+	existing, err := s.editablePost(ctx, editor, id)
+	if err != nil {
+		return model.Post{}, err
+	}
+
+	var to string
+	switch existing.Status {
+	case model.PostStatusPublished:
+		to = model.PostStatusArchived
+	case model.PostStatusScheduled:
+		to = model.PostStatusDraft
+	default:
+		return model.Post{}, fmt.Errorf("%w: post is %s", model.ErrInvalidArgument, existing.Status)
+	}
+
+	post, err := s.postStore.TransitionPost(ctx, id, []string{existing.Status}, model.Post{Status: to})
+	if err != nil {
+		return model.Post{}, err
+	}
+
+	posts := []model.Post{post}
+	if err = s.enrichPosts(ctx, editor.UserUUID, posts); err != nil {
+		return model.Post{}, err
+	}
+
+	return posts[0], nil
+}
+
+// SchedulerInterval период запуска ApplySchedule.
+func (s *Service) SchedulerInterval() time.Duration {
+	return s.cfg.SchedulerInterval
+}
+
+// ApplySchedule публикует посты, у которых наступил publish_at, архивирует
+// посты с наступившим unpublish_at и рассылает уведомления об упоминаниях в
+// постах, ставших видимыми. Несколько экземпляров сервиса могут вызывать
+// его одновременно: очередь обрабатывает тот, кто взял advisory-блокировку.
+func (s *Service) ApplySchedule(ctx context.Context) error {
+	for {
+		published, archived, locked, err := s.postStore.ApplyPostSchedule(ctx, s.cfg.SchedulerBatch)
+		if err != nil {
+			return err
+		}
+
+		if !locked {
+			break
+		}
+
+		if published > 0 || archived > 0 {
+			s.logger.Info("post schedule applied", zap.Int("published", published), zap.Int("archived", archived))
+		}
+
+		if published < s.cfg.SchedulerBatch && archived < s.cfg.SchedulerBatch {
+			break
+		}
+	}
+
+	return s.notifyPendingMentions(ctx)
+}
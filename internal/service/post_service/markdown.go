@@ -0,0 +1,128 @@
+package post_service
+
+import (
+	"context"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/AdilBaidual/baseProject/pkg/markdown"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"html"
+)
+
+// profilePath адрес профиля для ссылок из упоминаний. Ссылка ведёт по
+// идентификатору и не ломается, когда пользователь меняет имя.
+const profilePath = "/users/"
+
+func mentionLinks(mentions []model.Mention) map[string]string {
+	links := make(map[string]string, len(mentions))
+	for _, m := range mentions {
+		links[m.Handle] = profilePath + m.UserUUID.String()
+	}
+
+	return links
+}
+
+// renderPost строит HTML поста из Markdown перед сохранением. Упоминания
+// должны быть уже в post.Mentions.
+func renderPost(post *model.Post) error {
+	rendered, err := markdown.Render(post.Content, mentionLinks(post.Mentions))
+	if err != nil {
+		return err
+	}
+
+	post.ContentHTML = rendered
+	post.ContentHTMLVersion = markdown.Version
+
+	return nil
+}
+
+func renderComment(comment *model.Comment) error {
+	rendered, err := markdown.Render(comment.Content, mentionLinks(comment.Mentions))
+	if err != nil {
+		return err
+	}
+
+	comment.ContentHTML = rendered
+	comment.ContentHTMLVersion = markdown.Version
+
+	return nil
+}
+
+// freshPosts перестраивает HTML, сохранённый прежней версией рендерера, и
+// записывает его обратно. Ошибка записи только журналируется: читатель всё
+// равно получает актуальный HTML, а строку перестроит следующее чтение.
+func (s *Service) freshPosts(ctx context.Context, posts []model.Post) error {
+	var stale []int64
+	for _, post := range posts {
+		if post.ContentHTMLVersion != markdown.Version {
+			stale = append(stale, post.ID)
+		}
+	}
+	if len(stale) == 0 {
+		return nil
+	}
+
+	mentions, err := s.postStore.GetPostMentions(ctx, stale)
+	if err != nil {
+		return err
+	}
+
+	for i := range posts {
+		if posts[i].ContentHTMLVersion == markdown.Version {
+			continue
+		}
+
+		posts[i].Mentions = mentions[posts[i].ID]
+		if err = renderPost(&posts[i]); err != nil {
+			return err
+		}
+
+		if err = s.postStore.SavePostContentHTML(ctx, posts[i]); err != nil {
+			s.logger.Error("error saving post html", zap.Error(err), zap.Int64("post_id", posts[i].ID))
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) freshComments(ctx context.Context, comments []model.Comment) error {
+	var stale []int64
+	for _, comment := range comments {
+		if comment.ContentHTMLVersion != markdown.Version {
+			stale = append(stale, comment.ID)
+		}
+	}
+	if len(stale) == 0 {
+		return nil
+	}
+
+	mentions, err := s.postStore.GetCommentMentions(ctx, stale)
+	if err != nil {
+		return err
+	}
+
+	for i := range comments {
+		if comments[i].ContentHTMLVersion == markdown.Version {
+			continue
+		}
+
+		comments[i].Mentions = mentions[comments[i].ID]
+		if err = renderComment(&comments[i]); err != nil {
+			return err
+		}
+
+		if err = s.postStore.SaveCommentContentHTML(ctx, comments[i]); err != nil {
+			s.logger.Error("error saving comment html", zap.Error(err), zap.Int64("comment_id", comments[i].ID))
+		}
+	}
+
+	return nil
+}
+
+// placeholder заменяет содержимое комментария заглушкой и скрывает автора.
+func placeholder(comment *model.Comment, text string) {
+	comment.AuthorUUID = uuid.Nil
+	comment.Content = text
+	comment.ContentHTML = html.EscapeString(text)
+	comment.ContentHTMLVersion = markdown.Version
+}
@@ -0,0 +1,90 @@
+package post_service
+
+import (
+	"context"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/AdilBaidual/baseProject/pkg/markdown"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// maxMentions сколько разных упоминаний в одном тексте становятся ссылками
+// и уведомлениями, остальные остаются текстом.
+const maxMentions = 20
+
+// resolveMentions находит пользователей, упомянутых в content.
+func (s *Service) resolveMentions(ctx context.Context, content string) ([]model.Mention, error) {
+	handles := markdown.Mentions(content)
+	if len(handles) == 0 {
+		return nil, nil
+	}
+	if len(handles) > maxMentions {
+		handles = handles[:maxMentions]
+	}
+
+	return s.postStore.ResolveUsernames(ctx, handles)
+}
+
+// notifyPostMentions уведомляет упомянутых в посте, если пост уже виден всем.
+// Пост к этому моменту сохранён, поэтому сбой только журналируется.
+func (s *Service) notifyPostMentions(ctx context.Context, postID int64) {
+	if _, err := s.sendPostMentions(ctx, postID, maxMentions); err != nil {
+		s.logger.Error("failed to create mention notifications", zap.Error(err), zap.Int64("post_id", postID))
+	}
+}
+
+// notifyPendingMentions уведомляет об упоминаниях в постах, которые стали
+// видны без правки: опубликованы по расписанию или одобрены модератором.
+func (s *Service) notifyPendingMentions(ctx context.Context) error {
+	for {
+		sent, err := s.sendPostMentions(ctx, 0, s.cfg.SchedulerBatch)
+		if err != nil {
+			return err
+		}
+
+		if sent < s.cfg.SchedulerBatch {
+			return nil
+		}
+	}
+}
+
+// sendPostMentions забирает не более limit неотправленных упоминаний в
+// видимых постах (postID = 0 — в любых) и создаёт по ним уведомления.
+func (s *Service) sendPostMentions(ctx context.Context, postID int64, limit int) (int, error) {
+	claimed, err := s.postStore.ClaimPostMentions(ctx, postID, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	users := make(map[int64][]uuid.UUID)
+	authors := make(map[int64]uuid.UUID)
+	for _, n := range claimed {
+		users[n.PostID] = append(users[n.PostID], n.UserUUID)
+		authors[n.PostID] = n.ActorUUID
+	}
+
+	for id, mentioned := range users {
+		if err = s.notificationService.NotifyMentions(ctx, authors[id], mentioned, id, 0); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(claimed), nil
+}
+
+// notifyCommentMentions уведомляет упомянутых в новом комментарии. Как и
+// уведомления об ответах, не создаётся для скрытых комментариев.
+func (s *Service) notifyCommentMentions(ctx context.Context, comment model.Comment) {
+	if len(comment.Mentions) == 0 || comment.HiddenAt != nil || comment.AuthorShadowBanned {
+		return
+	}
+
+	users := make([]uuid.UUID, 0, len(comment.Mentions))
+	for _, m := range comment.Mentions {
+		users = append(users, m.UserUUID)
+	}
+
+	if err := s.notificationService.NotifyMentions(ctx, comment.AuthorUUID, users, comment.PostID, comment.ID); err != nil {
+		s.logger.Error("failed to create mention notifications", zap.Error(err), zap.Int64("comment_id", comment.ID))
+	}
+}
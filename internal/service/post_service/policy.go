@@ -0,0 +1,42 @@
+package post_service
+
+import (
+	"context"
+	"fmt"
+	"github.com/AdilBaidual/baseProject/internal/auth"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"go.uber.org/zap"
+)
+
+type policyService interface {
+	Evaluate(ctx context.Context, author auth.Identity, content model.PolicyContent) (model.PolicyDecision, error)
+}
+
+// checkPolicy проверяет новый контент правилами контент-политики. Возвращает
+// решение, если контент нужно задержать до проверки модератором, nil — если
+// его можно публиковать, и ошибку, если правило его отклонило.
+func (s *Service) checkPolicy(ctx context.Context, author auth.Identity, content model.PolicyContent) (*model.PolicyDecision, error) {
+	decision, err := s.policyService.Evaluate(ctx, author, content)
+	if err != nil {
+		return nil, err
+	}
+
+	switch decision.Action {
+	case model.PolicyActionReject:
+		s.logger.Info("content rejected by policy",
+			zap.String("kind", content.Kind),
+			zap.String("author_uuid", author.UserUUID.String()),
+			zap.String("rule", decision.RuleName),
+		)
+		return nil, fmt.Errorf("%w: content rejected by content policy", model.ErrPermissionDenied)
+	case model.PolicyActionHold:
+		s.logger.Info("content held for review",
+			zap.String("kind", content.Kind),
+			zap.String("author_uuid", author.UserUUID.String()),
+			zap.String("rule", decision.RuleName),
+		)
+		return &decision, nil
+	}
+
+	return nil, nil
+}
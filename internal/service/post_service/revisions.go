@@ -0,0 +1,141 @@
+package post_service
+
+import (
+	"context"
+	"fmt"
+	"github.com/AdilBaidual/baseProject/internal/auth"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/AdilBaidual/baseProject/pkg/linediff"
+	"github.com/AdilBaidual/baseProject/pkg/pagination"
+)
+
+type revisionCursor struct {
+	Version int32 `json:"v"`
+}
+
+// RevisionDiff построчная разница между двумя ревизиями поста.
+type RevisionDiff struct {
+	From    model.PostRevision
+	To      model.PostRevision
+	Title   []linediff.Line
+	Content []linediff.Line
+}
+
+// ListRevisions история правок от новых к старым. Историю видят те, кто может
+// редактировать пост: в ней может остаться удалённый из поста текст.
+func (s *Service) ListRevisions(ctx context.Context, editor auth.Identity, postID int64, pageSize int32, pageToken string) ([]model.PostRevision, string, error) {
+	if _, err := s.editablePost(ctx, editor, postID); err != nil {
+		return nil, "", err
+	}
+
+	var c revisionCursor
+	if err := pagination.DecodeToken(pageToken, &c); err != nil {
+		return nil, "", fmt.Errorf("%w: %s", model.ErrInvalidArgument, err.Error())
+	}
+
+	limit := pagination.PageSize(pageSize)
+
+	revisions, err := s.postStore.ListRevisions(ctx, postID, c.Version, limit+1)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var nextToken string
+	if len(revisions) > limit {
+		revisions = revisions[:limit]
+		if nextToken, err = pagination.EncodeToken(revisionCursor{Version: revisions[limit-1].Version}); err != nil {
+			return nil, "", err
+		}
+	}
+
+	return revisions, nextToken, nil
+}
+
+func (s *Service) GetRevision(ctx context.Context, editor auth.Identity, postID int64, version int32) (model.PostRevision, error) {
+	if _, err := s.editablePost(ctx, editor, postID); err != nil {
+		return model.PostRevision{}, err
+	}
+
+	return s.postStore.GetRevision(ctx, postID, version)
+}
+
+// DiffRevisions сравнивает ревизию from с ревизией to. to = 0 — текущая версия поста.
+func (s *Service) DiffRevisions(ctx context.Context, editor auth.Identity, postID int64, from, to int32) (RevisionDiff, error) {
+	post, err := s.editablePost(ctx, editor, postID)
+	if err != nil {
+		return RevisionDiff{}, err
+	}
+
+	if to == 0 {
+		to = post.Version
+	}
+
+	fromRevision, err := s.postStore.GetRevision(ctx, postID, from)
+	if err != nil {
+		return RevisionDiff{}, err
+	}
+
+	toRevision, err := s.postStore.GetRevision(ctx, postID, to)
+	if err != nil {
+		return RevisionDiff{}, err
+	}
+
+	return RevisionDiff{
+		From:    fromRevision,
+		To:      toRevision,
+		Title:   linediff.Diff(fromRevision.Title, toRevision.Title),
+		Content: linediff.Diff(fromRevision.Content, toRevision.Content),
+	}, nil
+}
+
+// RestoreRevision возвращает посту заголовок и текст ревизии version. История
+// не переписывается: восстановление сохраняется как новая ревизия. expectedVersion
+// защищает от одновременной правки так же, как в UpdatePost.
+func (s *Service) RestoreRevision(ctx context.Context, editor auth.Identity, postID int64, version, expectedVersion int32) (model.Post, error) {
+	if expectedVersion <= 0 {
+		return model.Post{}, fmt.Errorf("%w: expected version is required", model.ErrInvalidArgument)
+	}
+
+	current, err := s.editablePost(ctx, editor, postID)
+	if err != nil {
+		return model.Post{}, err
+	}
+
+	revision, err := s.postStore.GetRevision(ctx, postID, version)
+	if err != nil {
+		return model.Post{}, err
+	}
+
+	tags, err := s.postStore.GetPostTags(ctx, []int64{postID})
+	if err != nil {
+		return model.Post{}, err
+	}
+
+	restored := current
+	restored.Title = revision.Title
+	restored.Content = revision.Content
+	restored.Tags = tags[postID]
+	restored.Version = expectedVersion
+
+	if restored.Mentions, err = s.resolveMentions(ctx, restored.Content); err != nil {
+		return model.Post{}, err
+	}
+
+	if err = renderPost(&restored); err != nil {
+		return model.Post{}, err
+	}
+
+	post, err := s.postStore.UpdatePost(ctx, restored, editor.UserUUID, revision.Version)
+	if err != nil {
+		return model.Post{}, err
+	}
+
+	s.notifyPostMentions(ctx, post.ID)
+
+	posts := []model.Post{post}
+	if err = s.enrichPosts(ctx, editor.UserUUID, posts); err != nil {
+		return model.Post{}, err
+	}
+
+	return posts[0], nil
+}
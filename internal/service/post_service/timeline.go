@@ -0,0 +1,41 @@
+package post_service
+
+import (
+	"context"
+	"fmt"
+	"github.com/AdilBaidual/baseProject/internal/auth"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/AdilBaidual/baseProject/pkg/pagination"
+)
+
+// Timeline лента опубликованных постов авторов, на которых подписан
+// читатель, от новых к старым. Глубину ленты задаёт followService.
+func (s *Service) Timeline(ctx context.Context, viewer auth.Identity, pageSize int32, pageToken string) ([]model.Post, string, error) {
+	var c postCursor
+	if err := pagination.DecodeToken(pageToken, &c); err != nil {
+		return nil, "", fmt.Errorf("%w: %s", model.ErrInvalidArgument, err.Error())
+	}
+
+	limit := pagination.PageSize(pageSize)
+
+	posts, err := s.postStore.ListTimeline(ctx, viewer.UserUUID, s.followService.TimelineSince(), c.PublishedAt, c.ID, limit+1)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var nextToken string
+	if len(posts) > limit {
+		posts = posts[:limit]
+		last := posts[limit-1]
+
+		if nextToken, err = pagination.EncodeToken(postCursor{PublishedAt: last.PublishedAt, ID: last.ID}); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if err = s.enrichPosts(ctx, viewer.UserUUID, posts); err != nil {
+		return nil, "", err
+	}
+
+	return posts, nextToken, nil
+}
@@ -0,0 +1,160 @@
+package post_service
+
+import (
+	"context"
+	"fmt"
+	"github.com/AdilBaidual/baseProject/internal/auth"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/AdilBaidual/baseProject/pkg/pagination"
+)
+
+// DeletePost переносит пост в корзину. Удалить чужой пост может пользователь
+// с правом posts.delete_any.
+func (s *Service) DeletePost(ctx context.Context, editor auth.Identity, id int64) error {
+	post, err := s.visiblePost(ctx, editor, id)
+	if err != nil {
+		return err
+	}
+
+	if post.AuthorUUID != editor.UserUUID && !editor.HasPermission(model.PermissionPostsDeleteAny) {
+		return fmt.Errorf("%w: only the author can delete this post", model.ErrPermissionDenied)
+	}
+
+	return s.postStore.DeletePost(ctx, id, editor.UserUUID)
+}
+
+// RestorePost возвращает пост из корзины, пока не истёк срок хранения. Автор
+// может восстановить только то, что удалил сам: удалённое модератором
+// возвращает пользователь с правом posts.delete_any.
+func (s *Service) RestorePost(ctx context.Context, editor auth.Identity, id int64) (model.Post, error) {
+	deleted, err := s.postStore.GetDeletedPost(ctx, id)
+	if err != nil {
+		return model.Post{}, err
+	}
+
+	if !canRestore(editor, deleted.AuthorUUID == editor.UserUUID && deleted.DeletedBy == editor.UserUUID, model.PermissionPostsDeleteAny) {
+		return model.Post{}, model.ErrNotFound
+	}
+
+	post, err := s.postStore.RestorePost(ctx, id, s.trashService.Cutoff())
+	if err != nil {
+		return model.Post{}, err
+	}
+
+	posts := []model.Post{post}
+	if err = s.enrichPosts(ctx, editor.UserUUID, posts); err != nil {
+		return model.Post{}, err
+	}
+
+	return posts[0], nil
+}
+
+// ListDeletedPosts корзина постов пользователя, от недавно удалённых к давним.
+func (s *Service) ListDeletedPosts(ctx context.Context, editor auth.Identity, pageSize int32, pageToken string) ([]model.Post, string, error) {
+	var c cursor
+	if err := pagination.DecodeToken(pageToken, &c); err != nil {
+		return nil, "", fmt.Errorf("%w: %s", model.ErrInvalidArgument, err.Error())
+	}
+
+	limit := pagination.PageSize(pageSize)
+
+	posts, err := s.postStore.ListDeletedPosts(ctx, editor.UserUUID, s.trashService.Cutoff(), c.ID, limit+1)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var nextToken string
+	if len(posts) > limit {
+		posts = posts[:limit]
+		if nextToken, err = pagination.EncodeToken(cursor{ID: posts[limit-1].ID}); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if err = s.freshPosts(ctx, posts); err != nil {
+		return nil, "", err
+	}
+
+	return posts, nextToken, nil
+}
+
+// DeleteComment переносит комментарий в корзину. Удалить чужой комментарий может
+// пользователь с правом comments.delete_any.
+func (s *Service) DeleteComment(ctx context.Context, editor auth.Identity, id int64) error {
+	comment, err := s.postStore.GetComment(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if _, err = s.visiblePost(ctx, editor, comment.PostID); err != nil {
+		return err
+	}
+
+	if comment.AuthorUUID != editor.UserUUID && !editor.HasPermission(model.PermissionCommentsDeleteAny) {
+		return fmt.Errorf("%w: only the author can delete this comment", model.ErrPermissionDenied)
+	}
+
+	return s.postStore.DeleteComment(ctx, id, editor.UserUUID)
+}
+
+// RestoreComment возвращает комментарий из корзины по тем же правилам, что и RestorePost.
+func (s *Service) RestoreComment(ctx context.Context, editor auth.Identity, id int64) (model.Comment, error) {
+	deleted, err := s.postStore.GetDeletedComment(ctx, id)
+	if err != nil {
+		return model.Comment{}, err
+	}
+
+	if !canRestore(editor, deleted.AuthorUUID == editor.UserUUID && deleted.DeletedBy == editor.UserUUID, model.PermissionCommentsDeleteAny) {
+		return model.Comment{}, model.ErrNotFound
+	}
+
+	if _, err = s.visiblePost(ctx, editor, deleted.PostID); err != nil {
+		return model.Comment{}, err
+	}
+
+	comment, err := s.postStore.RestoreComment(ctx, id, s.trashService.Cutoff())
+	if err != nil {
+		return model.Comment{}, err
+	}
+
+	comments := []model.Comment{comment}
+	if err = s.freshComments(ctx, comments); err != nil {
+		return model.Comment{}, err
+	}
+
+	return comments[0], nil
+}
+
+// ListDeletedComments корзина комментариев пользователя.
+func (s *Service) ListDeletedComments(ctx context.Context, editor auth.Identity, pageSize int32, pageToken string) ([]model.Comment, string, error) {
+	var c cursor
+	if err := pagination.DecodeToken(pageToken, &c); err != nil {
+		return nil, "", fmt.Errorf("%w: %s", model.ErrInvalidArgument, err.Error())
+	}
+
+	limit := pagination.PageSize(pageSize)
+
+	comments, err := s.postStore.ListDeletedComments(ctx, editor.UserUUID, s.trashService.Cutoff(), c.ID, limit+1)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var nextToken string
+	if len(comments) > limit {
+		comments = comments[:limit]
+		if nextToken, err = pagination.EncodeToken(cursor{ID: comments[limit-1].ID}); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if err = s.freshComments(ctx, comments); err != nil {
+		return nil, "", err
+	}
+
+	return comments, nextToken, nil
+}
+
+// canRestore чужая корзина неотличима от пустой, поэтому отказ — это ErrNotFound у вызывающего.
+func canRestore(editor auth.Identity, ownDeletion bool, permission string) bool {
+	return ownDeletion || editor.HasPermission(permission)
+}
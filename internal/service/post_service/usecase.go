@@ -0,0 +1,530 @@
+package post_service
+
+import (
+	"context"
+	"fmt"
+	"github.com/AdilBaidual/baseProject/internal/auth"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/AdilBaidual/baseProject/pkg/pagination"
+	"github.com/AdilBaidual/baseProject/pkg/slug"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	maxTitleLength   = 255
+	maxContentLength = 100_000
+	maxCommentLength = 10_000
+	maxTagLength     = 50
+	maxTagsPerPost   = 10
+)
+
+type postStore interface {
+	CreatePost(ctx context.Context, post model.Post, held *model.PolicyDecision) (model.Post, error)
+	UpdatePost(ctx context.Context, post model.Post, editorUUID uuid.UUID, restoredFrom int32) (model.Post, error)
+	GetPost(ctx context.Context, id int64) (model.Post, error)
+	ListPosts(ctx context.Context, filter model.PostFilter, before *time.Time, beforeID int64, limit int) ([]model.Post, error)
+	TransitionPost(ctx context.Context, id int64, from []string, to model.Post) (model.Post, error)
+	ApplyPostSchedule(ctx context.Context, batch int) (published, archived int, locked bool, err error)
+	GetPostTags(ctx context.Context, postIDs []int64) (map[int64][]model.Tag, error)
+	SearchTags(ctx context.Context, prefix string, limit int) ([]model.Tag, error)
+	ListPopularTags(ctx context.Context, limit int) ([]model.Tag, error)
+	CreateComment(ctx context.Context, comment model.Comment, held *model.PolicyDecision) (model.Comment, error)
+	ListComments(ctx context.Context, postID, parentID, afterID int64, limit int, viewer uuid.UUID, includeHidden bool) ([]model.Comment, error)
+	GetRevision(ctx context.Context, postID int64, version int32) (model.PostRevision, error)
+	ListRevisions(ctx context.Context, postID int64, beforeVersion int32, limit int) ([]model.PostRevision, error)
+	GetComment(ctx context.Context, id int64) (model.Comment, error)
+	DeletePost(ctx context.Context, id int64, deletedBy uuid.UUID) error
+	RestorePost(ctx context.Context, id int64, cutoff time.Time) (model.Post, error)
+	GetDeletedPost(ctx context.Context, id int64) (model.Post, error)
+	ListDeletedPosts(ctx context.Context, authorUUID uuid.UUID, cutoff time.Time, beforeID int64, limit int) ([]model.Post, error)
+	DeleteComment(ctx context.Context, id int64, deletedBy uuid.UUID) error
+	RestoreComment(ctx context.Context, id int64, cutoff time.Time) (model.Comment, error)
+	GetDeletedComment(ctx context.Context, id int64) (model.Comment, error)
+	ListDeletedComments(ctx context.Context, authorUUID uuid.UUID, cutoff time.Time, beforeID int64, limit int) ([]model.Comment, error)
+	SavePostContentHTML(ctx context.Context, post model.Post) error
+	SaveCommentContentHTML(ctx context.Context, comment model.Comment) error
+	ListTimeline(ctx context.Context, viewer uuid.UUID, since time.Time, before *time.Time, beforeID int64, limit int) ([]model.Post, error)
+	ResolveUsernames(ctx context.Context, handles []string) ([]model.Mention, error)
+	GetPostMentions(ctx context.Context, postIDs []int64) (map[int64][]model.Mention, error)
+	GetCommentMentions(ctx context.Context, commentIDs []int64) (map[int64][]model.Mention, error)
+	ClaimPostMentions(ctx context.Context, postID int64, limit int) ([]model.Notification, error)
+	GetBookmarkTimes(ctx context.Context, userUUID uuid.UUID, postIDs []int64) (map[int64]time.Time, error)
+	GetBookmarkCollection(ctx context.Context, userUUID uuid.UUID, id int64) (model.BookmarkCollection, error)
+	ListBookmarkedPosts(ctx context.Context, userUUID uuid.UUID, collectionID int64, before *time.Time, beforeID int64, limit int) ([]model.Post, error)
+}
+
+type reactionService interface {
+	Summaries(ctx context.Context, viewer uuid.UUID, targetType string, ids []int64) (map[int64]model.ReactionSummary, error)
+}
+
+type trashService interface {
+	Cutoff() time.Time
+}
+
+type followService interface {
+	TimelineSince() time.Time
+}
+
+type notificationService interface {
+	NotifyComment(ctx context.Context, post model.Post, comment model.Comment) error
+	NotifyMentions(ctx context.Context, actor uuid.UUID, users []uuid.UUID, postID, commentID int64) error
+}
+
+type Config struct {
+	// SchedulerInterval период проверки запланированных публикаций и снятий с публикации.
+	SchedulerInterval time.Duration `yaml:"scheduler_interval"`
+	// SchedulerBatch число постов, обрабатываемых за одну транзакцию.
+	SchedulerBatch int `yaml:"scheduler_batch"`
+}
+
+func (c Config) withDefaults() Config {
+	if c.SchedulerInterval <= 0 {
+		c.SchedulerInterval = 30 * time.Second
+	}
+	if c.SchedulerBatch <= 0 {
+		c.SchedulerBatch = 100
+	}
+	return c
+}
+
+type cursor struct {
+	ID int64 `json:"id"`
+}
+
+// postCursor позиция в ленте опубликованных постов, которая упорядочена по времени публикации.
+type postCursor struct {
+	PublishedAt *time.Time `json:"t,omitempty"`
+	ID          int64      `json:"id"`
+}
+
+type Service struct {
+	logger *zap.Logger
+	cfg    Config
+
+	postStore       postStore
+	reactionService reactionService
+	trashService    trashService
+	policyService   policyService
+	followService   followService
+
+	notificationService notificationService
+}
+
+func NewService(logger *zap.Logger, cfg Config, postStore postStore, reactionService reactionService, trashService trashService, policyService policyService, followService followService, notificationService notificationService) *Service {
+	return &Service{
+		logger:          logger,
+		cfg:             cfg.withDefaults(),
+		postStore:       postStore,
+		reactionService: reactionService,
+		trashService:    trashService,
+		policyService:   policyService,
+		followService:   followService,
+
+		notificationService: notificationService,
+	}
+}
+
+// CreatePost создаёт пост. Без Draft и PublishAt пост публикуется сразу.
+// Пост, задержанный контент-политикой, создаётся скрытым до решения модератора.
+func (s *Service) CreatePost(ctx context.Context, author auth.Identity, title, content string, commentsEnabled bool, tags []string, publication Publication) (model.Post, error) {
+	post, err := newPost(title, content, commentsEnabled, tags)
+	if err != nil {
+		return model.Post{}, err
+	}
+
+	if post.Status, err = publication.status(time.Now()); err != nil {
+		return model.Post{}, err
+	}
+
+	post.AuthorUUID = author.UserUUID
+	post.UnpublishAt = publication.UnpublishAt
+	if post.Status == model.PostStatusScheduled {
+		post.PublishAt = publication.PublishAt
+	}
+
+	held, err := s.checkPolicy(ctx, author, model.PolicyContent{Kind: model.ReactionTargetPost, Title: post.Title, Text: post.Content})
+	if err != nil {
+		return model.Post{}, err
+	}
+
+	if post.Mentions, err = s.resolveMentions(ctx, post.Content); err != nil {
+		return model.Post{}, err
+	}
+
+	if err = renderPost(&post); err != nil {
+		return model.Post{}, err
+	}
+
+	created, err := s.postStore.CreatePost(ctx, post, held)
+	if err != nil {
+		return model.Post{}, err
+	}
+
+	s.notifyPostMentions(ctx, created.ID)
+
+	return created, nil
+}
+
+// UpdatePost заменяет заголовок, текст и теги и сохраняет ревизию. version —
+// версия поста, которую видел редактор: если пост с тех пор изменили, правка
+// отклоняется с model.ErrConflict. Чужой пост может изменить только пользователь
+// с правом posts.update_any.
+func (s *Service) UpdatePost(ctx context.Context, editor auth.Identity, id int64, version int32, title, content string, commentsEnabled bool, tags []string) (model.Post, error) {
+	if version <= 0 {
+		return model.Post{}, fmt.Errorf("%w: version is required", model.ErrInvalidArgument)
+	}
+
+	if _, err := s.editablePost(ctx, editor, id); err != nil {
+		return model.Post{}, err
+	}
+
+	post, err := newPost(title, content, commentsEnabled, tags)
+	if err != nil {
+		return model.Post{}, err
+	}
+
+	post.ID = id
+	post.Version = version
+
+	if post.Mentions, err = s.resolveMentions(ctx, post.Content); err != nil {
+		return model.Post{}, err
+	}
+
+	if err = renderPost(&post); err != nil {
+		return model.Post{}, err
+	}
+
+	updated, err := s.postStore.UpdatePost(ctx, post, editor.UserUUID, 0)
+	if err != nil {
+		return model.Post{}, err
+	}
+
+	// Уведомляются только упомянутые впервые: прежние упоминания уже отмечены.
+	s.notifyPostMentions(ctx, updated.ID)
+
+	return updated, nil
+}
+
+// GetPost возвращает пост вместе с тегами и реакциями. У анонимного читателя пустой viewer.
+func (s *Service) GetPost(ctx context.Context, viewer auth.Identity, id int64) (model.Post, error) {
+	post, err := s.visiblePost(ctx, viewer, id)
+	if err != nil {
+		return model.Post{}, err
+	}
+
+	posts := []model.Post{post}
+	if err = s.enrichPosts(ctx, viewer.UserUUID, posts); err != nil {
+		return model.Post{}, err
+	}
+
+	return posts[0], nil
+}
+
+// ListPosts возвращает посты от новых к старым. Фильтры по тегу, автору и дате
+// комбинируются между собой. Неопубликованные посты можно запросить только свои,
+// скрытые модерацией видят только модераторы.
+func (s *Service) ListPosts(ctx context.Context, viewer auth.Identity, filter model.PostFilter, pageSize int32, pageToken string) ([]model.Post, string, error) {
+	if filter.TagSlug != "" {
+		filter.TagSlug = slug.Make(filter.TagSlug)
+	}
+	filter.IncludeHidden = isModerator(viewer)
+	filter.Viewer = viewer.UserUUID
+
+	switch {
+	case filter.Status == "" || filter.Status == model.PostStatusPublished:
+	case !slices.Contains(model.PostStatuses, filter.Status):
+		return nil, "", fmt.Errorf("%w: unknown post status %q", model.ErrInvalidArgument, filter.Status)
+	case viewer.UserUUID == uuid.Nil:
+		return nil, "", fmt.Errorf("%w: sign in to list unpublished posts", model.ErrUnauthenticated)
+	case filter.AuthorUUID != uuid.Nil && filter.AuthorUUID != viewer.UserUUID:
+		return nil, "", fmt.Errorf("%w: unpublished posts are visible only to their author", model.ErrPermissionDenied)
+	default:
+		filter.AuthorUUID = viewer.UserUUID
+	}
+
+	var c postCursor
+	if err := pagination.DecodeToken(pageToken, &c); err != nil {
+		return nil, "", fmt.Errorf("%w: %s", model.ErrInvalidArgument, err.Error())
+	}
+
+	limit := pagination.PageSize(pageSize)
+
+	posts, err := s.postStore.ListPosts(ctx, filter, c.PublishedAt, c.ID, limit+1)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var nextToken string
+	if len(posts) > limit {
+		posts = posts[:limit]
+		last := posts[limit-1]
+
+		next := postCursor{ID: last.ID}
+		if last.Status == model.PostStatusPublished {
+			next.PublishedAt = last.PublishedAt
+		}
+
+		if nextToken, err = pagination.EncodeToken(next); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if err = s.enrichPosts(ctx, viewer.UserUUID, posts); err != nil {
+		return nil, "", err
+	}
+
+	return posts, nextToken, nil
+}
+
+// SearchTags автодополнение тегов по началу slug.
+func (s *Service) SearchTags(ctx context.Context, prefix string, limit int32) ([]model.Tag, error) {
+	prefix = slug.Make(prefix)
+	if prefix == "" {
+		return nil, fmt.Errorf("%w: prefix is required", model.ErrInvalidArgument)
+	}
+
+	return s.postStore.SearchTags(ctx, prefix, pagination.PageSize(limit))
+}
+
+func (s *Service) ListPopularTags(ctx context.Context, limit int32) ([]model.Tag, error) {
+	return s.postStore.ListPopularTags(ctx, pagination.PageSize(limit))
+}
+
+func (s *Service) CreateComment(ctx context.Context, author auth.Identity, postID, parentID int64, content string) (model.Comment, error) {
+	if strings.TrimSpace(content) == "" || utf8.RuneCountInString(content) > maxCommentLength {
+		return model.Comment{}, fmt.Errorf("%w: content must be 1..%d characters", model.ErrInvalidArgument, maxCommentLength)
+	}
+
+	post, err := s.visiblePost(ctx, author, postID)
+	if err != nil {
+		return model.Comment{}, err
+	}
+
+	if post.Status != model.PostStatusPublished {
+		return model.Comment{}, fmt.Errorf("%w: post is not published", model.ErrInvalidArgument)
+	}
+
+	if !post.CommentsEnabled {
+		return model.Comment{}, fmt.Errorf("%w: comments are disabled for this post", model.ErrPermissionDenied)
+	}
+
+	held, err := s.checkPolicy(ctx, author, model.PolicyContent{Kind: model.ReactionTargetComment, Text: content})
+	if err != nil {
+		return model.Comment{}, err
+	}
+
+	comment := model.Comment{
+		PostID:     postID,
+		ParentID:   parentID,
+		AuthorUUID: author.UserUUID,
+		Content:    content,
+	}
+	if comment.Mentions, err = s.resolveMentions(ctx, content); err != nil {
+		return model.Comment{}, err
+	}
+	if err = renderComment(&comment); err != nil {
+		return model.Comment{}, err
+	}
+
+	created, err := s.postStore.CreateComment(ctx, comment, held)
+	if err != nil {
+		return model.Comment{}, err
+	}
+
+	// Комментарий уже сохранён: сбой уведомлений не отменяет его создание.
+	if err = s.notificationService.NotifyComment(ctx, post, created); err != nil {
+		s.logger.Error("failed to create comment notifications", zap.Error(err), zap.Int64("comment_id", created.ID))
+	}
+	s.notifyCommentMentions(ctx, created)
+
+	return created, nil
+}
+
+// ListComments возвращает ответы на parentID (0 — комментарии верхнего уровня).
+func (s *Service) ListComments(ctx context.Context, viewer auth.Identity, postID, parentID int64, pageSize int32, pageToken string) ([]model.Comment, string, error) {
+	if _, err := s.visiblePost(ctx, viewer, postID); err != nil {
+		return nil, "", err
+	}
+
+	var c cursor
+	if err := pagination.DecodeToken(pageToken, &c); err != nil {
+		return nil, "", fmt.Errorf("%w: %s", model.ErrInvalidArgument, err.Error())
+	}
+
+	limit := pagination.PageSize(pageSize)
+
+	moderator := isModerator(viewer)
+
+	comments, err := s.postStore.ListComments(ctx, postID, parentID, c.ID, limit+1, viewer.UserUUID, moderator)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var nextToken string
+	if len(comments) > limit {
+		comments = comments[:limit]
+		if nextToken, err = pagination.EncodeToken(cursor{ID: comments[limit-1].ID}); err != nil {
+			return nil, "", err
+		}
+	}
+
+	ids := make([]int64, 0, len(comments))
+	for _, comment := range comments {
+		ids = append(ids, comment.ID)
+	}
+
+	summaries, err := s.reactionService.Summaries(ctx, viewer.UserUUID, model.ReactionTargetComment, ids)
+	if err != nil {
+		return nil, "", err
+	}
+
+	for i := range comments {
+		// Удалённый или скрытый комментарий остаётся в ветке заглушкой ради ответов на него.
+		switch {
+		case comments[i].DeletedAt != nil:
+			placeholder(&comments[i], model.DeletedCommentPlaceholder)
+			continue
+		case !comments[i].VisibleTo(viewer.UserUUID, moderator):
+			placeholder(&comments[i], model.HiddenCommentPlaceholder)
+			continue
+		}
+		comments[i].Reactions = summaries[comments[i].ID]
+	}
+
+	if err = s.freshComments(ctx, comments); err != nil {
+		return nil, "", err
+	}
+
+	return comments, nextToken, nil
+}
+
+// visiblePost возвращает пост, если читатель может его видеть. Чужие
+// неопубликованные и скрытые модерацией посты неотличимы от несуществующих.
+func (s *Service) visiblePost(ctx context.Context, viewer auth.Identity, id int64) (model.Post, error) {
+	post, err := s.postStore.GetPost(ctx, id)
+	if err != nil {
+		return model.Post{}, err
+	}
+
+	if !post.VisibleTo(viewer.UserUUID, isModerator(viewer)) {
+		return model.Post{}, model.ErrNotFound
+	}
+
+	return post, nil
+}
+
+// editablePost возвращает пост, который editor может изменять: свой или, с правом
+// posts.update_any, чужой опубликованный.
+func (s *Service) editablePost(ctx context.Context, editor auth.Identity, id int64) (model.Post, error) {
+	post, err := s.visiblePost(ctx, editor, id)
+	if err != nil {
+		return model.Post{}, err
+	}
+
+	if post.AuthorUUID != editor.UserUUID && !editor.HasPermission(model.PermissionPostsUpdateAny) {
+		return model.Post{}, fmt.Errorf("%w: only the author can edit this post", model.ErrPermissionDenied)
+	}
+
+	return post, nil
+}
+
+func isModerator(identity auth.Identity) bool {
+	return identity.HasPermission(model.PermissionModerationManage)
+}
+
+func (s *Service) enrichPosts(ctx context.Context, viewer uuid.UUID, posts []model.Post) error {
+	ids := make([]int64, 0, len(posts))
+	for _, post := range posts {
+		ids = append(ids, post.ID)
+	}
+
+	tags, err := s.postStore.GetPostTags(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	summaries, err := s.reactionService.Summaries(ctx, viewer, model.ReactionTargetPost, ids)
+	if err != nil {
+		return err
+	}
+
+	// Закладки есть только у вошедшего читателя.
+	var bookmarks map[int64]time.Time
+	if viewer != uuid.Nil {
+		if bookmarks, err = s.postStore.GetBookmarkTimes(ctx, viewer, ids); err != nil {
+			return err
+		}
+	}
+
+	for i := range posts {
+		posts[i].Tags = tags[posts[i].ID]
+		posts[i].Reactions = summaries[posts[i].ID]
+
+		if t, ok := bookmarks[posts[i].ID]; ok {
+			posts[i].BookmarkedAt = &t
+		}
+	}
+
+	return s.freshPosts(ctx, posts)
+}
+
+func newPost(title, content string, commentsEnabled bool, rawTags []string) (model.Post, error) {
+	title = strings.TrimSpace(title)
+	if title == "" || utf8.RuneCountInString(title) > maxTitleLength {
+		return model.Post{}, fmt.Errorf("%w: title must be 1..%d characters", model.ErrInvalidArgument, maxTitleLength)
+	}
+
+	if strings.TrimSpace(content) == "" || utf8.RuneCountInString(content) > maxContentLength {
+		return model.Post{}, fmt.Errorf("%w: content must be 1..%d characters", model.ErrInvalidArgument, maxContentLength)
+	}
+
+	tags, err := normalizeTags(rawTags)
+	if err != nil {
+		return model.Post{}, err
+	}
+
+	return model.Post{
+		Title:           title,
+		Content:         content,
+		CommentsEnabled: commentsEnabled,
+		Tags:            tags,
+	}, nil
+}
+
+// normalizeTags приводит теги к slug и убирает повторы. Имя тега сохраняется
+// в том виде, в котором его впервые указали, slug служит ключом.
+func normalizeTags(raw []string) ([]model.Tag, error) {
+	tags := make([]model.Tag, 0, len(raw))
+	seen := make(map[string]struct{}, len(raw))
+
+	for _, name := range raw {
+		name = strings.Join(strings.Fields(strings.TrimLeft(strings.TrimSpace(name), "#")), " ")
+
+		tagSlug := slug.Make(name)
+		if tagSlug == "" {
+			continue
+		}
+
+		if utf8.RuneCountInString(name) > maxTagLength || utf8.RuneCountInString(tagSlug) > maxTagLength {
+			return nil, fmt.Errorf("%w: tag %q is longer than %d characters", model.ErrInvalidArgument, name, maxTagLength)
+		}
+
+		if _, ok := seen[tagSlug]; ok {
+			continue
+		}
+		seen[tagSlug] = struct{}{}
+
+		tags = append(tags, model.Tag{Slug: tagSlug, Name: name})
+	}
+
+	if len(tags) > maxTagsPerPost {
+		return nil, fmt.Errorf("%w: at most %d tags per post", model.ErrInvalidArgument, maxTagsPerPost)
+	}
+
+	return tags, nil
+}
@@ -0,0 +1,84 @@
+package post_service
+
+import (
+	"errors"
+	"fmt"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	tooMany := make([]string, maxTagsPerPost+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("tag%d", i)
+	}
+
+	tests := []struct {
+		name    string
+		raw     []string
+		want    []model.Tag
+		wantErr error
+	}{
+		{name: "empty", raw: nil, want: []model.Tag{}},
+		{
+			name: "slug and display name",
+			raw:  []string{"Go & Postgres"},
+			want: []model.Tag{{Slug: "go-postgres", Name: "Go & Postgres"}},
+		},
+		{
+			name: "hash prefix and spaces",
+			raw:  []string{"  #Базы   Данных  "},
+			want: []model.Tag{{Slug: "базы-данных", Name: "Базы Данных"}},
+		},
+		{
+			name: "duplicates keep first name",
+			raw:  []string{"Golang", "golang", "#GOLANG", "go-lang"},
+			want: []model.Tag{{Slug: "golang", Name: "Golang"}, {Slug: "go-lang", Name: "go-lang"}},
+		},
+		{
+			name: "tags without letters are skipped",
+			raw:  []string{"", "   ", "#", "!!!", "rust"},
+			want: []model.Tag{{Slug: "rust", Name: "rust"}},
+		},
+		{
+			name: "longest allowed",
+			raw:  []string{strings.Repeat("я", maxTagLength)},
+			want: []model.Tag{{Slug: strings.Repeat("я", maxTagLength), Name: strings.Repeat("я", maxTagLength)}},
+		},
+		{name: "too long", raw: []string{strings.Repeat("a", maxTagLength+1)}, wantErr: model.ErrInvalidArgument},
+		{name: "too many", raw: tooMany, wantErr: model.ErrInvalidArgument},
+		{
+			name: "duplicates do not count against the limit",
+			raw:  append(repeat("go", maxTagsPerPost+5), "sql"),
+			want: []model.Tag{{Slug: "go", Name: "go"}, {Slug: "sql", Name: "sql"}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := normalizeTags(tc.raw)
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Fatalf("normalizeTags error = %v, want %v", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("normalizeTags: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("normalizeTags = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func repeat(s string, n int) []string {
+	res := make([]string, n)
+	for i := range res {
+		res[i] = s
+	}
+	return res
+}
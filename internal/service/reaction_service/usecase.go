@@ -0,0 +1,188 @@
+package reaction_service
+
+import (
+	"context"
+	"fmt"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/AdilBaidual/baseProject/pkg/pagination"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"slices"
+	"time"
+)
+
+type reactionStore interface {
+	AddReaction(ctx context.Context, userUUID uuid.UUID, target model.ReactionTarget, kind string) (bool, error)
+	RemoveReaction(ctx context.Context, userUUID uuid.UUID, target model.ReactionTarget, kind string) (bool, error)
+	GetReactionCounts(ctx context.Context, targetType string, ids []int64) (map[int64]map[string]int64, error)
+	GetUserReactions(ctx context.Context, userUUID uuid.UUID, targetType string, ids []int64) (map[int64][]string, error)
+	ListReactions(ctx context.Context, target model.ReactionTarget, kind string, before *time.Time, beforeUser uuid.UUID, limit int) ([]model.Reaction, error)
+	GetPost(ctx context.Context, id int64) (model.Post, error)
+	GetComment(ctx context.Context, id int64) (model.Comment, error)
+}
+
+type cursor struct {
+	CreatedAt time.Time `json:"t"`
+	UserUUID  uuid.UUID `json:"u"`
+}
+
+type Service struct {
+	logger *zap.Logger
+
+	reactionStore reactionStore
+}
+
+func NewService(logger *zap.Logger, reactionStore reactionStore) *Service {
+	return &Service{
+		logger:        logger,
+		reactionStore: reactionStore,
+	}
+}
+
+// Add ставит реакцию. Повторный вызов ничего не меняет.
+func (s *Service) Add(ctx context.Context, userUUID uuid.UUID, target model.ReactionTarget, kind string) (model.ReactionSummary, error) {
+	if err := s.validate(ctx, userUUID, target, kind); err != nil {
+		return model.ReactionSummary{}, err
+	}
+
+	if _, err := s.reactionStore.AddReaction(ctx, userUUID, target, kind); err != nil {
+		return model.ReactionSummary{}, err
+	}
+
+	return s.summary(ctx, userUUID, target)
+}
+
+// Remove снимает реакцию. Снятие отсутствующей реакции не считается ошибкой.
+func (s *Service) Remove(ctx context.Context, userUUID uuid.UUID, target model.ReactionTarget, kind string) (model.ReactionSummary, error) {
+	if err := s.validate(ctx, userUUID, target, kind); err != nil {
+		return model.ReactionSummary{}, err
+	}
+
+	if _, err := s.reactionStore.RemoveReaction(ctx, userUUID, target, kind); err != nil {
+		return model.ReactionSummary{}, err
+	}
+
+	return s.summary(ctx, userUUID, target)
+}
+
+// List возвращает, кто и как отреагировал. kind = "" — реакции всех видов.
+func (s *Service) List(ctx context.Context, viewer uuid.UUID, target model.ReactionTarget, kind string, pageSize int32, pageToken string) ([]model.Reaction, model.ReactionSummary, string, error) {
+	if kind != "" && !slices.Contains(model.ReactionKinds, kind) {
+		return nil, model.ReactionSummary{}, "", fmt.Errorf("%w: unknown reaction %q", model.ErrInvalidArgument, kind)
+	}
+
+	if err := s.checkTarget(ctx, viewer, target); err != nil {
+		return nil, model.ReactionSummary{}, "", err
+	}
+
+	var c cursor
+	if err := pagination.DecodeToken(pageToken, &c); err != nil {
+		return nil, model.ReactionSummary{}, "", fmt.Errorf("%w: %s", model.ErrInvalidArgument, err.Error())
+	}
+
+	var before *time.Time
+	if !c.CreatedAt.IsZero() {
+		before = &c.CreatedAt
+	}
+
+	limit := pagination.PageSize(pageSize)
+
+	reactions, err := s.reactionStore.ListReactions(ctx, target, kind, before, c.UserUUID, limit+1)
+	if err != nil {
+		return nil, model.ReactionSummary{}, "", err
+	}
+
+	var nextToken string
+	if len(reactions) > limit {
+		reactions = reactions[:limit]
+		last := reactions[limit-1]
+
+		if nextToken, err = pagination.EncodeToken(cursor{CreatedAt: last.CreatedAt, UserUUID: last.UserUUID}); err != nil {
+			return nil, model.ReactionSummary{}, "", err
+		}
+	}
+
+	summary, err := s.summary(ctx, viewer, target)
+	if err != nil {
+		return nil, model.ReactionSummary{}, "", err
+	}
+
+	return reactions, summary, nextToken, nil
+}
+
+// Summaries возвращает агрегированные реакции для набора объектов одного типа.
+// Используется для встраивания счётчиков в ответы с постами и комментариями.
+func (s *Service) Summaries(ctx context.Context, viewer uuid.UUID, targetType string, ids []int64) (map[int64]model.ReactionSummary, error) {
+	summaries := make(map[int64]model.ReactionSummary, len(ids))
+	if len(ids) == 0 {
+		return summaries, nil
+	}
+
+	counts, err := s.reactionStore.GetReactionCounts(ctx, targetType, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	var mine map[int64][]string
+	if viewer != uuid.Nil {
+		if mine, err = s.reactionStore.GetUserReactions(ctx, viewer, targetType, ids); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, id := range ids {
+		summaries[id] = model.ReactionSummary{Counts: counts[id], Mine: mine[id]}
+	}
+
+	return summaries, nil
+}
+
+func (s *Service) summary(ctx context.Context, viewer uuid.UUID, target model.ReactionTarget) (model.ReactionSummary, error) {
+	summaries, err := s.Summaries(ctx, viewer, target.Type, []int64{target.ID})
+	if err != nil {
+		return model.ReactionSummary{}, err
+	}
+
+	return summaries[target.ID], nil
+}
+
+func (s *Service) validate(ctx context.Context, viewer uuid.UUID, target model.ReactionTarget, kind string) error {
+	if !slices.Contains(model.ReactionKinds, kind) {
+		return fmt.Errorf("%w: unknown reaction %q", model.ErrInvalidArgument, kind)
+	}
+
+	return s.checkTarget(ctx, viewer, target)
+}
+
+// checkTarget проверяет, что объект существует и виден пользователю:
+// реакции к чужим неопубликованным постам, их комментариям и к контенту,
+// скрытому модерацией, недоступны.
+func (s *Service) checkTarget(ctx context.Context, viewer uuid.UUID, target model.ReactionTarget) error {
+	postID := target.ID
+
+	switch target.Type {
+	case model.ReactionTargetPost:
+	case model.ReactionTargetComment:
+		comment, err := s.reactionStore.GetComment(ctx, target.ID)
+		if err != nil {
+			return err
+		}
+		if !comment.VisibleTo(viewer, false) {
+			return model.ErrNotFound
+		}
+		postID = comment.PostID
+	default:
+		return fmt.Errorf("%w: unknown reaction target %q", model.ErrInvalidArgument, target.Type)
+	}
+
+	post, err := s.reactionStore.GetPost(ctx, postID)
+	if err != nil {
+		return err
+	}
+
+	if !post.VisibleTo(viewer, false) {
+		return model.ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,159 @@
+package sso_service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/AdilBaidual/baseProject/pkg/sso"
+	"go.uber.org/zap"
+	"golang.org/x/oauth2"
+	"time"
+)
+
+const stateBytes = 32
+
+type ssoStore interface {
+	CreateSSOState(ctx context.Context, stateHash string, state model.SSOState) error
+	ConsumeSSOState(ctx context.Context, stateHash, provider string) (model.SSOState, error)
+	DeleteExpiredSSOStates(ctx context.Context) error
+}
+
+type userService interface {
+	LoginExternal(ctx context.Context, identity model.ExternalIdentity, ip string) (model.LoginResult, error)
+}
+
+type Service struct {
+	logger          *zap.Logger
+	stateTTL        time.Duration
+	cleanupInterval time.Duration
+
+	ssoStore    ssoStore
+	registry    *sso.Registry
+	userService userService
+}
+
+func NewService(logger *zap.Logger, cfg sso.Config, ssoStore ssoStore, registry *sso.Registry, userService userService) *Service {
+	stateTTL := cfg.StateTTL
+	if stateTTL <= 0 {
+		stateTTL = 10 * time.Minute
+	}
+
+	cleanupInterval := cfg.CleanupInterval
+	if cleanupInterval <= 0 {
+		cleanupInterval = 10 * time.Minute
+	}
+
+	return &Service{
+		logger:          logger,
+		stateTTL:        stateTTL,
+		cleanupInterval: cleanupInterval,
+		ssoStore:        ssoStore,
+		registry:        registry,
+		userService:     userService,
+	}
+}
+
+// CleanupInterval период запуска Cleanup.
+func (s *Service) CleanupInterval() time.Duration {
+	return s.cleanupInterval
+}
+
+// Cleanup удаляет state начатых, но не завершённых входов. Каждый анонимный
+// запрос login создаёт запись, так что без очистки таблица только растёт.
+func (s *Service) Cleanup(ctx context.Context) error {
+	return s.ssoStore.DeleteExpiredSSOStates(ctx)
+}
+
+func (s *Service) Providers() []string {
+	return s.registry.Names()
+}
+
+// Begin начинает authorization code flow: сохраняет state, nonce и PKCE
+// verifier и возвращает адрес страницы входа провайдера.
+func (s *Service) Begin(ctx context.Context, providerName string) (string, error) {
+	provider, err := s.registry.Get(providerName)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", model.ErrNotFound, err.Error())
+	}
+
+	state, err := randomString()
+	if err != nil {
+		return "", err
+	}
+
+	nonce, err := randomString()
+	if err != nil {
+		return "", err
+	}
+
+	verifier := oauth2.GenerateVerifier()
+
+	err = s.ssoStore.CreateSSOState(ctx, hashState(state), model.SSOState{
+		Provider:     providerName,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    time.Now().Add(s.stateTTL),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return provider.AuthCodeURL(ctx, state, nonce, verifier)
+}
+
+// Complete обменивает код на id_token и входит по полученной учётной записи.
+func (s *Service) Complete(ctx context.Context, providerName, state, code, ip string) (model.LoginResult, error) {
+	provider, err := s.registry.Get(providerName)
+	if err != nil {
+		return model.LoginResult{}, fmt.Errorf("%w: %s", model.ErrNotFound, err.Error())
+	}
+
+	if state == "" || code == "" {
+		return model.LoginResult{}, fmt.Errorf("%w: state and code are required", model.ErrInvalidArgument)
+	}
+
+	saved, err := s.ssoStore.ConsumeSSOState(ctx, hashState(state), providerName)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return model.LoginResult{}, fmt.Errorf("%w: unknown or reused state", model.ErrInvalidArgument)
+		}
+		return model.LoginResult{}, err
+	}
+
+	if time.Now().After(saved.ExpiresAt) {
+		return model.LoginResult{}, fmt.Errorf("%w: login attempt expired", model.ErrInvalidArgument)
+	}
+
+	identity, err := provider.Exchange(ctx, code, saved.CodeVerifier, saved.Nonce)
+	if err != nil {
+		s.logger.Warn("sso exchange failed", zap.Error(err), zap.String("provider", providerName))
+		return model.LoginResult{}, model.ErrInvalidCredentials
+	}
+
+	return s.userService.LoginExternal(ctx, model.ExternalIdentity{
+		Provider:      identity.Provider,
+		Subject:       identity.Subject,
+		Email:         identity.Email,
+		EmailVerified: identity.EmailVerified,
+		Name:          identity.Name,
+	}, ip)
+}
+
+func randomString() (string, error) {
+	b := make([]byte, stateBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("randomString - rand.Read - %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashState(state string) string {
+	sum := sha256.Sum256([]byte(state))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,404 @@
+package sso_service_test
+
+import (
+	"context"
+	"errors"
+	"github.com/AdilBaidual/baseProject/internal/auth"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/AdilBaidual/baseProject/internal/service/sso_service"
+	"github.com/AdilBaidual/baseProject/internal/service/user_service"
+	"github.com/AdilBaidual/baseProject/internal/store"
+	"github.com/AdilBaidual/baseProject/pkg/oidcmock"
+	"github.com/AdilBaidual/baseProject/pkg/sso"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	providerName = "mock"
+	clientID     = "base-project"
+	redirectURL  = "http://app.test/v1/sso/mock/callback"
+)
+
+// memoryStore хранит state входа, пользователей и их внешние учётные записи
+// в памяти. Остальные методы хранилища в сценарии входа не вызываются:
+// обращение к ним упадёт на nil *store.Store.
+type memoryStore struct {
+	*store.Store
+
+	mu         sync.Mutex
+	states     map[string]model.SSOState
+	users      map[uuid.UUID]model.User
+	identities map[string]uuid.UUID
+	linked     []model.ExternalIdentity
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		states:     make(map[string]model.SSOState),
+		users:      make(map[uuid.UUID]model.User),
+		identities: make(map[string]uuid.UUID),
+	}
+}
+
+func (m *memoryStore) CreateSSOState(_ context.Context, stateHash string, state model.SSOState) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.states[stateHash] = state
+	return nil
+}
+
+func (m *memoryStore) ConsumeSSOState(_ context.Context, stateHash, provider string) (model.SSOState, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	state, ok := m.states[stateHash]
+	if !ok || state.Provider != provider {
+		return model.SSOState{}, model.ErrNotFound
+	}
+	delete(m.states, stateHash)
+	return state, nil
+}
+
+func (m *memoryStore) DeleteExpiredSSOStates(context.Context) error {
+	return nil
+}
+
+// tamper меняет сохранённый state, как если бы его подменили между Begin и Complete.
+func (m *memoryStore) tamper(change func(*model.SSOState)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for hash, state := range m.states {
+		change(&state)
+		m.states[hash] = state
+	}
+}
+
+func (m *memoryStore) addUser(user model.User) model.User {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	user.UUID = uuid.New()
+	m.users[user.UUID] = user
+	return user
+}
+
+func (m *memoryStore) GetUserByIdentity(_ context.Context, provider, subject string) (model.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	userUUID, ok := m.identities[provider+"/"+subject]
+	if !ok {
+		return model.User{}, model.ErrNotFound
+	}
+	return m.users[userUUID], nil
+}
+
+func (m *memoryStore) GetUserByEmail(_ context.Context, email string) (model.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, user := range m.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return model.User{}, model.ErrNotFound
+}
+
+func (m *memoryStore) LinkIdentity(_ context.Context, userUUID uuid.UUID, identity model.ExternalIdentity) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.identities[identity.Provider+"/"+identity.Subject] = userUUID
+	m.linked = append(m.linked, identity)
+	return nil
+}
+
+func (m *memoryStore) CreateUserWithIdentity(_ context.Context, user model.User, identity model.ExternalIdentity) (uuid.UUID, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	user.UUID = uuid.New()
+	m.users[user.UUID] = user
+	m.identities[identity.Provider+"/"+identity.Subject] = user.UUID
+	return user.UUID, nil
+}
+
+func (m *memoryStore) MarkEmailVerified(_ context.Context, id uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	user := m.users[id]
+	now := time.Now()
+	user.EmailVerifiedAt = &now
+	m.users[id] = user
+	return nil
+}
+
+func (m *memoryStore) GetMFA(context.Context, uuid.UUID) (model.MFA, error) {
+	return model.MFA{}, model.ErrNotFound
+}
+
+func (m *memoryStore) ResetLoginFailures(context.Context, string, string) error {
+	return nil
+}
+
+func (m *memoryStore) CreateAuditEvent(context.Context, model.AuditEvent) error {
+	return nil
+}
+
+type env struct {
+	store   *memoryStore
+	service *sso_service.Service
+	tokens  *auth.TokenManager
+}
+
+// newEnv поднимает oidcmock и собирает сервис входа поверх настоящего user_service.
+func newEnv(t *testing.T) *env {
+	t.Helper()
+
+	var issuer *oidcmock.Issuer
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		issuer.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	var err error
+	issuer, err = oidcmock.New(oidcmock.Config{
+		Issuer:   server.URL,
+		ClientID: clientID,
+		Users: map[string]oidcmock.User{
+			"alice":      {Subject: "alice-sub", Email: "alice@example.com", EmailVerified: true, Name: "Alice"},
+			"unverified": {Subject: "mallory-sub", Email: "alice@example.com", Name: "Mallory"},
+		},
+		DefaultUser: oidcmock.User{Subject: "bob-sub", Email: "bob@example.com", EmailVerified: true, Name: "Bob"},
+	})
+	if err != nil {
+		t.Fatalf("oidcmock.New: %v", err)
+	}
+
+	registry, err := sso.NewRegistry(sso.Config{Providers: map[string]sso.ProviderConfig{
+		providerName: {Enabled: true, Issuer: server.URL, ClientID: clientID, RedirectURL: redirectURL},
+	}})
+	if err != nil {
+		t.Fatalf("sso.NewRegistry: %v", err)
+	}
+
+	memory := newMemoryStore()
+	tokens := auth.NewTokenManager(auth.Config{JWTSecret: "test-secret", Issuer: "test"})
+	users := user_service.NewService(zap.NewNop(), user_service.Config{}, memory, tokens, nil, nil, nil, nil)
+
+	return &env{
+		store:   memory,
+		service: sso_service.NewService(zap.NewNop(), sso.Config{}, memory, registry, users),
+		tokens:  tokens,
+	}
+}
+
+// begin проходит авторизацию у провайдера и возвращает state и code из
+// перенаправления на callback.
+func (e *env) begin(t *testing.T, loginHint string) (string, string) {
+	t.Helper()
+
+	authURL, err := e.service.Begin(context.Background(), providerName)
+	if err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+
+	if loginHint != "" {
+		u, err := url.Parse(authURL)
+		if err != nil {
+			t.Fatalf("parse auth url: %v", err)
+		}
+		q := u.Query()
+		q.Set("login_hint", loginHint)
+		u.RawQuery = q.Encode()
+		authURL = u.String()
+	}
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	resp, err := client.Get(authURL)
+	if err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("authorize status = %d, want %d", resp.StatusCode, http.StatusFound)
+	}
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatalf("parse callback: %v", err)
+	}
+
+	return location.Query().Get("state"), location.Query().Get("code")
+}
+
+func (e *env) subject(t *testing.T, result model.LoginResult) uuid.UUID {
+	t.Helper()
+
+	claims, err := e.tokens.Parse(result.AccessToken, auth.TokenTypeAccess)
+	if err != nil {
+		t.Fatalf("Parse access token: %v", err)
+	}
+
+	userUUID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		t.Fatalf("access token subject %q: %v", claims.Subject, err)
+	}
+
+	return userUUID
+}
+
+func TestLoginCreatesUser(t *testing.T) {
+	e := newEnv(t)
+
+	state, code := e.begin(t, "")
+	result, err := e.service.Complete(context.Background(), providerName, state, code, "127.0.0.1")
+	if err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+
+	user, err := e.store.GetUserByIdentity(context.Background(), providerName, "bob-sub")
+	if err != nil {
+		t.Fatalf("identity not created: %v", err)
+	}
+	if user.Email != "bob@example.com" || !user.EmailVerified() {
+		t.Errorf("created user = %+v, want verified bob@example.com", user)
+	}
+	if got := e.subject(t, result); got != user.UUID {
+		t.Errorf("access token subject = %s, want %s", got, user.UUID)
+	}
+
+	// Повторный вход находит ту же учётную запись по внешнему идентификатору.
+	state, code = e.begin(t, "")
+	result, err = e.service.Complete(context.Background(), providerName, state, code, "127.0.0.1")
+	if err != nil {
+		t.Fatalf("second Complete: %v", err)
+	}
+	if got := e.subject(t, result); got != user.UUID {
+		t.Errorf("second login subject = %s, want %s", got, user.UUID)
+	}
+}
+
+func TestLoginLinksExistingAccount(t *testing.T) {
+	e := newEnv(t)
+	existing := e.store.addUser(model.User{Email: "alice@example.com", FirstName: "Alice"})
+
+	state, code := e.begin(t, "alice")
+	result, err := e.service.Complete(context.Background(), providerName, state, code, "127.0.0.1")
+	if err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+
+	if got := e.subject(t, result); got != existing.UUID {
+		t.Errorf("access token subject = %s, want existing user %s", got, existing.UUID)
+	}
+	if len(e.store.linked) != 1 || e.store.linked[0].Subject != "alice-sub" {
+		t.Errorf("linked identities = %+v, want alice-sub", e.store.linked)
+	}
+	if user := e.store.users[existing.UUID]; !user.EmailVerified() {
+		t.Error("email confirmed by the provider must mark the account verified")
+	}
+}
+
+func TestLoginRejectsUnverifiedEmailOfExistingAccount(t *testing.T) {
+	e := newEnv(t)
+	e.store.addUser(model.User{Email: "alice@example.com", FirstName: "Alice"})
+
+	state, code := e.begin(t, "unverified")
+	_, err := e.service.Complete(context.Background(), providerName, state, code, "127.0.0.1")
+	if !errors.Is(err, model.ErrAlreadyExists) {
+		t.Fatalf("Complete error = %v, want ErrAlreadyExists", err)
+	}
+	if len(e.store.linked) != 0 {
+		t.Errorf("identity linked without verified email: %+v", e.store.linked)
+	}
+}
+
+func TestCompleteRejects(t *testing.T) {
+	tests := []struct {
+		name string
+		// prepare меняет state и code после авторизации у провайдера.
+		prepare func(e *env, state, code string) (string, string)
+		want    error
+	}{
+		{
+			name: "unknown state",
+			prepare: func(_ *env, _, code string) (string, string) {
+				return "forged", code
+			},
+			want: model.ErrInvalidArgument,
+		},
+		{
+			name: "missing code",
+			prepare: func(_ *env, state, _ string) (string, string) {
+				return state, ""
+			},
+			want: model.ErrInvalidArgument,
+		},
+		{
+			name: "expired state",
+			prepare: func(e *env, state, code string) (string, string) {
+				e.store.tamper(func(s *model.SSOState) { s.ExpiresAt = time.Now().Add(-time.Second) })
+				return state, code
+			},
+			want: model.ErrInvalidArgument,
+		},
+		{
+			name: "pkce verifier mismatch",
+			prepare: func(e *env, state, code string) (string, string) {
+				e.store.tamper(func(s *model.SSOState) { s.CodeVerifier = "another-verifier-of-sufficient-length-0123456789" })
+				return state, code
+			},
+			want: model.ErrInvalidCredentials,
+		},
+		{
+			name: "nonce mismatch",
+			prepare: func(e *env, state, code string) (string, string) {
+				e.store.tamper(func(s *model.SSOState) { s.Nonce = "another-nonce" })
+				return state, code
+			},
+			want: model.ErrInvalidCredentials,
+		},
+		{
+			name: "forged code",
+			prepare: func(_ *env, state, _ string) (string, string) {
+				return state, "forged"
+			},
+			want: model.ErrInvalidCredentials,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			e := newEnv(t)
+
+			state, code := e.begin(t, "")
+			state, code = tc.prepare(e, state, code)
+
+			if _, err := e.service.Complete(context.Background(), providerName, state, code, "127.0.0.1"); !errors.Is(err, tc.want) {
+				t.Errorf("Complete error = %v, want %v", err, tc.want)
+			}
+			if _, err := e.store.GetUserByIdentity(context.Background(), providerName, "bob-sub"); err == nil {
+				t.Error("rejected login must not create a user")
+			}
+		})
+	}
+}
+
+func TestCompleteRejectsReusedState(t *testing.T) {
+	e := newEnv(t)
+
+	state, code := e.begin(t, "")
+	if _, err := e.service.Complete(context.Background(), providerName, state, code, "127.0.0.1"); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+
+	if _, err := e.service.Complete(context.Background(), providerName, state, code, "127.0.0.1"); !errors.Is(err, model.ErrInvalidArgument) {
+		t.Errorf("reused state error = %v, want ErrInvalidArgument", err)
+	}
+}
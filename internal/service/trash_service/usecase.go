@@ -0,0 +1,83 @@
+package trash_service
+
+import (
+	"context"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"go.uber.org/zap"
+	"time"
+)
+
+type Config struct {
+	// Retention сколько удалённое можно восстановить, после этого его удаляет очистка.
+	Retention time.Duration `yaml:"retention"`
+	// PurgeInterval период запуска очистки.
+	PurgeInterval time.Duration `yaml:"purge_interval"`
+	// PurgeBatch число записей каждого вида, удаляемых за одну транзакцию.
+	PurgeBatch int `yaml:"purge_batch"`
+}
+
+func (c Config) withDefaults() Config {
+	if c.Retention <= 0 {
+		c.Retention = 30 * 24 * time.Hour
+	}
+	if c.PurgeInterval <= 0 {
+		c.PurgeInterval = time.Hour
+	}
+	if c.PurgeBatch <= 0 {
+		c.PurgeBatch = 100
+	}
+	return c
+}
+
+type trashStore interface {
+	PurgeDeleted(ctx context.Context, cutoff time.Time, batch int) (model.PurgeResult, bool, error)
+}
+
+type Service struct {
+	logger *zap.Logger
+	cfg    Config
+
+	trashStore trashStore
+}
+
+func NewService(logger *zap.Logger, cfg Config, trashStore trashStore) *Service {
+	return &Service{
+		logger:     logger,
+		cfg:        cfg.withDefaults(),
+		trashStore: trashStore,
+	}
+}
+
+// Cutoff граница корзины: удалённое раньше этого момента восстановить нельзя.
+func (s *Service) Cutoff() time.Time {
+	return time.Now().Add(-s.cfg.Retention)
+}
+
+// PurgeInterval период запуска Purge.
+func (s *Service) PurgeInterval() time.Duration {
+	return s.cfg.PurgeInterval
+}
+
+// Purge окончательно удаляет пользователей, посты и комментарии, пролежавшие
+// в корзине дольше Retention. Экземпляры сервиса не мешают друг другу:
+// очистку выполняет тот, кто взял advisory-блокировку.
+func (s *Service) Purge(ctx context.Context) error {
+	cutoff := s.Cutoff()
+
+	for {
+		result, locked, err := s.trashStore.PurgeDeleted(ctx, cutoff, s.cfg.PurgeBatch)
+		if err != nil {
+			return err
+		}
+
+		if !locked || result.Empty() {
+			return nil
+		}
+
+		s.logger.Info("trash purged",
+			zap.Int("users", result.Users),
+			zap.Int("posts", result.Posts),
+			zap.Int("comments", result.Comments),
+		)
+	}
+}
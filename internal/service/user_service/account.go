@@ -0,0 +1,45 @@
+package user_service
+
+import (
+	"context"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/google/uuid"
+)
+
+// DeleteAccount переносит в корзину собственную учётную запись вместе с постами
+// и комментариями. Токены и API-ключи удалённого пользователя перестают работать сразу.
+func (s *Service) DeleteAccount(ctx context.Context, userUUID uuid.UUID) error {
+	return s.DeleteUser(ctx, userUUID, userUUID)
+}
+
+// DeleteUser переносит учётную запись в корзину. Права users.delete проверяет
+// политика авторизации, здесь только журналируется, кто удалил.
+func (s *Service) DeleteUser(ctx context.Context, actorUUID, userUUID uuid.UUID) error {
+	if err := s.userStore.DeleteUser(ctx, userUUID); err != nil {
+		return err
+	}
+
+	s.audit(ctx, model.AuditEvent{
+		Event:    model.AuditAccountDeleted,
+		UserUUID: userUUID,
+		Details:  map[string]any{"actor_uuid": actorUUID.String()},
+	})
+
+	return nil
+}
+
+// RestoreUser возвращает учётную запись и всё, что было удалено вместе с ней,
+// пока не истёк срок хранения корзины.
+func (s *Service) RestoreUser(ctx context.Context, actorUUID, userUUID uuid.UUID) error {
+	if err := s.userStore.RestoreUser(ctx, userUUID, s.trash.Cutoff()); err != nil {
+		return err
+	}
+
+	s.audit(ctx, model.AuditEvent{
+		Event:    model.AuditAccountRestored,
+		UserUUID: userUUID,
+		Details:  map[string]any{"actor_uuid": actorUUID.String()},
+	})
+
+	return nil
+}
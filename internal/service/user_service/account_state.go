@@ -0,0 +1,104 @@
+package user_service
+
+import (
+	"context"
+	"fmt"
+	"github.com/AdilBaidual/baseProject/internal/auth"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/google/uuid"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const maxStateReasonLength = 500
+
+var accountStateEvents = map[string]string{
+	model.AccountStateActive:       model.AuditAccountReinstated,
+	model.AccountStateSuspended:    model.AuditAccountSuspended,
+	model.AccountStateBanned:       model.AuditAccountBanned,
+	model.AccountStateShadowBanned: model.AuditAccountShadowBanned,
+}
+
+// SetAccountState назначает пользователю состояние аккаунта. Ограничить другого
+// модератора может только тот, кто управляет ролями. При бане все токены доступа
+// и API-ключи пользователя отзываются сразу.
+func (s *Service) SetAccountState(ctx context.Context, actor auth.Identity, userUUID uuid.UUID, restriction model.AccountRestriction) error {
+	restriction.Reason = strings.TrimSpace(restriction.Reason)
+	restriction.ActorUUID = actor.UserUUID
+
+	if err := validateRestriction(restriction, time.Now()); err != nil {
+		return err
+	}
+
+	if userUUID == actor.UserUUID {
+		return fmt.Errorf("%w: cannot change own account state", model.ErrPermissionDenied)
+	}
+
+	permissions, err := s.userStore.GetUserPermissions(ctx, userUUID)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(permissions, model.PermissionUsersBan) && !actor.HasPermission(model.PermissionRolesManage) {
+		return fmt.Errorf("%w: permission %q required to restrict a moderator", model.ErrPermissionDenied, model.PermissionRolesManage)
+	}
+
+	banned := restriction.State == model.AccountStateBanned
+	if err = s.userStore.SetAccountState(ctx, userUUID, restriction, banned); err != nil {
+		return err
+	}
+
+	details := map[string]any{"actor_uuid": actor.UserUUID.String()}
+	if restriction.Reason != "" {
+		details["reason"] = restriction.Reason
+	}
+	if restriction.Until != nil {
+		details["until"] = restriction.Until.UTC().Format(time.RFC3339)
+	}
+
+	s.audit(ctx, model.AuditEvent{
+		Event:    accountStateEvents[restriction.State],
+		UserUUID: userUUID,
+		Details:  details,
+	})
+
+	return nil
+}
+
+func validateRestriction(restriction model.AccountRestriction, now time.Time) error {
+	if !slices.Contains(model.AccountStates, restriction.State) {
+		return fmt.Errorf("%w: unknown account state %q", model.ErrInvalidArgument, restriction.State)
+	}
+
+	if restriction.State == model.AccountStateActive {
+		if restriction.Until != nil {
+			return fmt.Errorf("%w: until is not allowed for active state", model.ErrInvalidArgument)
+		}
+		return nil
+	}
+
+	if restriction.Reason == "" {
+		return fmt.Errorf("%w: reason is required", model.ErrInvalidArgument)
+	}
+	if utf8.RuneCountInString(restriction.Reason) > maxStateReasonLength {
+		return fmt.Errorf("%w: reason must be at most %d characters", model.ErrInvalidArgument, maxStateReasonLength)
+	}
+
+	if restriction.State == model.AccountStateSuspended && restriction.Until == nil {
+		return fmt.Errorf("%w: until is required for suspension", model.ErrInvalidArgument)
+	}
+	if restriction.Until != nil && !restriction.Until.After(now) {
+		return fmt.Errorf("%w: until must be in the future", model.ErrInvalidArgument)
+	}
+
+	return nil
+}
+
+func checkNotBanned(user model.User) error {
+	if user.State(time.Now()) == model.AccountStateBanned {
+		return fmt.Errorf("%w: account is banned", model.ErrPermissionDenied)
+	}
+
+	return nil
+}
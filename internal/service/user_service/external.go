@@ -0,0 +1,116 @@
+package user_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"strings"
+	"time"
+)
+
+// LoginExternal входит по учётной записи внешнего провайдера. Учётная запись
+// ищется по паре provider/subject. Новая пара привязывается к существующему
+// пользователю только если провайдер подтвердил email, иначе создаётся новый пользователь.
+func (s *Service) LoginExternal(ctx context.Context, identity model.ExternalIdentity, ip string) (model.LoginResult, error) {
+	if identity.Provider == "" || identity.Subject == "" {
+		return model.LoginResult{}, fmt.Errorf("%w: incomplete external identity", model.ErrInvalidArgument)
+	}
+
+	identity.Email = strings.ToLower(strings.TrimSpace(identity.Email))
+
+	user, err := s.userStore.GetUserByIdentity(ctx, identity.Provider, identity.Subject)
+	if err == nil {
+		return s.completeLogin(ctx, user, ip)
+	}
+	if !errors.Is(err, model.ErrNotFound) {
+		return model.LoginResult{}, err
+	}
+
+	user, err = s.userStore.GetUserByEmail(ctx, identity.Email)
+	switch {
+	case err == nil:
+		user, err = s.linkIdentity(ctx, user, identity, ip)
+	case errors.Is(err, model.ErrNotFound):
+		user, err = s.createExternalUser(ctx, identity, ip)
+	}
+	if err != nil {
+		return model.LoginResult{}, err
+	}
+
+	return s.completeLogin(ctx, user, ip)
+}
+
+func (s *Service) linkIdentity(ctx context.Context, user model.User, identity model.ExternalIdentity, ip string) (model.User, error) {
+	// Без подтверждения от провайдера кто угодно мог бы указать чужой email
+	// и получить доступ к существующей учётной записи.
+	if !identity.EmailVerified {
+		return model.User{}, fmt.Errorf("%w: email is already registered", model.ErrAlreadyExists)
+	}
+
+	if err := s.userStore.LinkIdentity(ctx, user.UUID, identity); err != nil {
+		return model.User{}, err
+	}
+
+	if !user.EmailVerified() {
+		if err := s.userStore.MarkEmailVerified(ctx, user.UUID); err != nil {
+			return model.User{}, err
+		}
+	}
+
+	s.audit(ctx, model.AuditEvent{
+		Event:    model.AuditIdentityLinked,
+		UserUUID: user.UUID,
+		IP:       ip,
+		Details:  map[string]any{"provider": identity.Provider, "subject": identity.Subject},
+	})
+
+	return user, nil
+}
+
+func (s *Service) createExternalUser(ctx context.Context, identity model.ExternalIdentity, ip string) (model.User, error) {
+	email, err := normalizeEmail(identity.Email)
+	if err != nil {
+		return model.User{}, err
+	}
+
+	firstName := strings.TrimSpace(identity.Name)
+	if firstName == "" {
+		firstName = email[:strings.IndexByte(email, '@')]
+	}
+
+	// Пароля у такого пользователя нет. Хеш случайного значения вместо пустой
+	// строки, чтобы вход по паролю занимал столько же времени, сколько обычно.
+	randomPassword, err := newToken()
+	if err != nil {
+		return model.User{}, err
+	}
+
+	hash, err := hashPassword(randomPassword)
+	if err != nil {
+		return model.User{}, err
+	}
+
+	user := model.User{
+		Email:        email,
+		FirstName:    firstName,
+		PasswordHash: hash,
+	}
+	if identity.EmailVerified {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+	}
+
+	if user.UUID, err = s.userStore.CreateUserWithIdentity(ctx, user, identity); err != nil {
+		return model.User{}, err
+	}
+
+	s.audit(ctx, model.AuditEvent{
+		Event:    model.AuditIdentityCreated,
+		UserUUID: user.UUID,
+		IP:       ip,
+		Details:  map[string]any{"provider": identity.Provider, "subject": identity.Subject},
+	})
+
+	return user, nil
+}
@@ -0,0 +1,294 @@
+package user_service
+
+import (
+	"context"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+	"time"
+)
+
+type LockoutConfig struct {
+	// AccountThreshold число неудач подряд для одного email до блокировки.
+	AccountThreshold int `yaml:"account_threshold"`
+	// IPThreshold число неудач с одного IP до блокировки.
+	IPThreshold int `yaml:"ip_threshold"`
+	// Window после этого времени без неудач счётчик начинается заново.
+	Window time.Duration `yaml:"window"`
+	// Duration длительность первой блокировки, каждая следующая вдвое длиннее.
+	Duration    time.Duration `yaml:"duration"`
+	MaxDuration time.Duration `yaml:"max_duration"`
+	// DelayAfter число неудач, после которого ответ задерживается.
+	DelayAfter int           `yaml:"delay_after"`
+	BaseDelay  time.Duration `yaml:"base_delay"`
+	MaxDelay   time.Duration `yaml:"max_delay"`
+	// Retention через сколько после последней неудачи счётчик удаляется,
+	// если нет действующей блокировки.
+	Retention time.Duration `yaml:"retention"`
+}
+
+func (c LockoutConfig) withDefaults() LockoutConfig {
+	if c.AccountThreshold <= 0 {
+		c.AccountThreshold = 5
+	}
+	if c.IPThreshold <= 0 {
+		c.IPThreshold = 50
+	}
+	if c.Window <= 0 {
+		c.Window = time.Hour
+	}
+	if c.Duration <= 0 {
+		c.Duration = 15 * time.Minute
+	}
+	if c.MaxDuration <= 0 {
+		c.MaxDuration = 24 * time.Hour
+	}
+	if c.DelayAfter <= 0 {
+		c.DelayAfter = 2
+	}
+	if c.BaseDelay <= 0 {
+		c.BaseDelay = 250 * time.Millisecond
+	}
+	if c.MaxDelay <= 0 {
+		c.MaxDelay = 4 * time.Second
+	}
+	if c.Retention <= 0 {
+		c.Retention = 7 * 24 * time.Hour
+	}
+	if c.Retention < c.Window {
+		c.Retention = c.Window
+	}
+	return c
+}
+
+// auditCleanupBatch число событий аудита, удаляемых одним запросом.
+const auditCleanupBatch = 1000
+
+// dummyHash используется при входе с неизвестным email, чтобы время ответа
+// не отличалось от проверки пароля существующего пользователя.
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("dummy password for timing"), bcrypt.DefaultCost)
+
+// checkLoginAllowed отклоняет попытку, если заблокирован email или IP.
+// Блокировка считается по строке email, поэтому не выдаёт, существует ли пользователь.
+func (s *Service) checkLoginAllowed(ctx context.Context, email, ip string) error {
+	now := time.Now()
+
+	account, err := s.userStore.GetLoginFailures(ctx, model.LoginScopeAccount, email)
+	if err != nil {
+		return err
+	}
+
+	if account.Locked(now) {
+		s.audit(ctx, model.AuditEvent{
+			Event:   model.AuditLoginBlocked,
+			IP:      ip,
+			Details: map[string]any{"email": email, "scope": model.LoginScopeAccount},
+		})
+		return model.ErrTooManyAttempts
+	}
+
+	if ip == "" {
+		return nil
+	}
+
+	byIP, err := s.userStore.GetLoginFailures(ctx, model.LoginScopeIP, ip)
+	if err != nil {
+		return err
+	}
+
+	if byIP.Locked(now) {
+		s.audit(ctx, model.AuditEvent{
+			Event:   model.AuditLoginBlocked,
+			IP:      ip,
+			Details: map[string]any{"email": email, "scope": model.LoginScopeIP},
+		})
+		return model.ErrTooManyAttempts
+	}
+
+	return nil
+}
+
+// loginFailed учитывает неудачу, при необходимости блокирует email или IP
+// и задерживает ответ тем сильнее, чем больше неудач подряд.
+func (s *Service) loginFailed(ctx context.Context, event string, userUUID uuid.UUID, email, ip string) error {
+	s.audit(ctx, model.AuditEvent{
+		Event:    event,
+		UserUUID: userUUID,
+		IP:       ip,
+		Details:  map[string]any{"email": email},
+	})
+
+	account, err := s.recordFailure(ctx, model.LoginScopeAccount, email, s.cfg.Lockout.AccountThreshold, userUUID, ip)
+	if err != nil {
+		return err
+	}
+
+	if ip != "" {
+		if _, err = s.recordFailure(ctx, model.LoginScopeIP, ip, s.cfg.Lockout.IPThreshold, uuid.Nil, ip); err != nil {
+			return err
+		}
+	}
+
+	if err = sleepCtx(ctx, s.failureDelay(account.Failures)); err != nil {
+		return err
+	}
+
+	return model.ErrInvalidCredentials
+}
+
+func (s *Service) recordFailure(ctx context.Context, scope, subject string, threshold int, userUUID uuid.UUID, ip string) (model.LoginFailures, error) {
+	f, err := s.userStore.RecordLoginFailure(ctx, scope, subject, s.cfg.Lockout.Window)
+	if err != nil {
+		return model.LoginFailures{}, err
+	}
+
+	if f.Failures < threshold {
+		return f, nil
+	}
+
+	duration := s.cfg.Lockout.Duration << min(f.Lockouts, 16)
+	if duration <= 0 || duration > s.cfg.Lockout.MaxDuration {
+		duration = s.cfg.Lockout.MaxDuration
+	}
+
+	if err = s.userStore.LockLogin(ctx, scope, subject, time.Now().Add(duration)); err != nil {
+		return model.LoginFailures{}, err
+	}
+
+	event := model.AuditAccountLocked
+	if scope == model.LoginScopeIP {
+		event = model.AuditIPLocked
+	}
+
+	s.audit(ctx, model.AuditEvent{
+		Event:    event,
+		UserUUID: userUUID,
+		IP:       ip,
+		Details:  map[string]any{"subject": subject, "duration": duration.String(), "lockouts": f.Lockouts + 1},
+	})
+
+	return f, nil
+}
+
+func (s *Service) loginSucceeded(ctx context.Context, userUUID uuid.UUID, email, ip string) {
+	if err := s.userStore.ResetLoginFailures(ctx, model.LoginScopeAccount, email); err != nil {
+		s.logger.Error("failed to reset login failures", zap.Error(err))
+	}
+
+	s.audit(ctx, model.AuditEvent{
+		Event:    model.AuditLoginSucceeded,
+		UserUUID: userUUID,
+		IP:       ip,
+	})
+}
+
+func (s *Service) failureDelay(failures int) time.Duration {
+	if failures <= s.cfg.Lockout.DelayAfter {
+		return 0
+	}
+
+	delay := s.cfg.Lockout.BaseDelay << min(failures-s.cfg.Lockout.DelayAfter-1, 16)
+	if delay <= 0 || delay > s.cfg.Lockout.MaxDelay {
+		delay = s.cfg.Lockout.MaxDelay
+	}
+
+	return delay
+}
+
+// UnlockAccount снимает блокировку входа с учётной записи.
+func (s *Service) UnlockAccount(ctx context.Context, actorUUID, userUUID uuid.UUID) error {
+	user, err := s.userStore.GetUserByUUID(ctx, userUUID)
+	if err != nil {
+		return err
+	}
+
+	if err = s.userStore.ResetLoginFailures(ctx, model.LoginScopeAccount, user.Email); err != nil {
+		return err
+	}
+
+	s.audit(ctx, model.AuditEvent{
+		Event:     model.AuditAccountUnlocked,
+		UserUUID:  userUUID,
+		ActorUUID: actorUUID,
+	})
+
+	return nil
+}
+
+// audit пишет событие в журнал. Ошибка записи не прерывает запрос.
+func (s *Service) audit(ctx context.Context, event model.AuditEvent) {
+	s.logger.Info("audit event",
+		zap.String("event", event.Event),
+		zap.String("user_uuid", event.UserUUID.String()),
+		zap.String("ip", event.IP),
+	)
+
+	if err := s.userStore.CreateAuditEvent(context.WithoutCancel(ctx), event); err != nil {
+		s.logger.Error("failed to write audit event", zap.Error(err), zap.String("event", event.Event))
+	}
+}
+
+// CleanupInterval период запуска Cleanup.
+func (s *Service) CleanupInterval() time.Duration {
+	return s.cfg.CleanupInterval
+}
+
+// Cleanup удаляет устаревшие счётчики неудачных входов, старые события
+// аудита и отработавшие одноразовые токены. Счётчики заводятся на любой email
+// и IP, с которых пытались войти, а успешный вход сбрасывает только свои,
+// токены же остаются и после использования, так что без очистки эти таблицы
+// только растут. Удаление идемпотентно, экземпляры запускают его независимо.
+func (s *Service) Cleanup(ctx context.Context) error {
+	now := time.Now()
+
+	failures, err := s.userStore.DeleteStaleLoginFailures(ctx, now.Add(-s.cfg.Lockout.Retention))
+	if err != nil {
+		return err
+	}
+
+	var events int64
+	cutoff := now.Add(-s.cfg.AuditRetention)
+	for {
+		deleted, err := s.userStore.DeleteAuditEvents(ctx, cutoff, auditCleanupBatch)
+		if err != nil {
+			return err
+		}
+
+		events += deleted
+		if deleted < auditCleanupBatch {
+			break
+		}
+	}
+
+	tokens, err := s.userStore.DeleteSpentUserTokens(ctx, now.Add(-s.cfg.PasswordResetInterval))
+	if err != nil {
+		return err
+	}
+
+	if failures > 0 || events > 0 || tokens > 0 {
+		s.logger.Info("login failures, audit events and user tokens cleaned up",
+			zap.Int64("login_failures", failures),
+			zap.Int64("audit_events", events),
+			zap.Int64("user_tokens", tokens),
+		)
+	}
+
+	return nil
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,238 @@
+package user_service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"github.com/AdilBaidual/baseProject/internal/auth"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/AdilBaidual/baseProject/pkg/totp"
+	"github.com/google/uuid"
+	"strings"
+	"time"
+)
+
+const (
+	recoveryCodeCount = 10
+	// 10 байт дают ровно 16 символов base32.
+	recoveryCodeBytes = 10
+	// totpSkew допуск в шагах на расхождение часов клиента и сервера.
+	totpSkew = 1
+)
+
+var recoveryEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// EnrollTOTP создаёт новый секрет и возвращает его вместе с otpauth:// ссылкой.
+// 2FA включается только после ConfirmTOTP.
+func (s *Service) EnrollTOTP(ctx context.Context, userUUID uuid.UUID) (string, string, error) {
+	user, err := s.userStore.GetUserByUUID(ctx, userUUID)
+	if err != nil {
+		return "", "", err
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return "", "", err
+	}
+
+	encrypted, err := s.box.Seal([]byte(secret), userUUID[:])
+	if err != nil {
+		return "", "", err
+	}
+
+	if err = s.userStore.SavePendingMFA(ctx, userUUID, encrypted); err != nil {
+		if errors.Is(err, model.ErrAlreadyExists) {
+			return "", "", fmt.Errorf("%w: two-factor authentication already enabled", model.ErrAlreadyExists)
+		}
+		return "", "", err
+	}
+
+	return secret, totp.URI(s.cfg.TOTPIssuer, user.Email, secret), nil
+}
+
+// ConfirmTOTP включает 2FA по первому коду из приложения и возвращает коды восстановления.
+// Коды показываются один раз, в базе хранятся только их хеши.
+func (s *Service) ConfirmTOTP(ctx context.Context, userUUID uuid.UUID, code string) ([]string, error) {
+	mfa, err := s.userStore.GetMFA(ctx, userUUID)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, fmt.Errorf("%w: two-factor enrollment not started", model.ErrInvalidArgument)
+		}
+		return nil, err
+	}
+
+	if mfa.Enabled() {
+		return nil, fmt.Errorf("%w: two-factor authentication already enabled", model.ErrAlreadyExists)
+	}
+
+	secret, err := s.openSecret(mfa)
+	if err != nil {
+		return nil, err
+	}
+
+	step, ok := totp.Validate(secret, code, time.Now(), totpSkew)
+	if !ok {
+		return nil, fmt.Errorf("%w: invalid code", model.ErrInvalidArgument)
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.userStore.EnableMFA(ctx, userUUID, step, hashes); err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, fmt.Errorf("%w: invalid code", model.ErrInvalidArgument)
+		}
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// DisableTOTP отключает 2FA. Требуется действующий код из приложения или код восстановления.
+func (s *Service) DisableTOTP(ctx context.Context, userUUID uuid.UUID, code, recoveryCode string) error {
+	mfa, err := s.userStore.GetMFA(ctx, userUUID)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return fmt.Errorf("%w: two-factor authentication is not enabled", model.ErrInvalidArgument)
+		}
+		return err
+	}
+
+	if mfa.Enabled() {
+		if err = s.checkSecondFactor(ctx, mfa, code, recoveryCode); err != nil {
+			return err
+		}
+	}
+
+	return s.userStore.DeleteMFA(ctx, userUUID)
+}
+
+// VerifyMFA второй шаг входа: обменивает MFA-токен и код на токен доступа.
+// Неверные коды учитываются в тех же счётчиках, что и неверные пароли.
+func (s *Service) VerifyMFA(ctx context.Context, mfaToken, code, recoveryCode, ip string) (model.LoginResult, error) {
+	claims, err := s.tokens.Parse(mfaToken, auth.TokenTypeMFA)
+	if err != nil {
+		return model.LoginResult{}, model.ErrInvalidCredentials
+	}
+
+	userUUID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return model.LoginResult{}, model.ErrInvalidCredentials
+	}
+
+	user, err := s.userStore.GetUserByUUID(ctx, userUUID)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return model.LoginResult{}, model.ErrInvalidCredentials
+		}
+		return model.LoginResult{}, err
+	}
+
+	// Отзыв сессий между вводом пароля и второго фактора, например при смене
+	// пароля или бане, отменяет и промежуточный токен.
+	if issuedBeforeRevocation(user, claims) {
+		return model.LoginResult{}, model.ErrInvalidCredentials
+	}
+
+	if err = s.checkLoginAllowed(ctx, user.Email, ip); err != nil {
+		return model.LoginResult{}, err
+	}
+
+	if err = checkNotBanned(user); err != nil {
+		return model.LoginResult{}, err
+	}
+
+	mfa, err := s.userStore.GetMFA(ctx, userUUID)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return model.LoginResult{}, model.ErrInvalidCredentials
+		}
+		return model.LoginResult{}, err
+	}
+
+	if !mfa.Enabled() {
+		return model.LoginResult{}, model.ErrInvalidCredentials
+	}
+
+	if err = s.checkSecondFactor(ctx, mfa, code, recoveryCode); err != nil {
+		if errors.Is(err, model.ErrInvalidCredentials) {
+			return model.LoginResult{}, s.loginFailed(ctx, model.AuditMFAFailed, userUUID, user.Email, ip)
+		}
+		return model.LoginResult{}, err
+	}
+
+	s.loginSucceeded(ctx, userUUID, user.Email, ip)
+
+	return s.issueAccess(userUUID)
+}
+
+// checkSecondFactor проверяет TOTP-код или код восстановления. Оба одноразовые:
+// шаг TOTP и код восстановления отмечаются использованными атомарно.
+func (s *Service) checkSecondFactor(ctx context.Context, mfa model.MFA, code, recoveryCode string) error {
+	if recoveryCode != "" {
+		ok, err := s.userStore.UseRecoveryCode(ctx, mfa.UserUUID, hashToken(normalizeRecoveryCode(recoveryCode)))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return model.ErrInvalidCredentials
+		}
+		return nil
+	}
+
+	secret, err := s.openSecret(mfa)
+	if err != nil {
+		return err
+	}
+
+	step, ok := totp.Validate(secret, code, time.Now(), totpSkew)
+	if !ok {
+		return model.ErrInvalidCredentials
+	}
+
+	ok, err = s.userStore.UseTOTPStep(ctx, mfa.UserUUID, step)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return model.ErrInvalidCredentials
+	}
+
+	return nil
+}
+
+func (s *Service) openSecret(mfa model.MFA) (string, error) {
+	secret, err := s.box.Open(mfa.SecretEncrypted, mfa.UserUUID[:])
+	if err != nil {
+		return "", fmt.Errorf("openSecret - box.Open - %w", err)
+	}
+
+	return string(secret), nil
+}
+
+func newRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+
+	for i := 0; i < recoveryCodeCount; i++ {
+		b := make([]byte, recoveryCodeBytes)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, fmt.Errorf("newRecoveryCodes - rand.Read - %w", err)
+		}
+
+		raw := strings.ToLower(recoveryEncoding.EncodeToString(b))
+		codes = append(codes, raw[:8]+"-"+raw[8:16])
+		hashes = append(hashes, hashToken(raw[:16]))
+	}
+
+	return codes, hashes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
@@ -0,0 +1,372 @@
+package user_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/AdilBaidual/baseProject/internal/auth"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/AdilBaidual/baseProject/pkg/mailer"
+	"github.com/AdilBaidual/baseProject/pkg/secretbox"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+	"net/mail"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	minPasswordLength = 8
+	maxPasswordLength = 72
+)
+
+var roles = []string{model.RoleAdmin, model.RoleModerator, model.RoleUser}
+
+type Config struct {
+	VerificationURL  string        `yaml:"verification_url" env:"USERS_VERIFICATION_URL"`
+	PasswordResetURL string        `yaml:"password_reset_url" env:"USERS_PASSWORD_RESET_URL"`
+	VerificationTTL  time.Duration `yaml:"verification_ttl"`
+	PasswordResetTTL time.Duration `yaml:"password_reset_ttl"`
+	MFAEncryptionKey string        `yaml:"mfa_encryption_key" env:"USERS_MFA_ENCRYPTION_KEY" env-required:"true"`
+	TOTPIssuer       string        `yaml:"totp_issuer"`
+	MFATokenTTL      time.Duration `yaml:"mfa_token_ttl"`
+	Lockout          LockoutConfig `yaml:"lockout"`
+	// UsernameHold сколько освобождённое имя недоступно другим пользователям.
+	UsernameHold time.Duration `yaml:"username_hold"`
+	// UsernameChangeInterval не чаще какого интервала пользователь может
+	// менять имя. Каждое прежнее имя удерживается UsernameHold и остаётся
+	// в истории, поэтому частые переименования не допускаются.
+	UsernameChangeInterval time.Duration `yaml:"username_change_interval"`
+	// PasswordResetInterval не чаще какого интервала пользователю уходит
+	// письмо для сброса пароля: повторные запросы в течение интервала пропускаются.
+	PasswordResetInterval time.Duration `yaml:"password_reset_interval"`
+	// PasswordResetSenders сколько писем для сброса пароля отправляется
+	// одновременно. Запросы сверх этого отбрасываются.
+	PasswordResetSenders int `yaml:"password_reset_senders"`
+	// AuditRetention сколько хранятся события журнала аудита.
+	AuditRetention time.Duration `yaml:"audit_retention"`
+	// CleanupInterval период удаления устаревших счётчиков неудачных входов,
+	// событий аудита и отработавших одноразовых токенов.
+	CleanupInterval time.Duration `yaml:"cleanup_interval"`
+}
+
+type userStore interface {
+	CreateUser(ctx context.Context, user model.User) (uuid.UUID, error)
+	GetUserByEmail(ctx context.Context, email string) (model.User, error)
+	GetUserByUUID(ctx context.Context, id uuid.UUID) (model.User, error)
+	GetUserRoles(ctx context.Context, userUUID uuid.UUID) ([]string, error)
+	GetUserPermissions(ctx context.Context, userUUID uuid.UUID) ([]string, error)
+	AssignRole(ctx context.Context, userUUID uuid.UUID, role string) error
+	RevokeRole(ctx context.Context, userUUID uuid.UUID, role string) error
+	MarkEmailVerified(ctx context.Context, id uuid.UUID) error
+	UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash string) error
+	CreateUserToken(ctx context.Context, userUUID uuid.UUID, purpose, tokenHash string, expiresAt time.Time) error
+	ConsumeUserToken(ctx context.Context, purpose, tokenHash string) (uuid.UUID, error)
+	GetLastUserTokenTime(ctx context.Context, userUUID uuid.UUID, purpose string) (*time.Time, error)
+	DeleteSpentUserTokens(ctx context.Context, cutoff time.Time) (int64, error)
+	SavePendingMFA(ctx context.Context, userUUID uuid.UUID, secretEncrypted []byte) error
+	GetMFA(ctx context.Context, userUUID uuid.UUID) (model.MFA, error)
+	EnableMFA(ctx context.Context, userUUID uuid.UUID, step int64, recoveryCodeHashes []string) error
+	UseTOTPStep(ctx context.Context, userUUID uuid.UUID, step int64) (bool, error)
+	UseRecoveryCode(ctx context.Context, userUUID uuid.UUID, codeHash string) (bool, error)
+	DeleteMFA(ctx context.Context, userUUID uuid.UUID) error
+	GetUserByIdentity(ctx context.Context, provider, subject string) (model.User, error)
+	LinkIdentity(ctx context.Context, userUUID uuid.UUID, identity model.ExternalIdentity) error
+	CreateUserWithIdentity(ctx context.Context, user model.User, identity model.ExternalIdentity) (uuid.UUID, error)
+	GetLoginFailures(ctx context.Context, scope, subject string) (model.LoginFailures, error)
+	RecordLoginFailure(ctx context.Context, scope, subject string, window time.Duration) (model.LoginFailures, error)
+	LockLogin(ctx context.Context, scope, subject string, until time.Time) error
+	ResetLoginFailures(ctx context.Context, scope, subject string) error
+	CreateAuditEvent(ctx context.Context, event model.AuditEvent) error
+	DeleteStaleLoginFailures(ctx context.Context, cutoff time.Time) (int64, error)
+	DeleteAuditEvents(ctx context.Context, cutoff time.Time, batch int) (int64, error)
+	DeleteUser(ctx context.Context, userUUID uuid.UUID) error
+	RestoreUser(ctx context.Context, userUUID uuid.UUID, cutoff time.Time) error
+	SetAccountState(ctx context.Context, userUUID uuid.UUID, restriction model.AccountRestriction, revokeSessions bool) error
+	SetUsername(ctx context.Context, userUUID uuid.UUID, username string, heldSince, changedSince time.Time) error
+	ListUsernameHistory(ctx context.Context, userUUID uuid.UUID) ([]model.UsernameChange, error)
+}
+
+type trashService interface {
+	Cutoff() time.Time
+}
+
+type Service struct {
+	logger *zap.Logger
+	cfg    Config
+
+	userStore userStore
+	tokens    *auth.TokenManager
+	mailer    mailer.Mailer
+	templates *mailer.Templates
+	box       *secretbox.Box
+	trash     trashService
+
+	// resetSenders слоты фоновой отправки писем для сброса пароля.
+	resetSenders chan struct{}
+}
+
+func NewService(
+	logger *zap.Logger,
+	cfg Config,
+	userStore userStore,
+	tokens *auth.TokenManager,
+	mailer mailer.Mailer,
+	templates *mailer.Templates,
+	box *secretbox.Box,
+	trash trashService,
+) *Service {
+	if cfg.VerificationTTL <= 0 {
+		cfg.VerificationTTL = 48 * time.Hour
+	}
+	if cfg.PasswordResetTTL <= 0 {
+		cfg.PasswordResetTTL = time.Hour
+	}
+	if cfg.PasswordResetInterval <= 0 {
+		cfg.PasswordResetInterval = 5 * time.Minute
+	}
+	if cfg.PasswordResetSenders <= 0 {
+		cfg.PasswordResetSenders = 10
+	}
+	if cfg.MFATokenTTL <= 0 {
+		cfg.MFATokenTTL = 5 * time.Minute
+	}
+	if cfg.UsernameHold <= 0 {
+		cfg.UsernameHold = 30 * 24 * time.Hour
+	}
+	if cfg.UsernameChangeInterval <= 0 {
+		cfg.UsernameChangeInterval = 7 * 24 * time.Hour
+	}
+	if cfg.AuditRetention <= 0 {
+		cfg.AuditRetention = 365 * 24 * time.Hour
+	}
+	if cfg.CleanupInterval <= 0 {
+		cfg.CleanupInterval = time.Hour
+	}
+	if cfg.TOTPIssuer == "" {
+		cfg.TOTPIssuer = "baseProject"
+	}
+	cfg.Lockout = cfg.Lockout.withDefaults()
+
+	return &Service{
+		logger:    logger,
+		cfg:       cfg,
+		userStore: userStore,
+		tokens:    tokens,
+		mailer:    mailer,
+		templates: templates,
+		box:       box,
+		trash:     trash,
+
+		resetSenders: make(chan struct{}, cfg.PasswordResetSenders),
+	}
+}
+
+func (s *Service) Register(ctx context.Context, email, firstName, password string) (uuid.UUID, error) {
+	email, err := normalizeEmail(email)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	firstName = strings.TrimSpace(firstName)
+	if firstName == "" {
+		return uuid.Nil, fmt.Errorf("%w: first name is required", model.ErrInvalidArgument)
+	}
+
+	hash, err := hashPassword(password)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	id, err := s.userStore.CreateUser(ctx, model.User{
+		Email:        email,
+		FirstName:    firstName,
+		PasswordHash: hash,
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	if err = s.sendVerification(ctx, model.User{UUID: id, Email: email, FirstName: firstName}); err != nil {
+		s.logger.Error("failed to send verification email", zap.Error(err), zap.String("user_uuid", id.String()))
+	}
+
+	return id, nil
+}
+
+// Login проверяет пароль. Если у пользователя включена 2FA, токен доступа
+// не выдаётся: вместо него возвращается MFA-токен для VerifyMFA.
+// Для неизвестного email и неверного пароля ответ и время ответа одинаковы.
+func (s *Service) Login(ctx context.Context, email, password, ip string) (model.LoginResult, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+
+	if err := s.checkLoginAllowed(ctx, email, ip); err != nil {
+		return model.LoginResult{}, err
+	}
+
+	user, err := s.userStore.GetUserByEmail(ctx, email)
+	if err != nil && !errors.Is(err, model.ErrNotFound) {
+		return model.LoginResult{}, err
+	}
+
+	passwordHash := dummyHash
+	if err == nil {
+		passwordHash = []byte(user.PasswordHash)
+	}
+
+	if bcrypt.CompareHashAndPassword(passwordHash, []byte(password)) != nil || err != nil {
+		return model.LoginResult{}, s.loginFailed(ctx, model.AuditLoginFailed, user.UUID, email, ip)
+	}
+
+	return s.completeLogin(ctx, user, ip)
+}
+
+// completeLogin завершает вход после проверки первого фактора: при включённой
+// 2FA выдаёт MFA-токен, иначе токен доступа.
+func (s *Service) completeLogin(ctx context.Context, user model.User, ip string) (model.LoginResult, error) {
+	if err := checkNotBanned(user); err != nil {
+		return model.LoginResult{}, err
+	}
+
+	mfa, err := s.userStore.GetMFA(ctx, user.UUID)
+	if err != nil && !errors.Is(err, model.ErrNotFound) {
+		return model.LoginResult{}, err
+	}
+
+	if err == nil && mfa.Enabled() {
+		token, expiresAt, err := s.tokens.Issue(user.UUID, auth.TokenTypeMFA, s.cfg.MFATokenTTL)
+		if err != nil {
+			return model.LoginResult{}, err
+		}
+
+		return model.LoginResult{MFARequired: true, MFAToken: token, ExpiresAt: expiresAt}, nil
+	}
+
+	s.loginSucceeded(ctx, user.UUID, user.Email, ip)
+
+	return s.issueAccess(user.UUID)
+}
+
+func (s *Service) issueAccess(userUUID uuid.UUID) (model.LoginResult, error) {
+	token, expiresAt, err := s.tokens.IssueAccess(userUUID)
+	if err != nil {
+		return model.LoginResult{}, err
+	}
+
+	return model.LoginResult{AccessToken: token, ExpiresAt: expiresAt}, nil
+}
+
+func (s *Service) GetUser(ctx context.Context, id uuid.UUID) (model.User, error) {
+	user, err := s.userStore.GetUserByUUID(ctx, id)
+	if err != nil {
+		return model.User{}, err
+	}
+
+	if user.Roles, err = s.userStore.GetUserRoles(ctx, id); err != nil {
+		return model.User{}, err
+	}
+
+	if user.Permissions, err = s.userStore.GetUserPermissions(ctx, id); err != nil {
+		return model.User{}, err
+	}
+
+	return user, nil
+}
+
+// Authenticate проверяет токен доступа и загружает роли и права пользователя.
+func (s *Service) Authenticate(ctx context.Context, token string) (auth.Identity, error) {
+	claims, err := s.tokens.Parse(token, auth.TokenTypeAccess)
+	if err != nil {
+		return auth.Identity{}, err
+	}
+
+	userUUID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return auth.Identity{}, fmt.Errorf("%w: malformed subject", model.ErrUnauthenticated)
+	}
+
+	user, err := s.GetUser(ctx, userUUID)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return auth.Identity{}, model.ErrUnauthenticated
+		}
+		return auth.Identity{}, err
+	}
+
+	state := user.State(time.Now())
+	if state == model.AccountStateBanned {
+		return auth.Identity{}, fmt.Errorf("%w: account is banned", model.ErrUnauthenticated)
+	}
+
+	if issuedBeforeRevocation(user, claims) {
+		return auth.Identity{}, fmt.Errorf("%w: session revoked", model.ErrUnauthenticated)
+	}
+
+	permissions := user.Permissions
+	if !user.EmailVerified() {
+		permissions = auth.RestrictUnverified(permissions)
+	}
+
+	return auth.Identity{
+		UserUUID:      user.UUID,
+		EmailVerified: user.EmailVerified(),
+		Roles:         user.Roles,
+		Permissions:   permissions,
+		AccountState:  state,
+		StateUntil:    user.StateUntil,
+	}, nil
+}
+
+// issuedBeforeRevocation сообщает, выдан ли токен до отзыва сессий пользователя.
+// iat хранится с точностью до секунды, поэтому токен, выданный в ту же секунду,
+// тоже считается отозванным.
+func issuedBeforeRevocation(user model.User, claims auth.Claims) bool {
+	return user.SessionsRevokedAt != nil &&
+		(claims.IssuedAt == nil || !claims.IssuedAt.After(user.SessionsRevokedAt.Truncate(time.Second)))
+}
+
+func (s *Service) AssignRole(ctx context.Context, userUUID uuid.UUID, role string) error {
+	if !slices.Contains(roles, role) {
+		return fmt.Errorf("%w: unknown role %q", model.ErrInvalidArgument, role)
+	}
+
+	return s.userStore.AssignRole(ctx, userUUID, role)
+}
+
+func (s *Service) RevokeRole(ctx context.Context, userUUID uuid.UUID, role string) error {
+	if !slices.Contains(roles, role) {
+		return fmt.Errorf("%w: unknown role %q", model.ErrInvalidArgument, role)
+	}
+
+	return s.userStore.RevokeRole(ctx, userUUID, role)
+}
+
+func normalizeEmail(email string) (string, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return "", fmt.Errorf("%w: invalid email", model.ErrInvalidArgument)
+	}
+
+	return email, nil
+}
+
+func hashPassword(password string) (string, error) {
+	if utf8.RuneCountInString(password) < minPasswordLength {
+		return "", fmt.Errorf("%w: password must be at least %d characters", model.ErrInvalidArgument, minPasswordLength)
+	}
+	if len(password) > maxPasswordLength {
+		return "", fmt.Errorf("%w: password must be at most %d bytes", model.ErrInvalidArgument, maxPasswordLength)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("hashPassword - bcrypt.GenerateFromPassword - %w", err)
+	}
+
+	return string(hash), nil
+}
@@ -0,0 +1,67 @@
+package user_service
+
+import (
+	"context"
+	"fmt"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/google/uuid"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// usernamePattern латиница, цифры и подчёркивание, первым символом — буква.
+// Набор символов совпадает с тем, что распознаётся в тексте как @handle.
+var usernamePattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]{2,29}$`)
+
+// reservedUsernames имена, которые выглядят как служебные или совпадают
+// с частями адресов.
+var reservedUsernames = map[string]struct{}{
+	"admin": {}, "administrator": {}, "root": {}, "system": {}, "staff": {}, "official": {},
+	"moderator": {}, "mod": {}, "support": {}, "help": {}, "security": {}, "abuse": {},
+	"api": {}, "www": {}, "mail": {}, "me": {}, "settings": {}, "notifications": {},
+	"everyone": {}, "here": {}, "all": {}, "anonymous": {}, "deleted": {},
+	"null": {}, "undefined": {}, "baseproject": {},
+}
+
+// SetUsername задаёт имя пользователя. Регистр сохраняется, но имена,
+// различающиеся только регистром, считаются одинаковыми. Сменить имя снова
+// можно не раньше, чем через UsernameChangeInterval.
+func (s *Service) SetUsername(ctx context.Context, userUUID uuid.UUID, username string) (model.User, error) {
+	username, err := normalizeUsername(username)
+	if err != nil {
+		return model.User{}, err
+	}
+
+	now := time.Now()
+	if err = s.userStore.SetUsername(ctx, userUUID, username, now.Add(-s.cfg.UsernameHold), now.Add(-s.cfg.UsernameChangeInterval)); err != nil {
+		return model.User{}, err
+	}
+
+	return s.GetUser(ctx, userUUID)
+}
+
+// normalizeUsername убирает пробелы и ведущий @ и проверяет имя по
+// usernamePattern и списку зарезервированных.
+func normalizeUsername(username string) (string, error) {
+	username = strings.TrimPrefix(strings.TrimSpace(username), "@")
+
+	if !usernamePattern.MatchString(username) {
+		return "", fmt.Errorf("%w: username must be 3..30 latin letters, digits or underscores and start with a letter", model.ErrInvalidArgument)
+	}
+
+	if _, ok := reservedUsernames[strings.ToLower(username)]; ok {
+		return "", fmt.Errorf("%w: username is reserved", model.ErrInvalidArgument)
+	}
+
+	return username, nil
+}
+
+// UsernameHistory прежние имена пользователя от последнего к первому.
+func (s *Service) UsernameHistory(ctx context.Context, userUUID uuid.UUID) ([]model.UsernameChange, error) {
+	if _, err := s.userStore.GetUserByUUID(ctx, userUUID); err != nil {
+		return nil, err
+	}
+
+	return s.userStore.ListUsernameHistory(ctx, userUUID)
+}
@@ -0,0 +1,57 @@
+package user_service
+
+import (
+	"errors"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeUsername(t *testing.T) {
+	tests := []struct {
+		name     string
+		username string
+		want     string
+		wantErr  bool
+	}{
+		{name: "plain", username: "alice", want: "alice"},
+		{name: "case preserved", username: "AliceSmith", want: "AliceSmith"},
+		{name: "digits and underscore", username: "user_42", want: "user_42"},
+		{name: "surrounding spaces", username: "  alice\n", want: "alice"},
+		{name: "leading at", username: "@alice", want: "alice"},
+		{name: "spaces before at", username: " @alice ", want: "alice"},
+		{name: "shortest", username: "abc", want: "abc"},
+		{name: "longest", username: "a" + strings.Repeat("b", 29), want: "a" + strings.Repeat("b", 29)},
+		{name: "empty", username: "", wantErr: true},
+		{name: "bare at", username: "@", wantErr: true},
+		{name: "double at", username: "@@alice", wantErr: true},
+		{name: "too short", username: "ab", wantErr: true},
+		{name: "too long", username: "a" + strings.Repeat("b", 30), wantErr: true},
+		{name: "starts with digit", username: "1alice", wantErr: true},
+		{name: "starts with underscore", username: "_alice", wantErr: true},
+		{name: "dash", username: "alice-smith", wantErr: true},
+		{name: "dot", username: "alice.smith", wantErr: true},
+		{name: "inner space", username: "alice smith", wantErr: true},
+		{name: "cyrillic", username: "алиса", wantErr: true},
+		{name: "reserved", username: "admin", wantErr: true},
+		{name: "reserved in other case", username: "Admin", wantErr: true},
+		{name: "reserved with at", username: "@Settings", wantErr: true},
+		{name: "reserved project name", username: "BaseProject", wantErr: true},
+		{name: "reserved word as prefix", username: "admin_alice", want: "admin_alice"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := normalizeUsername(tc.username)
+			if tc.wantErr {
+				if !errors.Is(err, model.ErrInvalidArgument) {
+					t.Errorf("normalizeUsername(%q) = (%q, %v), want ErrInvalidArgument", tc.username, got, err)
+				}
+				return
+			}
+			if err != nil || got != tc.want {
+				t.Errorf("normalizeUsername(%q) = (%q, %v), want %q", tc.username, got, err, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package user_service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/AdilBaidual/baseProject/internal/mail"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	tokenBytes  = 32
+	sendTimeout = 30 * time.Second
+)
+
+type mailData struct {
+	FirstName string
+	Link      string
+	ExpiresAt time.Time
+}
+
+func (s *Service) SendVerificationEmail(ctx context.Context, userUUID uuid.UUID) error {
+	user, err := s.userStore.GetUserByUUID(ctx, userUUID)
+	if err != nil {
+		return err
+	}
+
+	if user.EmailVerified() {
+		return fmt.Errorf("%w: email already verified", model.ErrInvalidArgument)
+	}
+
+	return s.sendVerification(ctx, user)
+}
+
+func (s *Service) VerifyEmail(ctx context.Context, token string) error {
+	userUUID, err := s.userStore.ConsumeUserToken(ctx, model.TokenPurposeEmailVerification, hashToken(token))
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return fmt.Errorf("%w: invalid or expired token", model.ErrInvalidArgument)
+		}
+		return err
+	}
+
+	return s.userStore.MarkEmailVerified(ctx, userUUID)
+}
+
+// RequestPasswordReset всегда завершается успешно и выполняет всю работу в фоне,
+// чтобы ни ответ, ни время ответа не выдавали, зарегистрирован ли email.
+// Одновременных отправок не больше PasswordResetSenders, а одному пользователю
+// письмо уходит не чаще раза в PasswordResetInterval.
+func (s *Service) RequestPasswordReset(ctx context.Context, email string) {
+	email = strings.ToLower(strings.TrimSpace(email))
+
+	select {
+	case s.resetSenders <- struct{}{}:
+	default:
+		s.logger.Warn("password reset request dropped: too many pending emails")
+		return
+	}
+
+	go func() {
+		defer func() { <-s.resetSenders }()
+
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), sendTimeout)
+		defer cancel()
+
+		if err := s.sendPasswordReset(ctx, email); err != nil {
+			s.logger.Error("failed to send password reset email", zap.Error(err))
+		}
+	}()
+}
+
+func (s *Service) sendPasswordReset(ctx context.Context, email string) error {
+	user, err := s.userStore.GetUserByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	last, err := s.userStore.GetLastUserTokenTime(ctx, user.UUID, model.TokenPurposePasswordReset)
+	if err != nil {
+		return err
+	}
+	if last != nil && time.Since(*last) < s.cfg.PasswordResetInterval {
+		return nil
+	}
+
+	return s.sendToken(ctx, user, model.TokenPurposePasswordReset, s.cfg.PasswordResetURL, s.cfg.PasswordResetTTL, mail.TemplatePasswordReset)
+}
+
+func (s *Service) ResetPassword(ctx context.Context, token, newPassword string) error {
+	hash, err := hashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+
+	userUUID, err := s.userStore.ConsumeUserToken(ctx, model.TokenPurposePasswordReset, hashToken(token))
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return fmt.Errorf("%w: invalid or expired token", model.ErrInvalidArgument)
+		}
+		return err
+	}
+
+	if err = s.userStore.UpdatePassword(ctx, userUUID, hash); err != nil {
+		return err
+	}
+
+	// Письмо со ссылкой пришло на этот email, значит владение адресом подтверждено.
+	return s.userStore.MarkEmailVerified(ctx, userUUID)
+}
+
+func (s *Service) sendVerification(ctx context.Context, user model.User) error {
+	return s.sendToken(ctx, user, model.TokenPurposeEmailVerification, s.cfg.VerificationURL, s.cfg.VerificationTTL, mail.TemplateVerifyEmail)
+}
+
+func (s *Service) sendToken(ctx context.Context, user model.User, purpose, baseURL string, ttl time.Duration, template string) error {
+	token, err := newToken()
+	if err != nil {
+		return err
+	}
+
+	expiresAt := time.Now().Add(ttl)
+	if err = s.userStore.CreateUserToken(ctx, user.UUID, purpose, hashToken(token), expiresAt); err != nil {
+		return err
+	}
+
+	link, err := withToken(baseURL, token)
+	if err != nil {
+		return err
+	}
+
+	msg, err := s.templates.Render(template, mailData{
+		FirstName: user.FirstName,
+		Link:      link,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return err
+	}
+	msg.To = []string{user.Email}
+
+	return s.mailer.Send(ctx, msg)
+}
+
+func newToken() (string, error) {
+	b := make([]byte, tokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("newToken - rand.Read - %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func withToken(baseURL, token string) (string, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return "", fmt.Errorf("withToken - url.Parse - %w", err)
+	}
+
+	q := u.Query()
+	q.Set("token", token)
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
@@ -0,0 +1,51 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/google/uuid"
+)
+
+// shadowBannedUsers пользователи в действующем теневом бане. Их посты и
+// комментарии исключаются из выдачи для всех, кроме них самих и модераторов.
+const shadowBannedUsers = `SELECT uuid FROM users
+	WHERE account_state = 'shadow_banned' AND (state_until IS NULL OR state_until > NOW())`
+
+// SetAccountState меняет состояние аккаунта. С revokeSessions все выданные
+// токены доступа перестают действовать, а API-ключи отзываются.
+func (s *Store) SetAccountState(ctx context.Context, userUUID uuid.UUID, restriction model.AccountRestriction, revokeSessions bool) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("SetAccountState - Begin - %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE users
+		SET account_state = $2, state_until = $3, state_reason = $4,
+		    state_changed_by = $5, state_changed_at = NOW(),
+		    sessions_revoked_at = CASE WHEN $6 THEN NOW() ELSE sessions_revoked_at END
+		WHERE uuid = $1 AND deleted_at IS NULL`,
+		userUUID, restriction.State, restriction.Until, restriction.Reason, restriction.ActorUUID, revokeSessions,
+	)
+	if err != nil {
+		return fmt.Errorf("SetAccountState - update user - %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return model.ErrNotFound
+	}
+
+	if revokeSessions {
+		_, err = tx.Exec(ctx, `UPDATE api_keys SET revoked_at = NOW() WHERE user_uuid = $1 AND revoked_at IS NULL`, userUUID)
+		if err != nil {
+			return fmt.Errorf("SetAccountState - revoke api keys - %w", err)
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("SetAccountState - Commit - %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const apiKeyColumns = `id, user_uuid, name, prefix, key_hash, scopes, created_at, expires_at, last_used_at, revoked_at`
+
+func (s *Store) CreateAPIKey(ctx context.Context, key model.APIKey) (model.APIKey, error) {
+	rows, err := s.db.Query(ctx, `
+		INSERT INTO api_keys (user_uuid, name, prefix, key_hash, scopes, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING `+apiKeyColumns,
+		key.UserUUID, key.Name, key.Prefix, key.KeyHash, key.Scopes, key.ExpiresAt,
+	)
+	if err != nil {
+		return model.APIKey{}, fmt.Errorf("CreateAPIKey - Query - %w", err)
+	}
+
+	created, err := pgx.CollectExactlyOneRow(rows, scanAPIKey)
+	if err != nil {
+		if isPgError(err, uniqueViolation) {
+			return model.APIKey{}, model.ErrAlreadyExists
+		}
+		return model.APIKey{}, fmt.Errorf("CreateAPIKey - CollectExactlyOneRow - %w", err)
+	}
+
+	return created, nil
+}
+
+func (s *Store) GetAPIKeyByPrefix(ctx context.Context, prefix string) (model.APIKey, error) {
+	rows, err := s.db.Query(ctx, `SELECT `+apiKeyColumns+` FROM api_keys WHERE prefix = $1`, prefix)
+	if err != nil {
+		return model.APIKey{}, fmt.Errorf("GetAPIKeyByPrefix - Query - %w", err)
+	}
+
+	key, err := pgx.CollectExactlyOneRow(rows, scanAPIKey)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.APIKey{}, model.ErrNotFound
+		}
+		return model.APIKey{}, fmt.Errorf("GetAPIKeyByPrefix - CollectExactlyOneRow - %w", err)
+	}
+
+	return key, nil
+}
+
+func (s *Store) ListAPIKeys(ctx context.Context, userUUID uuid.UUID) ([]model.APIKey, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT `+apiKeyColumns+`
+		FROM api_keys
+		WHERE user_uuid = $1
+		ORDER BY created_at DESC`,
+		userUUID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ListAPIKeys - Query - %w", err)
+	}
+
+	keys, err := pgx.CollectRows(rows, scanAPIKey)
+	if err != nil {
+		return nil, fmt.Errorf("ListAPIKeys - CollectRows - %w", err)
+	}
+
+	return keys, nil
+}
+
+func (s *Store) RevokeAPIKey(ctx context.Context, userUUID, id uuid.UUID) error {
+	tag, err := s.db.Exec(ctx, `
+		UPDATE api_keys
+		SET revoked_at = NOW()
+		WHERE id = $1 AND user_uuid = $2 AND revoked_at IS NULL`,
+		id, userUUID,
+	)
+	if err != nil {
+		return fmt.Errorf("RevokeAPIKey - Exec - %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return model.ErrNotFound
+	}
+
+	return nil
+}
+
+// TouchAPIKey обновляет время последнего использования не чаще раза в минуту,
+// чтобы не писать в базу на каждый запрос.
+func (s *Store) TouchAPIKey(ctx context.Context, id uuid.UUID) error {
+	_, err := s.db.Exec(ctx, `
+		UPDATE api_keys
+		SET last_used_at = NOW()
+		WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute')`,
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("TouchAPIKey - Exec - %w", err)
+	}
+
+	return nil
+}
+
+func scanAPIKey(row pgx.CollectableRow) (model.APIKey, error) {
+	var key model.APIKey
+
+	err := row.Scan(
+		&key.ID,
+		&key.UserUUID,
+		&key.Name,
+		&key.Prefix,
+		&key.KeyHash,
+		&key.Scopes,
+		&key.CreatedAt,
+		&key.ExpiresAt,
+		&key.LastUsedAt,
+		&key.RevokedAt,
+	)
+
+	return key, err
+}
@@ -0,0 +1,360 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/jackc/pgx/v5"
+	"time"
+)
+
+const attachmentColumns = `a.id, a.post_id, a.sha256, a.filename, b.size, b.mime_type,
+	COALESCE(a.uploaded_by, uuid_nil()), a.created_at,
+	a.image_state, a.processing_attempts, a.width, a.height, a.blurhash`
+
+// TouchBlob отмечает блоб использованным и сообщает, есть ли он уже в хранилище.
+// Отмеченный блоб сборщик мусора не тронет ещё как минимум grace-период.
+func (s *Store) TouchBlob(ctx context.Context, sha256 string) (bool, error) {
+	tag, err := s.db.Exec(ctx, `UPDATE blobs SET last_used_at = NOW() WHERE sha256 = $1`, sha256)
+	if err != nil {
+		return false, fmt.Errorf("TouchBlob - Exec - %w", err)
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
+
+// CreateAttachment записывает блоб, если его ещё нет, и вложение к посту.
+// Повторная загрузка того же файла к тому же посту возвращает существующее вложение.
+func (s *Store) CreateAttachment(ctx context.Context, attachment model.Attachment) (model.Attachment, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return model.Attachment{}, fmt.Errorf("CreateAttachment - Begin - %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO blobs (sha256, size, mime_type)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (sha256) DO UPDATE SET last_used_at = NOW()`,
+		attachment.SHA256, attachment.Size, attachment.MimeType,
+	)
+	if err != nil {
+		return model.Attachment{}, fmt.Errorf("CreateAttachment - insert blob - %w", err)
+	}
+
+	rows, err := tx.Query(ctx, `
+		WITH a AS (
+			INSERT INTO attachments (post_id, sha256, filename, uploaded_by, image_state)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (post_id, sha256) DO UPDATE SET post_id = EXCLUDED.post_id
+			RETURNING *
+		)
+		SELECT `+attachmentColumns+`
+		FROM a JOIN blobs b ON b.sha256 = a.sha256`,
+		attachment.PostID, attachment.SHA256, attachment.Filename, attachment.UploadedBy, attachment.ImageState,
+	)
+	if err != nil {
+		return model.Attachment{}, fmt.Errorf("CreateAttachment - Query - %w", err)
+	}
+
+	created, err := pgx.CollectExactlyOneRow(rows, scanAttachment)
+	if err != nil {
+		if isPgError(err, foreignKeyViolation) {
+			return model.Attachment{}, model.ErrNotFound
+		}
+		return model.Attachment{}, fmt.Errorf("CreateAttachment - CollectExactlyOneRow - %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return model.Attachment{}, fmt.Errorf("CreateAttachment - Commit - %w", err)
+	}
+
+	return created, nil
+}
+
+func (s *Store) GetAttachment(ctx context.Context, id int64) (model.Attachment, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT `+attachmentColumns+`
+		FROM attachments a JOIN blobs b ON b.sha256 = a.sha256
+		WHERE a.id = $1`,
+		id,
+	)
+	if err != nil {
+		return model.Attachment{}, fmt.Errorf("GetAttachment - Query - %w", err)
+	}
+
+	attachment, err := pgx.CollectExactlyOneRow(rows, scanAttachment)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.Attachment{}, model.ErrNotFound
+		}
+		return model.Attachment{}, fmt.Errorf("GetAttachment - CollectExactlyOneRow - %w", err)
+	}
+
+	return attachment, nil
+}
+
+// ListAttachments вложения поста в порядке загрузки.
+func (s *Store) ListAttachments(ctx context.Context, postID int64) ([]model.Attachment, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT `+attachmentColumns+`
+		FROM attachments a JOIN blobs b ON b.sha256 = a.sha256
+		WHERE a.post_id = $1
+		ORDER BY a.id`,
+		postID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ListAttachments - Query - %w", err)
+	}
+
+	attachments, err := pgx.CollectRows(rows, scanAttachment)
+	if err != nil {
+		return nil, fmt.Errorf("ListAttachments - CollectRows - %w", err)
+	}
+
+	return attachments, nil
+}
+
+// GetAttachmentVariants возвращает варианты изображений для набора вложений.
+func (s *Store) GetAttachmentVariants(ctx context.Context, attachmentIDs []int64) (map[int64][]model.AttachmentVariant, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT v.attachment_id, v.name, v.sha256, b.size, b.mime_type, v.width, v.height
+		FROM attachment_variants v
+		JOIN blobs b ON b.sha256 = v.sha256
+		WHERE v.attachment_id = ANY($1)
+		ORDER BY v.width, v.name`,
+		attachmentIDs,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("GetAttachmentVariants - Query - %w", err)
+	}
+	defer rows.Close()
+
+	variants := make(map[int64][]model.AttachmentVariant, len(attachmentIDs))
+	for rows.Next() {
+		var (
+			attachmentID int64
+			variant      model.AttachmentVariant
+		)
+		err = rows.Scan(&attachmentID, &variant.Name, &variant.SHA256, &variant.Size, &variant.MimeType, &variant.Width, &variant.Height)
+		if err != nil {
+			return nil, fmt.Errorf("GetAttachmentVariants - Scan - %w", err)
+		}
+
+		variants[attachmentID] = append(variants[attachmentID], variant)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("GetAttachmentVariants - rows.Err - %w", err)
+	}
+
+	return variants, nil
+}
+
+// ClaimImages забирает в обработку не больше batch изображений из очереди,
+// включая те, обработка которых началась раньше staleBefore и не завершилась:
+// экземпляр, который их взял, скорее всего упал. Счётчик попыток
+// увеличивается при каждом захвате.
+func (s *Store) ClaimImages(ctx context.Context, staleBefore time.Time, batch int) ([]model.Attachment, error) {
+	rows, err := s.db.Query(ctx, `
+		WITH a AS (
+			UPDATE attachments
+			SET image_state = 'processing', processing_started_at = NOW(),
+			    processing_attempts = processing_attempts + 1
+			WHERE id IN (
+				SELECT id FROM attachments
+				WHERE image_state = 'pending'
+				   OR (image_state = 'processing' AND processing_started_at < $1)
+				ORDER BY id
+				LIMIT $2
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING *
+		)
+		SELECT `+attachmentColumns+`
+		FROM a JOIN blobs b ON b.sha256 = a.sha256
+		ORDER BY a.id`,
+		staleBefore, batch,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ClaimImages - Query - %w", err)
+	}
+
+	attachments, err := pgx.CollectRows(rows, scanAttachment)
+	if err != nil {
+		return nil, fmt.Errorf("ClaimImages - CollectRows - %w", err)
+	}
+
+	return attachments, nil
+}
+
+// CompleteImage сохраняет результат обработки: размеры, BlurHash и варианты.
+// Блобы вариантов должны быть уже записаны в хранилище. Варианты, которых нет
+// в attachment.Variants, удаляются. Если вложение успели удалить, возвращается
+// model.ErrNotFound.
+func (s *Store) CompleteImage(ctx context.Context, attachment model.Attachment) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("CompleteImage - Begin - %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE attachments
+		SET image_state = 'ready', width = $2, height = $3, blurhash = $4,
+		    processing_error = '', processed_at = NOW()
+		WHERE id = $1`,
+		attachment.ID, attachment.Width, attachment.Height, attachment.Blurhash,
+	)
+	if err != nil {
+		return fmt.Errorf("CompleteImage - update attachment - %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return model.ErrNotFound
+	}
+
+	names := make([]string, 0, len(attachment.Variants))
+	for _, v := range attachment.Variants {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO blobs (sha256, size, mime_type)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (sha256) DO UPDATE SET last_used_at = NOW()`,
+			v.SHA256, v.Size, v.MimeType,
+		)
+		if err != nil {
+			return fmt.Errorf("CompleteImage - insert blob - %w", err)
+		}
+
+		_, err = tx.Exec(ctx, `
+			INSERT INTO attachment_variants (attachment_id, name, sha256, width, height)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (attachment_id, name) DO UPDATE
+			SET sha256 = EXCLUDED.sha256, width = EXCLUDED.width, height = EXCLUDED.height`,
+			attachment.ID, v.Name, v.SHA256, v.Width, v.Height,
+		)
+		if err != nil {
+			return fmt.Errorf("CompleteImage - upsert variant - %w", err)
+		}
+
+		names = append(names, v.Name)
+	}
+
+	_, err = tx.Exec(ctx, `
+		DELETE FROM attachment_variants
+		WHERE attachment_id = $1 AND NOT name = ANY($2)`,
+		attachment.ID, names,
+	)
+	if err != nil {
+		return fmt.Errorf("CompleteImage - delete variants - %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("CompleteImage - Commit - %w", err)
+	}
+
+	return nil
+}
+
+// FailImage записывает причину ошибки обработки. При retry изображение
+// возвращается в очередь, иначе остаётся в состоянии failed.
+func (s *Store) FailImage(ctx context.Context, id int64, reason string, retry bool) error {
+	_, err := s.db.Exec(ctx, `
+		UPDATE attachments
+		SET image_state = CASE WHEN $3 THEN 'pending' ELSE 'failed' END,
+		    processing_error = $2,
+		    processed_at = CASE WHEN $3 THEN processed_at ELSE NOW() END
+		WHERE id = $1`,
+		id, reason, retry,
+	)
+	if err != nil {
+		return fmt.Errorf("FailImage - Exec - %w", err)
+	}
+
+	return nil
+}
+
+// DeleteAttachment удаляет только запись о вложении, блоб подберёт сборщик мусора.
+func (s *Store) DeleteAttachment(ctx context.Context, id int64) error {
+	tag, err := s.db.Exec(ctx, `DELETE FROM attachments WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("DeleteAttachment - Exec - %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return model.ErrNotFound
+	}
+
+	return nil
+}
+
+// PurgeUnusedBlobs удаляет не больше batch блобов, которые не использовались
+// с before и на которые не ссылается ни одно вложение или вариант изображения.
+// remove удаляет содержимое из хранилища, пока строки блобов заблокированы:
+// параллельная загрузка того же файла дождётся конца транзакции и запишет
+// содержимое заново.
+// Если remove вернул ошибку, строки остаются и удаление повторится в следующий раз.
+func (s *Store) PurgeUnusedBlobs(ctx context.Context, before time.Time, batch int, remove func(ctx context.Context, sha256 string) error) (int, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("PurgeUnusedBlobs - Begin - %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	rows, err := tx.Query(ctx, `
+		SELECT b.sha256 FROM blobs b
+		WHERE b.last_used_at < $1
+		  AND NOT EXISTS (SELECT 1 FROM attachments a WHERE a.sha256 = b.sha256)
+		  AND NOT EXISTS (SELECT 1 FROM attachment_variants v WHERE v.sha256 = b.sha256)
+		ORDER BY b.last_used_at
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED`,
+		before, batch,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("PurgeUnusedBlobs - select - %w", err)
+	}
+	hashes, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return 0, fmt.Errorf("PurgeUnusedBlobs - collect - %w", err)
+	}
+
+	removed := make([]string, 0, len(hashes))
+	for _, sha256 := range hashes {
+		if err = remove(ctx, sha256); err != nil {
+			break
+		}
+		removed = append(removed, sha256)
+	}
+
+	if _, execErr := tx.Exec(ctx, `DELETE FROM blobs WHERE sha256 = ANY($1)`, removed); execErr != nil {
+		return 0, fmt.Errorf("PurgeUnusedBlobs - delete - %w", execErr)
+	}
+
+	if commitErr := tx.Commit(ctx); commitErr != nil {
+		return 0, fmt.Errorf("PurgeUnusedBlobs - Commit - %w", commitErr)
+	}
+
+	return len(removed), err
+}
+
+func scanAttachment(row pgx.CollectableRow) (model.Attachment, error) {
+	var attachment model.Attachment
+
+	err := row.Scan(
+		&attachment.ID,
+		&attachment.PostID,
+		&attachment.SHA256,
+		&attachment.Filename,
+		&attachment.Size,
+		&attachment.MimeType,
+		&attachment.UploadedBy,
+		&attachment.CreatedAt,
+		&attachment.ImageState,
+		&attachment.ProcessingAttempts,
+		&attachment.Width,
+		&attachment.Height,
+		&attachment.Blurhash,
+	)
+
+	return attachment, err
+}
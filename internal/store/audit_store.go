@@ -0,0 +1,53 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/google/uuid"
+	"time"
+)
+
+func (s *Store) CreateAuditEvent(ctx context.Context, event model.AuditEvent) error {
+	details := event.Details
+	if details == nil {
+		details = map[string]any{}
+	}
+
+	_, err := s.db.Exec(ctx, `
+		INSERT INTO audit_events (event, user_uuid, actor_uuid, ip, details)
+		VALUES ($1, $2, $3, $4, $5)`,
+		event.Event, nullUUID(event.UserUUID), nullUUID(event.ActorUUID), event.IP, details,
+	)
+	if err != nil {
+		return fmt.Errorf("CreateAuditEvent - Exec - %w", err)
+	}
+
+	return nil
+}
+
+// DeleteAuditEvents удаляет не больше batch событий, записанных до cutoff.
+func (s *Store) DeleteAuditEvents(ctx context.Context, cutoff time.Time, batch int) (int64, error) {
+	tag, err := s.db.Exec(ctx, `
+		DELETE FROM audit_events
+		WHERE id IN (
+			SELECT id FROM audit_events
+			WHERE created_at < $1
+			ORDER BY created_at
+			LIMIT $2
+		)`,
+		cutoff, batch,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("DeleteAuditEvents - Exec - %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+func nullUUID(id uuid.UUID) *uuid.UUID {
+	if id == uuid.Nil {
+		return nil
+	}
+	return &id
+}
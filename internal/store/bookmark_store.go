@@ -0,0 +1,270 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"time"
+)
+
+const bookmarkColumns = `user_uuid, post_id, COALESCE(collection_id, 0), created_at`
+
+const bookmarkCollectionColumns = `c.id, c.user_uuid, c.name,
+	(SELECT COUNT(*) FROM bookmarks b WHERE b.collection_id = c.id), c.created_at`
+
+// AddBookmark добавляет пост в закладки. Если пост уже в закладках, он
+// переносится в коллекцию collectionID, время добавления не меняется.
+// Отсутствующие пост и коллекция, как и чужая коллекция, нарушают внешние
+// ключи и возвращаются как ErrNotFound.
+func (s *Store) AddBookmark(ctx context.Context, bookmark model.Bookmark) (model.Bookmark, error) {
+	rows, err := s.db.Query(ctx, `
+		INSERT INTO bookmarks (user_uuid, post_id, collection_id)
+		VALUES ($1, $2, NULLIF($3, 0))
+		ON CONFLICT (user_uuid, post_id) DO UPDATE SET collection_id = EXCLUDED.collection_id
+		RETURNING `+bookmarkColumns,
+		bookmark.UserUUID, bookmark.PostID, bookmark.CollectionID,
+	)
+	if err != nil {
+		return model.Bookmark{}, fmt.Errorf("AddBookmark - Query - %w", err)
+	}
+
+	added, err := pgx.CollectExactlyOneRow(rows, scanBookmark)
+	if err != nil {
+		if isPgError(err, foreignKeyViolation) {
+			return model.Bookmark{}, model.ErrNotFound
+		}
+		return model.Bookmark{}, fmt.Errorf("AddBookmark - CollectExactlyOneRow - %w", err)
+	}
+
+	return added, nil
+}
+
+// MoveBookmark переносит закладку в коллекцию collectionID, 0 — из коллекции
+// в общий список. Чужая коллекция, как и отсутствующая, даёт ErrNotFound.
+func (s *Store) MoveBookmark(ctx context.Context, userUUID uuid.UUID, postID, collectionID int64) (model.Bookmark, error) {
+	rows, err := s.db.Query(ctx, `
+		UPDATE bookmarks
+		SET collection_id = NULLIF($3, 0)
+		WHERE user_uuid = $1 AND post_id = $2
+		RETURNING `+bookmarkColumns,
+		userUUID, postID, collectionID,
+	)
+	if err != nil {
+		return model.Bookmark{}, fmt.Errorf("MoveBookmark - Query - %w", err)
+	}
+
+	moved, err := pgx.CollectExactlyOneRow(rows, scanBookmark)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) || isPgError(err, foreignKeyViolation) {
+			return model.Bookmark{}, model.ErrNotFound
+		}
+		return model.Bookmark{}, fmt.Errorf("MoveBookmark - CollectExactlyOneRow - %w", err)
+	}
+
+	return moved, nil
+}
+
+func (s *Store) RemoveBookmark(ctx context.Context, userUUID uuid.UUID, postID int64) (bool, error) {
+	tag, err := s.db.Exec(ctx, `DELETE FROM bookmarks WHERE user_uuid = $1 AND post_id = $2`, userUUID, postID)
+	if err != nil {
+		return false, fmt.Errorf("RemoveBookmark - Exec - %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// GetBookmarkTimes время добавления в закладки пользователя для постов из
+// postIDs, которые в них есть.
+func (s *Store) GetBookmarkTimes(ctx context.Context, userUUID uuid.UUID, postIDs []int64) (map[int64]time.Time, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT post_id, created_at
+		FROM bookmarks
+		WHERE user_uuid = $1 AND post_id = ANY($2)`,
+		userUUID, postIDs,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("GetBookmarkTimes - Query - %w", err)
+	}
+	defer rows.Close()
+
+	times := make(map[int64]time.Time, len(postIDs))
+	for rows.Next() {
+		var (
+			postID    int64
+			createdAt time.Time
+		)
+		if err = rows.Scan(&postID, &createdAt); err != nil {
+			return nil, fmt.Errorf("GetBookmarkTimes - Scan - %w", err)
+		}
+
+		times[postID] = createdAt
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("GetBookmarkTimes - rows.Err - %w", err)
+	}
+
+	return times, nil
+}
+
+// ListBookmarkedPosts посты из закладок пользователя от новых закладок к
+// старым, collectionID = 0 — из всех коллекций. Посты, которые читатель
+// больше не может видеть, пропускаются, но закладки на них остаются.
+func (s *Store) ListBookmarkedPosts(ctx context.Context, userUUID uuid.UUID, collectionID int64, before *time.Time, beforeID int64, limit int) ([]model.Post, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT `+postColumns+`, b.created_at
+		FROM bookmarks b
+		JOIN posts p ON p.id = b.post_id
+		WHERE b.user_uuid = $1
+		  AND ($2 = 0 OR b.collection_id = $2)
+		  AND ($3::timestamptz IS NULL OR (b.created_at, b.post_id) < ($3, $4))
+		  AND p.deleted_at IS NULL AND p.hidden_at IS NULL
+		  AND (p.author_uuid = $1 OR p.status = 'published' AND p.author_uuid NOT IN (`+shadowBannedUsers+`))
+		ORDER BY b.created_at DESC, b.post_id DESC
+		LIMIT $5`,
+		userUUID, collectionID, before, beforeID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ListBookmarkedPosts - Query - %w", err)
+	}
+
+	posts, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.Post, error) {
+		var post model.Post
+		err := row.Scan(append(postFields(&post), &post.BookmarkedAt)...)
+		return post, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ListBookmarkedPosts - CollectRows - %w", err)
+	}
+
+	return posts, nil
+}
+
+func scanBookmark(row pgx.CollectableRow) (model.Bookmark, error) {
+	var b model.Bookmark
+	err := row.Scan(&b.UserUUID, &b.PostID, &b.CollectionID, &b.CreatedAt)
+	return b, err
+}
+
+func (s *Store) CreateBookmarkCollection(ctx context.Context, collection model.BookmarkCollection) (model.BookmarkCollection, error) {
+	rows, err := s.db.Query(ctx, `
+		INSERT INTO bookmark_collections AS c (user_uuid, name)
+		VALUES ($1, $2)
+		RETURNING `+bookmarkCollectionColumns,
+		collection.UserUUID, collection.Name,
+	)
+	if err != nil {
+		return model.BookmarkCollection{}, fmt.Errorf("CreateBookmarkCollection - Query - %w", err)
+	}
+
+	created, err := pgx.CollectExactlyOneRow(rows, scanBookmarkCollection)
+	if err != nil {
+		if isPgError(err, uniqueViolation) {
+			return model.BookmarkCollection{}, model.ErrAlreadyExists
+		}
+		return model.BookmarkCollection{}, fmt.Errorf("CreateBookmarkCollection - CollectExactlyOneRow - %w", err)
+	}
+
+	return created, nil
+}
+
+func (s *Store) RenameBookmarkCollection(ctx context.Context, userUUID uuid.UUID, id int64, name string) (model.BookmarkCollection, error) {
+	rows, err := s.db.Query(ctx, `
+		UPDATE bookmark_collections AS c
+		SET name = $3
+		WHERE c.id = $2 AND c.user_uuid = $1
+		RETURNING `+bookmarkCollectionColumns,
+		userUUID, id, name,
+	)
+	if err != nil {
+		return model.BookmarkCollection{}, fmt.Errorf("RenameBookmarkCollection - Query - %w", err)
+	}
+
+	renamed, err := pgx.CollectExactlyOneRow(rows, scanBookmarkCollection)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.BookmarkCollection{}, model.ErrNotFound
+		}
+		if isPgError(err, uniqueViolation) {
+			return model.BookmarkCollection{}, model.ErrAlreadyExists
+		}
+		return model.BookmarkCollection{}, fmt.Errorf("RenameBookmarkCollection - CollectExactlyOneRow - %w", err)
+	}
+
+	return renamed, nil
+}
+
+// DeleteBookmarkCollection удаляет коллекцию, её закладки переходят в общий список.
+func (s *Store) DeleteBookmarkCollection(ctx context.Context, userUUID uuid.UUID, id int64) error {
+	tag, err := s.db.Exec(ctx, `DELETE FROM bookmark_collections WHERE id = $2 AND user_uuid = $1`, userUUID, id)
+	if err != nil {
+		return fmt.Errorf("DeleteBookmarkCollection - Exec - %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return model.ErrNotFound
+	}
+
+	return nil
+}
+
+func (s *Store) GetBookmarkCollection(ctx context.Context, userUUID uuid.UUID, id int64) (model.BookmarkCollection, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT `+bookmarkCollectionColumns+`
+		FROM bookmark_collections c
+		WHERE c.id = $2 AND c.user_uuid = $1`,
+		userUUID, id,
+	)
+	if err != nil {
+		return model.BookmarkCollection{}, fmt.Errorf("GetBookmarkCollection - Query - %w", err)
+	}
+
+	collection, err := pgx.CollectExactlyOneRow(rows, scanBookmarkCollection)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.BookmarkCollection{}, model.ErrNotFound
+		}
+		return model.BookmarkCollection{}, fmt.Errorf("GetBookmarkCollection - CollectExactlyOneRow - %w", err)
+	}
+
+	return collection, nil
+}
+
+// ListBookmarkCollections коллекции пользователя по имени.
+func (s *Store) ListBookmarkCollections(ctx context.Context, userUUID uuid.UUID) ([]model.BookmarkCollection, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT `+bookmarkCollectionColumns+`
+		FROM bookmark_collections c
+		WHERE c.user_uuid = $1
+		ORDER BY LOWER(c.name)`,
+		userUUID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ListBookmarkCollections - Query - %w", err)
+	}
+
+	collections, err := pgx.CollectRows(rows, scanBookmarkCollection)
+	if err != nil {
+		return nil, fmt.Errorf("ListBookmarkCollections - CollectRows - %w", err)
+	}
+
+	return collections, nil
+}
+
+func (s *Store) CountBookmarkCollections(ctx context.Context, userUUID uuid.UUID) (int, error) {
+	var count int
+	err := s.db.QueryRow(ctx, `SELECT COUNT(*) FROM bookmark_collections WHERE user_uuid = $1`, userUUID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("CountBookmarkCollections - QueryRow - %w", err)
+	}
+
+	return count, nil
+}
+
+func scanBookmarkCollection(row pgx.CollectableRow) (model.BookmarkCollection, error) {
+	var c model.BookmarkCollection
+	err := row.Scan(&c.ID, &c.UserUUID, &c.Name, &c.BookmarksCount, &c.CreatedAt)
+	return c, err
+}
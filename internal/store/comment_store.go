@@ -0,0 +1,159 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const commentColumns = `id, post_id, parent_id, COALESCE(author_uuid, uuid_nil()), content, has_sub_comments, created_at,
+	deleted_at, COALESCE(deleted_by, uuid_nil()), hidden_at,
+	COALESCE(author_uuid IN (` + shadowBannedUsers + `), FALSE),
+	content_html, content_html_version`
+
+// CreateComment сохраняет комментарий и отмечает у родителя наличие ответов.
+// Родитель должен принадлежать тому же посту. Комментарий, задержанный правилом
+// контент-политики (held != nil), сохраняется скрытым и попадает в очередь модерации.
+func (s *Store) CreateComment(ctx context.Context, comment model.Comment, held *model.PolicyDecision) (model.Comment, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return model.Comment{}, fmt.Errorf("CreateComment - Begin - %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	if comment.ParentID != 0 {
+		tag, err := tx.Exec(ctx, `
+			UPDATE comments
+			SET has_sub_comments = TRUE
+			WHERE id = $1 AND post_id = $2 AND deleted_at IS NULL`,
+			comment.ParentID, comment.PostID,
+		)
+		if err != nil {
+			return model.Comment{}, fmt.Errorf("CreateComment - update parent - %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return model.Comment{}, fmt.Errorf("%w: parent comment", model.ErrNotFound)
+		}
+	}
+
+	rows, err := tx.Query(ctx, `
+		INSERT INTO comments (post_id, parent_id, author_uuid, content, hidden_at, content_html, content_html_version)
+		VALUES ($1, $2, $3, $4, CASE WHEN $5 THEN NOW() END, $6, $7)
+		RETURNING `+commentColumns,
+		comment.PostID, comment.ParentID, comment.AuthorUUID, comment.Content, held != nil,
+		comment.ContentHTML, comment.ContentHTMLVersion,
+	)
+	if err != nil {
+		return model.Comment{}, fmt.Errorf("CreateComment - Query - %w", err)
+	}
+
+	created, err := pgx.CollectExactlyOneRow(rows, scanComment)
+	if err != nil {
+		return model.Comment{}, fmt.Errorf("CreateComment - CollectExactlyOneRow - %w", err)
+	}
+
+	if err = insertCommentMentions(ctx, tx, created.ID, comment.Mentions); err != nil {
+		return model.Comment{}, err
+	}
+
+	if held != nil {
+		target := model.ModerationTarget{Type: model.ReactionTargetComment, ID: created.ID}
+		if err = insertPolicyReport(ctx, tx, target, held.RuleID); err != nil {
+			return model.Comment{}, err
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return model.Comment{}, fmt.Errorf("CreateComment - Commit - %w", err)
+	}
+
+	created.Mentions = comment.Mentions
+
+	return created, nil
+}
+
+// SaveCommentContentHTML сохраняет HTML, перестроенный новой версией рендерера.
+func (s *Store) SaveCommentContentHTML(ctx context.Context, comment model.Comment) error {
+	_, err := s.db.Exec(ctx, `
+		UPDATE comments
+		SET content_html = $2, content_html_version = $3
+		WHERE id = $1 AND content_html_version < $3`,
+		comment.ID, comment.ContentHTML, comment.ContentHTMLVersion,
+	)
+	if err != nil {
+		return fmt.Errorf("SaveCommentContentHTML - Exec - %w", err)
+	}
+
+	return nil
+}
+
+func (s *Store) GetComment(ctx context.Context, id int64) (model.Comment, error) {
+	rows, err := s.db.Query(ctx, `SELECT `+commentColumns+` FROM comments WHERE id = $1 AND deleted_at IS NULL`, id)
+	if err != nil {
+		return model.Comment{}, fmt.Errorf("GetComment - Query - %w", err)
+	}
+
+	comment, err := pgx.CollectExactlyOneRow(rows, scanComment)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.Comment{}, model.ErrNotFound
+		}
+		return model.Comment{}, fmt.Errorf("GetComment - CollectExactlyOneRow - %w", err)
+	}
+
+	return comment, nil
+}
+
+// ListComments возвращает ответы на parentID (0 — комментарии верхнего уровня)
+// в порядке создания. Удалённые комментарии, а без includeHidden и скрытые
+// модерацией, попадают в выборку, только если у них есть ответы, чтобы ветка
+// не обрывалась. Комментарии авторов в теневом бане без includeHidden
+// выбираются так же, как скрытые, если viewer не их автор.
+func (s *Store) ListComments(ctx context.Context, postID, parentID, afterID int64, limit int, viewer uuid.UUID, includeHidden bool) ([]model.Comment, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT `+commentColumns+`
+		FROM comments
+		WHERE post_id = $1 AND parent_id = $2 AND id > $3
+		  AND ((deleted_at IS NULL AND ($5 OR (hidden_at IS NULL
+		        AND (author_uuid IS NULL OR author_uuid = $6 OR author_uuid NOT IN (`+shadowBannedUsers+`)))))
+		    OR has_sub_comments)
+		ORDER BY id
+		LIMIT $4`,
+		postID, parentID, afterID, limit, includeHidden, viewer,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ListComments - Query - %w", err)
+	}
+
+	comments, err := pgx.CollectRows(rows, scanComment)
+	if err != nil {
+		return nil, fmt.Errorf("ListComments - CollectRows - %w", err)
+	}
+
+	return comments, nil
+}
+
+func scanComment(row pgx.CollectableRow) (model.Comment, error) {
+	var comment model.Comment
+
+	err := row.Scan(
+		&comment.ID,
+		&comment.PostID,
+		&comment.ParentID,
+		&comment.AuthorUUID,
+		&comment.Content,
+		&comment.HasSubComments,
+		&comment.CreatedAt,
+		&comment.DeletedAt,
+		&comment.DeletedBy,
+		&comment.HiddenAt,
+		&comment.AuthorShadowBanned,
+		&comment.ContentHTML,
+		&comment.ContentHTMLVersion,
+	)
+
+	return comment, err
+}
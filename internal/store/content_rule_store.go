@@ -0,0 +1,167 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const contentRuleColumns = `id, name, description, expression, action, priority, enabled,
+	COALESCE(created_by, uuid_nil()), created_at, updated_at`
+
+// ListContentRules возвращает правила в порядке проверки.
+func (s *Store) ListContentRules(ctx context.Context, enabledOnly bool) ([]model.ContentRule, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT `+contentRuleColumns+`
+		FROM content_rules
+		WHERE enabled OR NOT $1
+		ORDER BY priority, id`,
+		enabledOnly,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ListContentRules - Query - %w", err)
+	}
+
+	rules, err := pgx.CollectRows(rows, scanContentRule)
+	if err != nil {
+		return nil, fmt.Errorf("ListContentRules - CollectRows - %w", err)
+	}
+
+	return rules, nil
+}
+
+func (s *Store) CreateContentRule(ctx context.Context, rule model.ContentRule) (model.ContentRule, error) {
+	rows, err := s.db.Query(ctx, `
+		INSERT INTO content_rules (name, description, expression, action, priority, enabled, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING `+contentRuleColumns,
+		rule.Name, rule.Description, rule.Expression, rule.Action, rule.Priority, rule.Enabled, rule.CreatedBy,
+	)
+	if err != nil {
+		return model.ContentRule{}, fmt.Errorf("CreateContentRule - Query - %w", err)
+	}
+
+	created, err := pgx.CollectExactlyOneRow(rows, scanContentRule)
+	if err != nil {
+		if isPgError(err, uniqueViolation) {
+			return model.ContentRule{}, model.ErrAlreadyExists
+		}
+		return model.ContentRule{}, fmt.Errorf("CreateContentRule - CollectExactlyOneRow - %w", err)
+	}
+
+	return created, nil
+}
+
+func (s *Store) UpdateContentRule(ctx context.Context, rule model.ContentRule) (model.ContentRule, error) {
+	rows, err := s.db.Query(ctx, `
+		UPDATE content_rules
+		SET name = $2, description = $3, expression = $4, action = $5, priority = $6, enabled = $7,
+		    updated_at = NOW()
+		WHERE id = $1
+		RETURNING `+contentRuleColumns,
+		rule.ID, rule.Name, rule.Description, rule.Expression, rule.Action, rule.Priority, rule.Enabled,
+	)
+	if err != nil {
+		return model.ContentRule{}, fmt.Errorf("UpdateContentRule - Query - %w", err)
+	}
+
+	updated, err := pgx.CollectExactlyOneRow(rows, scanContentRule)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.ContentRule{}, model.ErrNotFound
+		}
+		if isPgError(err, uniqueViolation) {
+			return model.ContentRule{}, model.ErrAlreadyExists
+		}
+		return model.ContentRule{}, fmt.Errorf("UpdateContentRule - CollectExactlyOneRow - %w", err)
+	}
+
+	return updated, nil
+}
+
+func (s *Store) DeleteContentRule(ctx context.Context, id int64) error {
+	tag, err := s.db.Exec(ctx, `DELETE FROM content_rules WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("DeleteContentRule - Exec - %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return model.ErrNotFound
+	}
+
+	return nil
+}
+
+// GetAuthorActivity считает публикации автора за последний час и сутки
+// и ссылки в них за последний час. Удалённое тоже учитывается.
+func (s *Store) GetAuthorActivity(ctx context.Context, authorUUID uuid.UUID) (model.AuthorActivity, error) {
+	var activity model.AuthorActivity
+
+	err := s.db.QueryRow(ctx, `
+		WITH recent AS (
+			SELECT 'post' AS kind, created_at, title || ' ' || content AS text
+			FROM posts
+			WHERE author_uuid = $1 AND created_at > NOW() - INTERVAL '1 day'
+			UNION ALL
+			SELECT 'comment', created_at, content
+			FROM comments
+			WHERE author_uuid = $1 AND created_at > NOW() - INTERVAL '1 day'
+		), hourly AS (
+			SELECT kind, (SELECT COUNT(*) FROM regexp_matches(text, $2, 'gi')) AS links
+			FROM recent
+			WHERE created_at > NOW() - INTERVAL '1 hour'
+		)
+		SELECT (SELECT COUNT(*) FROM hourly WHERE kind = 'post'),
+		       (SELECT COUNT(*) FROM recent WHERE kind = 'post'),
+		       (SELECT COUNT(*) FROM hourly WHERE kind = 'comment'),
+		       (SELECT COUNT(*) FROM recent WHERE kind = 'comment'),
+		       (SELECT COALESCE(SUM(links), 0)::bigint FROM hourly)`,
+		authorUUID, model.LinkPattern,
+	).Scan(
+		&activity.PostsLastHour,
+		&activity.PostsLastDay,
+		&activity.CommentsLastHour,
+		&activity.CommentsLastDay,
+		&activity.LinksLastHour,
+	)
+	if err != nil {
+		return model.AuthorActivity{}, fmt.Errorf("GetAuthorActivity - Scan - %w", err)
+	}
+
+	return activity, nil
+}
+
+// insertPolicyReport ставит задержанный правилом контент в очередь модерации.
+func insertPolicyReport(ctx context.Context, tx pgx.Tx, target model.ModerationTarget, ruleID int64) error {
+	_, err := tx.Exec(ctx, `
+		INSERT INTO reports (target_type, target_id, reason, rule_id)
+		VALUES ($1, $2, $3, NULLIF($4::bigint, 0))`,
+		target.Type, target.ID, model.ReportReasonPolicy, ruleID,
+	)
+	if err != nil {
+		return fmt.Errorf("insertPolicyReport - Exec - %w", err)
+	}
+
+	return nil
+}
+
+func scanContentRule(row pgx.CollectableRow) (model.ContentRule, error) {
+	var rule model.ContentRule
+
+	err := row.Scan(
+		&rule.ID,
+		&rule.Name,
+		&rule.Description,
+		&rule.Expression,
+		&rule.Action,
+		&rule.Priority,
+		&rule.Enabled,
+		&rule.CreatedBy,
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+	)
+
+	return rule, err
+}
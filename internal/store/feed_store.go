@@ -0,0 +1,48 @@
+package store
+
+import (
+	"context"
+	"fmt"
+)
+
+// feedStateChannel канал NOTIFY, в который триггеры feed_state_notify пишут
+// об изменениях постов, их тегов и авторов.
+const feedStateChannel = "feed_state"
+
+// ListenFeedChanges держит отдельное соединение с LISTEN на канале изменений
+// лент и вызывает notify на каждое изменение. Возвращается при отмене ctx
+// или потере соединения; изменения, пришедшие без соединения, теряются,
+// поэтому после ошибки слушатели должны перечитать состояние сами.
+func (s *Store) ListenFeedChanges(ctx context.Context, listening func(), notify func()) error {
+	return s.listen(ctx, feedStateChannel, listening, func(string) {
+		notify()
+	})
+}
+
+// listen держит соединение с LISTEN на channel и передаёт в notify полезную
+// нагрузку каждого сигнала. listening вызывается, когда LISTEN уже действует.
+func (s *Store) listen(ctx context.Context, channel string, listening func(), notify func(payload string)) error {
+	pooled, err := s.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("listen - Acquire - %w", err)
+	}
+
+	// Соединение в режиме LISTEN не возвращается в пул.
+	conn := pooled.Hijack()
+	defer conn.Close(context.Background()) //nolint:errcheck
+
+	if _, err = conn.Exec(ctx, `LISTEN `+channel); err != nil {
+		return fmt.Errorf("listen - LISTEN - %w", err)
+	}
+
+	listening()
+
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("listen - WaitForNotification - %w", err)
+		}
+
+		notify(n.Payload)
+	}
+}
@@ -0,0 +1,321 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"time"
+)
+
+// timelineLockClass класс advisory-блокировок авторов ('tlne'), тот же, что
+// в триггере timeline_fan_out. Подписка, отписка и публикация берут
+// блокировку автора разделяемой, смена его режима ленты — исключительной.
+const timelineLockClass int32 = 0x746c6e65
+
+func lockTimelineAuthor(ctx context.Context, tx pgx.Tx, authorUUID uuid.UUID, exclusive bool) error {
+	lock := `SELECT pg_advisory_xact_lock_shared($1, hashtext($2))`
+	if exclusive {
+		lock = `SELECT pg_advisory_xact_lock($1, hashtext($2))`
+	}
+
+	_, err := tx.Exec(ctx, lock, timelineLockClass, authorUUID.String())
+	return err
+}
+
+// Follow подписывает follower на followee. Если followee пишет много, его
+// посты, опубликованные после since, сразу раскладываются в ленту подписчика.
+// Повторная подписка ничего не меняет.
+func (s *Store) Follow(ctx context.Context, followerUUID, followeeUUID uuid.UUID, since time.Time) (bool, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("Follow - Begin - %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	if err = lockTimelineAuthor(ctx, tx, followeeUUID, false); err != nil {
+		return false, fmt.Errorf("Follow - lock - %w", err)
+	}
+
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO follows (follower_uuid, followee_uuid)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING`,
+		followerUUID, followeeUUID,
+	)
+	if err != nil {
+		return false, fmt.Errorf("Follow - insert - %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO timeline_entries (user_uuid, post_id, author_uuid, published_at)
+		SELECT $1, p.id, p.author_uuid, p.published_at
+		FROM posts p
+		WHERE p.author_uuid = $2 AND p.status = 'published' AND p.deleted_at IS NULL AND p.published_at >= $3
+		  AND EXISTS (SELECT 1 FROM prolific_authors WHERE author_uuid = $2)
+		ON CONFLICT DO NOTHING`,
+		followerUUID, followeeUUID, since,
+	)
+	if err != nil {
+		return false, fmt.Errorf("Follow - backfill - %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("Follow - Commit - %w", err)
+	}
+
+	return true, nil
+}
+
+// Unfollow отменяет подписку и убирает посты followee из предвычисленной ленты.
+func (s *Store) Unfollow(ctx context.Context, followerUUID, followeeUUID uuid.UUID) (bool, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("Unfollow - Begin - %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	if err = lockTimelineAuthor(ctx, tx, followeeUUID, false); err != nil {
+		return false, fmt.Errorf("Unfollow - lock - %w", err)
+	}
+
+	tag, err := tx.Exec(ctx, `DELETE FROM follows WHERE follower_uuid = $1 AND followee_uuid = $2`, followerUUID, followeeUUID)
+	if err != nil {
+		return false, fmt.Errorf("Unfollow - delete - %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `DELETE FROM timeline_entries WHERE user_uuid = $1 AND author_uuid = $2`, followerUUID, followeeUUID)
+	if err != nil {
+		return false, fmt.Errorf("Unfollow - delete entries - %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("Unfollow - Commit - %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// ListFollowers подписчики пользователя от новых подписок к старым.
+func (s *Store) ListFollowers(ctx context.Context, userUUID uuid.UUID, before *time.Time, beforeUser uuid.UUID, limit int) ([]model.Follow, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT f.follower_uuid, f.followee_uuid, f.created_at
+		FROM follows f
+		JOIN users u ON u.uuid = f.follower_uuid AND u.deleted_at IS NULL
+		WHERE f.followee_uuid = $1
+		  AND ($2::timestamptz IS NULL OR (f.created_at, f.follower_uuid) < ($2, $3))
+		ORDER BY f.created_at DESC, f.follower_uuid DESC
+		LIMIT $4`,
+		userUUID, before, beforeUser, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ListFollowers - Query - %w", err)
+	}
+
+	follows, err := pgx.CollectRows(rows, scanFollow)
+	if err != nil {
+		return nil, fmt.Errorf("ListFollowers - CollectRows - %w", err)
+	}
+
+	return follows, nil
+}
+
+// ListFollowing подписки пользователя от новых к старым.
+func (s *Store) ListFollowing(ctx context.Context, userUUID uuid.UUID, before *time.Time, beforeUser uuid.UUID, limit int) ([]model.Follow, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT f.follower_uuid, f.followee_uuid, f.created_at
+		FROM follows f
+		JOIN users u ON u.uuid = f.followee_uuid AND u.deleted_at IS NULL
+		WHERE f.follower_uuid = $1
+		  AND ($2::timestamptz IS NULL OR (f.created_at, f.followee_uuid) < ($2, $3))
+		ORDER BY f.created_at DESC, f.followee_uuid DESC
+		LIMIT $4`,
+		userUUID, before, beforeUser, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ListFollowing - Query - %w", err)
+	}
+
+	follows, err := pgx.CollectRows(rows, scanFollow)
+	if err != nil {
+		return nil, fmt.Errorf("ListFollowing - CollectRows - %w", err)
+	}
+
+	return follows, nil
+}
+
+func scanFollow(row pgx.CollectableRow) (model.Follow, error) {
+	var f model.Follow
+	err := row.Scan(&f.FollowerUUID, &f.FolloweeUUID, &f.CreatedAt)
+	return f, err
+}
+
+// ListTimeline опубликованные посты авторов, на которых подписан viewer,
+// начиная с since, в порядке публикации. Посты авторов из prolific_authors
+// берутся из предвычисленной ленты, остальных — из posts по подпискам.
+// Каждая часть ограничивается limit до объединения, так объём работы не
+// зависит от числа подписок на активных авторов.
+func (s *Store) ListTimeline(ctx context.Context, viewer uuid.UUID, since time.Time, before *time.Time, beforeID int64, limit int) ([]model.Post, error) {
+	rows, err := s.db.Query(ctx, `
+		WITH candidates AS (
+			(SELECT te.post_id AS id
+			 FROM timeline_entries te
+			 JOIN posts p ON p.id = te.post_id
+			 WHERE te.user_uuid = $1 AND te.published_at >= $2
+			   AND ($3::timestamptz IS NULL OR (te.published_at, te.post_id) < ($3, $4))
+			   AND p.status = 'published' AND p.deleted_at IS NULL AND p.hidden_at IS NULL
+			   AND p.author_uuid NOT IN (`+shadowBannedUsers+`)
+			 ORDER BY te.published_at DESC, te.post_id DESC
+			 LIMIT $5)
+			UNION
+			(SELECT p.id
+			 FROM follows f
+			 JOIN posts p ON p.author_uuid = f.followee_uuid
+			 WHERE f.follower_uuid = $1
+			   AND NOT EXISTS (SELECT 1 FROM prolific_authors pa WHERE pa.author_uuid = f.followee_uuid)
+			   AND p.status = 'published' AND p.published_at >= $2
+			   AND ($3::timestamptz IS NULL OR (p.published_at, p.id) < ($3, $4))
+			   AND p.deleted_at IS NULL AND p.hidden_at IS NULL
+			   AND p.author_uuid NOT IN (`+shadowBannedUsers+`)
+			 ORDER BY p.published_at DESC, p.id DESC
+			 LIMIT $5)
+		)
+		SELECT `+postColumns+`
+		FROM posts p
+		WHERE p.id IN (SELECT id FROM candidates)
+		ORDER BY p.published_at DESC, p.id DESC
+		LIMIT $5`,
+		viewer, since, before, beforeID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ListTimeline - Query - %w", err)
+	}
+
+	posts, err := pgx.CollectRows(rows, scanPost)
+	if err != nil {
+		return nil, fmt.Errorf("ListTimeline - CollectRows - %w", err)
+	}
+
+	return posts, nil
+}
+
+// ListProlificChanges авторы, чей режим ленты пора сменить. Автор становится
+// активным, опубликовав за window не меньше threshold постов, а перестаёт,
+// когда их становится меньше половины threshold: так режим не переключается
+// туда и обратно на каждой проверке.
+func (s *Store) ListProlificChanges(ctx context.Context, threshold int, window time.Duration) (entering, leaving []uuid.UUID, err error) {
+	rows, err := s.db.Query(ctx, `
+		WITH counts AS (
+			SELECT author_uuid, COUNT(*) AS posts
+			FROM posts
+			WHERE status = 'published' AND deleted_at IS NULL AND published_at >= $1
+			GROUP BY author_uuid
+		)
+		SELECT c.author_uuid, TRUE
+		FROM counts c
+		WHERE c.posts >= $2 AND NOT EXISTS (SELECT 1 FROM prolific_authors pa WHERE pa.author_uuid = c.author_uuid)
+		UNION ALL
+		SELECT pa.author_uuid, FALSE
+		FROM prolific_authors pa
+		LEFT JOIN counts c ON c.author_uuid = pa.author_uuid
+		WHERE COALESCE(c.posts, 0) < $2 / 2`,
+		time.Now().Add(-window), threshold,
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ListProlificChanges - Query - %w", err)
+	}
+
+	var (
+		author uuid.UUID
+		enter  bool
+	)
+	_, err = pgx.ForEachRow(rows, []any{&author, &enter}, func() error {
+		if enter {
+			entering = append(entering, author)
+		} else {
+			leaving = append(leaving, author)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("ListProlificChanges - ForEachRow - %w", err)
+	}
+
+	return entering, leaving, nil
+}
+
+// SetProlificAuthor переключает режим ленты автора. Активному автору посты,
+// опубликованные после since, раскладываются по лентам всех подписчиков;
+// у переставшего быть активным они убираются, и лента снова собирает его
+// посты при чтении. Переключение атомарно для читателей ленты.
+func (s *Store) SetProlificAuthor(ctx context.Context, authorUUID uuid.UUID, prolific bool, since time.Time) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("SetProlificAuthor - Begin - %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	if err = lockTimelineAuthor(ctx, tx, authorUUID, true); err != nil {
+		return fmt.Errorf("SetProlificAuthor - lock - %w", err)
+	}
+
+	if prolific {
+		tag, err := tx.Exec(ctx, `INSERT INTO prolific_authors (author_uuid) VALUES ($1) ON CONFLICT DO NOTHING`, authorUUID)
+		if err != nil {
+			return fmt.Errorf("SetProlificAuthor - insert - %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return nil
+		}
+
+		_, err = tx.Exec(ctx, `
+			INSERT INTO timeline_entries (user_uuid, post_id, author_uuid, published_at)
+			SELECT f.follower_uuid, p.id, p.author_uuid, p.published_at
+			FROM follows f
+			JOIN posts p ON p.author_uuid = f.followee_uuid
+			WHERE f.followee_uuid = $1 AND p.status = 'published' AND p.deleted_at IS NULL AND p.published_at >= $2
+			ON CONFLICT DO NOTHING`,
+			authorUUID, since,
+		)
+		if err != nil {
+			return fmt.Errorf("SetProlificAuthor - backfill - %w", err)
+		}
+	} else {
+		if _, err = tx.Exec(ctx, `DELETE FROM prolific_authors WHERE author_uuid = $1`, authorUUID); err != nil {
+			return fmt.Errorf("SetProlificAuthor - delete - %w", err)
+		}
+
+		if _, err = tx.Exec(ctx, `DELETE FROM timeline_entries WHERE author_uuid = $1`, authorUUID); err != nil {
+			return fmt.Errorf("SetProlificAuthor - delete entries - %w", err)
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("SetProlificAuthor - Commit - %w", err)
+	}
+
+	return nil
+}
+
+// PruneTimeline удаляет из предвычисленной ленты записи старше since и
+// записи постов, которые больше не опубликованы.
+func (s *Store) PruneTimeline(ctx context.Context, since time.Time) (int64, error) {
+	tag, err := s.db.Exec(ctx, `
+		DELETE FROM timeline_entries te
+		WHERE te.published_at < $1
+		   OR EXISTS (
+				SELECT 1 FROM posts p
+				WHERE p.id = te.post_id AND (p.status <> 'published' OR p.deleted_at IS NOT NULL)
+			)`,
+		since,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("PruneTimeline - Exec - %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,138 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+func (s *Store) GetUserByIdentity(ctx context.Context, provider, subject string) (model.User, error) {
+	var userUUID uuid.UUID
+
+	err := s.db.QueryRow(ctx, `
+		SELECT user_uuid
+		FROM user_identities
+		WHERE provider = $1 AND subject = $2`,
+		provider, subject,
+	).Scan(&userUUID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.User{}, model.ErrNotFound
+		}
+		return model.User{}, fmt.Errorf("GetUserByIdentity - Scan - %w", err)
+	}
+
+	return s.GetUserByUUID(ctx, userUUID)
+}
+
+func (s *Store) LinkIdentity(ctx context.Context, userUUID uuid.UUID, identity model.ExternalIdentity) error {
+	_, err := s.db.Exec(ctx, `
+		INSERT INTO user_identities (provider, subject, user_uuid, email)
+		VALUES ($1, $2, $3, $4)`,
+		identity.Provider, identity.Subject, userUUID, identity.Email,
+	)
+	if err != nil {
+		if isPgError(err, uniqueViolation) {
+			return model.ErrAlreadyExists
+		}
+		return fmt.Errorf("LinkIdentity - Exec - %w", err)
+	}
+
+	return nil
+}
+
+// CreateUserWithIdentity создаёт пользователя с ролью по умолчанию и сразу
+// привязывает к нему внешнюю учётную запись.
+func (s *Store) CreateUserWithIdentity(ctx context.Context, user model.User, identity model.ExternalIdentity) (uuid.UUID, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("CreateUserWithIdentity - Begin - %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	var id uuid.UUID
+	err = tx.QueryRow(ctx, `
+		INSERT INTO users (email, first_name, password_hash, email_verified_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING uuid`,
+		user.Email, user.FirstName, user.PasswordHash, user.EmailVerifiedAt,
+	).Scan(&id)
+	if err != nil {
+		if isPgError(err, uniqueViolation) {
+			return uuid.Nil, model.ErrAlreadyExists
+		}
+		return uuid.Nil, fmt.Errorf("CreateUserWithIdentity - insert user - %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO user_roles (user_uuid, role_id)
+		SELECT $1, id FROM roles WHERE name = $2`,
+		id, model.RoleUser,
+	)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("CreateUserWithIdentity - insert role - %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO user_identities (provider, subject, user_uuid, email)
+		VALUES ($1, $2, $3, $4)`,
+		identity.Provider, identity.Subject, id, identity.Email,
+	)
+	if err != nil {
+		if isPgError(err, uniqueViolation) {
+			return uuid.Nil, model.ErrAlreadyExists
+		}
+		return uuid.Nil, fmt.Errorf("CreateUserWithIdentity - insert identity - %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return uuid.Nil, fmt.Errorf("CreateUserWithIdentity - Commit - %w", err)
+	}
+
+	return id, nil
+}
+
+func (s *Store) CreateSSOState(ctx context.Context, stateHash string, state model.SSOState) error {
+	_, err := s.db.Exec(ctx, `
+		INSERT INTO sso_states (state_hash, provider, nonce, code_verifier, expires_at)
+		VALUES ($1, $2, $3, $4, $5)`,
+		stateHash, state.Provider, state.Nonce, state.CodeVerifier, state.ExpiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("CreateSSOState - Exec - %w", err)
+	}
+
+	return nil
+}
+
+// ConsumeSSOState атомарно удаляет state и возвращает его параметры.
+func (s *Store) ConsumeSSOState(ctx context.Context, stateHash, provider string) (model.SSOState, error) {
+	var state model.SSOState
+
+	err := s.db.QueryRow(ctx, `
+		DELETE FROM sso_states
+		WHERE state_hash = $1 AND provider = $2
+		RETURNING provider, nonce, code_verifier, expires_at`,
+		stateHash, provider,
+	).Scan(&state.Provider, &state.Nonce, &state.CodeVerifier, &state.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.SSOState{}, model.ErrNotFound
+		}
+		return model.SSOState{}, fmt.Errorf("ConsumeSSOState - Scan - %w", err)
+	}
+
+	return state, nil
+}
+
+// DeleteExpiredSSOStates удаляет state, с которыми пользователь так и не вернулся.
+func (s *Store) DeleteExpiredSSOStates(ctx context.Context) error {
+	if _, err := s.db.Exec(ctx, `DELETE FROM sso_states WHERE expires_at < NOW()`); err != nil {
+		return fmt.Errorf("DeleteExpiredSSOStates - Exec - %w", err)
+	}
+
+	return nil
+}
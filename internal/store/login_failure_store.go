@@ -0,0 +1,99 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/jackc/pgx/v5"
+	"time"
+)
+
+func (s *Store) GetLoginFailures(ctx context.Context, scope, subject string) (model.LoginFailures, error) {
+	var f model.LoginFailures
+
+	err := s.db.QueryRow(ctx, `
+		SELECT failures, lockouts, locked_until
+		FROM login_failures
+		WHERE scope = $1 AND subject = $2`,
+		scope, subject,
+	).Scan(&f.Failures, &f.Lockouts, &f.LockedUntil)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.LoginFailures{}, nil
+		}
+		return model.LoginFailures{}, fmt.Errorf("GetLoginFailures - Scan - %w", err)
+	}
+
+	return f, nil
+}
+
+// RecordLoginFailure увеличивает счётчик неудач. Если последняя неудача была
+// раньше window, счёт начинается заново.
+func (s *Store) RecordLoginFailure(ctx context.Context, scope, subject string, window time.Duration) (model.LoginFailures, error) {
+	var f model.LoginFailures
+
+	err := s.db.QueryRow(ctx, `
+		INSERT INTO login_failures AS lf (scope, subject, failures, last_failure_at)
+		VALUES ($1, $2, 1, NOW())
+		ON CONFLICT (scope, subject) DO UPDATE
+			SET failures        = CASE
+			                          WHEN lf.last_failure_at < NOW() - make_interval(secs => $3) THEN 1
+			                          ELSE lf.failures + 1
+			                      END,
+			    last_failure_at = NOW()
+		RETURNING failures, lockouts, locked_until`,
+		scope, subject, window.Seconds(),
+	).Scan(&f.Failures, &f.Lockouts, &f.LockedUntil)
+	if err != nil {
+		return model.LoginFailures{}, fmt.Errorf("RecordLoginFailure - Scan - %w", err)
+	}
+
+	return f, nil
+}
+
+// LockLogin блокирует вход до until и сбрасывает счётчик неудач.
+// Число блокировок сохраняется, чтобы следующая была длиннее.
+func (s *Store) LockLogin(ctx context.Context, scope, subject string, until time.Time) error {
+	_, err := s.db.Exec(ctx, `
+		UPDATE login_failures
+		SET failures = 0, lockouts = lockouts + 1, locked_until = $3
+		WHERE scope = $1 AND subject = $2`,
+		scope, subject, until,
+	)
+	if err != nil {
+		return fmt.Errorf("LockLogin - Exec - %w", err)
+	}
+
+	return nil
+}
+
+func (s *Store) ResetLoginFailures(ctx context.Context, scope, subject string) error {
+	_, err := s.db.Exec(ctx, `
+		DELETE FROM login_failures
+		WHERE scope = $1 AND subject = $2`,
+		scope, subject,
+	)
+	if err != nil {
+		return fmt.Errorf("ResetLoginFailures - Exec - %w", err)
+	}
+
+	return nil
+}
+
+// DeleteStaleLoginFailures удаляет счётчики, по которым не было неудач с
+// cutoff и нет действующей блокировки. Вместе с ними забывается и число
+// прошлых блокировок.
+func (s *Store) DeleteStaleLoginFailures(ctx context.Context, cutoff time.Time) (int64, error) {
+	tag, err := s.db.Exec(ctx, `
+		DELETE FROM login_failures
+		WHERE last_failure_at < $1
+		  AND (locked_until IS NULL OR locked_until < NOW())`,
+		cutoff,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("DeleteStaleLoginFailures - Exec - %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,143 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// setPostMentions приводит упоминания поста к заданному набору. У оставшихся
+// упоминаний сохраняется отметка об отправленном уведомлении.
+func setPostMentions(ctx context.Context, tx pgx.Tx, postID int64, mentions []model.Mention) error {
+	users, handles := splitMentions(mentions)
+
+	_, err := tx.Exec(ctx, `DELETE FROM post_mentions WHERE post_id = $1 AND NOT (user_uuid = ANY($2))`, postID, users)
+	if err != nil {
+		return fmt.Errorf("setPostMentions - remove - %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO post_mentions (post_id, user_uuid, handle)
+		SELECT $1, * FROM unnest($2::uuid[], $3::varchar[])
+		ON CONFLICT (post_id, user_uuid) DO UPDATE SET handle = EXCLUDED.handle`,
+		postID, users, handles,
+	)
+	if err != nil {
+		return fmt.Errorf("setPostMentions - add - %w", err)
+	}
+
+	return nil
+}
+
+func insertCommentMentions(ctx context.Context, tx pgx.Tx, commentID int64, mentions []model.Mention) error {
+	users, handles := splitMentions(mentions)
+
+	_, err := tx.Exec(ctx, `
+		INSERT INTO comment_mentions (comment_id, user_uuid, handle)
+		SELECT $1, * FROM unnest($2::uuid[], $3::varchar[])
+		ON CONFLICT DO NOTHING`,
+		commentID, users, handles,
+	)
+	if err != nil {
+		return fmt.Errorf("insertCommentMentions - Exec - %w", err)
+	}
+
+	return nil
+}
+
+func splitMentions(mentions []model.Mention) ([]uuid.UUID, []string) {
+	users := make([]uuid.UUID, 0, len(mentions))
+	handles := make([]string, 0, len(mentions))
+	for _, m := range mentions {
+		users = append(users, m.UserUUID)
+		handles = append(handles, m.Handle)
+	}
+
+	return users, handles
+}
+
+// GetPostMentions возвращает упоминания для набора постов.
+func (s *Store) GetPostMentions(ctx context.Context, postIDs []int64) (map[int64][]model.Mention, error) {
+	return s.getMentions(ctx, `SELECT post_id, handle, user_uuid FROM post_mentions WHERE post_id = ANY($1)`, postIDs)
+}
+
+// GetCommentMentions возвращает упоминания для набора комментариев.
+func (s *Store) GetCommentMentions(ctx context.Context, commentIDs []int64) (map[int64][]model.Mention, error) {
+	return s.getMentions(ctx, `SELECT comment_id, handle, user_uuid FROM comment_mentions WHERE comment_id = ANY($1)`, commentIDs)
+}
+
+func (s *Store) getMentions(ctx context.Context, query string, ids []int64) (map[int64][]model.Mention, error) {
+	rows, err := s.db.Query(ctx, query, ids)
+	if err != nil {
+		return nil, fmt.Errorf("getMentions - Query - %w", err)
+	}
+	defer rows.Close()
+
+	mentions := make(map[int64][]model.Mention, len(ids))
+	for rows.Next() {
+		var (
+			id      int64
+			mention model.Mention
+		)
+		if err = rows.Scan(&id, &mention.Handle, &mention.UserUUID); err != nil {
+			return nil, fmt.Errorf("getMentions - Scan - %w", err)
+		}
+
+		mentions[id] = append(mentions[id], mention)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("getMentions - rows.Err - %w", err)
+	}
+
+	return mentions, nil
+}
+
+// ClaimPostMentions отмечает уведомлёнными упоминания в постах, которые уже
+// видны всем, и возвращает их как уведомления. postID = 0 — в любых постах,
+// например опубликованных по расписанию или после модерации. Параллельные
+// вызовы не получают одно упоминание дважды.
+func (s *Store) ClaimPostMentions(ctx context.Context, postID int64, limit int) ([]model.Notification, error) {
+	rows, err := s.db.Query(ctx, `
+		WITH claimed AS (
+			SELECT m.post_id, m.user_uuid, p.author_uuid
+			FROM post_mentions m
+			JOIN posts p ON p.id = m.post_id
+			WHERE m.notified_at IS NULL
+			  AND ($1 = 0 OR m.post_id = $1)
+			  AND p.status = 'published' AND p.deleted_at IS NULL AND p.hidden_at IS NULL
+			  AND p.author_uuid NOT IN (`+shadowBannedUsers+`)
+			ORDER BY m.post_id
+			LIMIT $2
+			FOR UPDATE OF m SKIP LOCKED
+		)
+		UPDATE post_mentions m
+		SET notified_at = NOW()
+		FROM claimed c
+		WHERE m.post_id = c.post_id AND m.user_uuid = c.user_uuid
+		RETURNING m.post_id, m.user_uuid, c.author_uuid`,
+		postID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ClaimPostMentions - Query - %w", err)
+	}
+
+	notifications, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.Notification, error) {
+		n := model.Notification{Type: model.NotificationMention}
+		err := row.Scan(&n.PostID, &n.UserUUID, &n.ActorUUID)
+		return n, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ClaimPostMentions - CollectRows - %w", err)
+	}
+
+	return notifications, nil
+}
+
+func scanMention(row pgx.CollectableRow) (model.Mention, error) {
+	var m model.Mention
+	err := row.Scan(&m.Handle, &m.UserUUID)
+	return m, err
+}
@@ -0,0 +1,154 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// SavePendingMFA сохраняет новый секрет, пока 2FA не подтверждена.
+// Для пользователя с уже включённой 2FA секрет не перезаписывается.
+func (s *Store) SavePendingMFA(ctx context.Context, userUUID uuid.UUID, secretEncrypted []byte) error {
+	tag, err := s.db.Exec(ctx, `
+		INSERT INTO user_mfa (user_uuid, secret_encrypted)
+		VALUES ($1, $2)
+		ON CONFLICT (user_uuid) DO UPDATE
+			SET secret_encrypted = EXCLUDED.secret_encrypted,
+			    last_used_step   = 0,
+			    created_at       = NOW()
+			WHERE user_mfa.enabled_at IS NULL`,
+		userUUID, secretEncrypted,
+	)
+	if err != nil {
+		return fmt.Errorf("SavePendingMFA - Exec - %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return model.ErrAlreadyExists
+	}
+
+	return nil
+}
+
+func (s *Store) GetMFA(ctx context.Context, userUUID uuid.UUID) (model.MFA, error) {
+	var mfa model.MFA
+
+	err := s.db.QueryRow(ctx, `
+		SELECT user_uuid, secret_encrypted, enabled_at, last_used_step
+		FROM user_mfa
+		WHERE user_uuid = $1`,
+		userUUID,
+	).Scan(&mfa.UserUUID, &mfa.SecretEncrypted, &mfa.EnabledAt, &mfa.LastUsedStep)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.MFA{}, model.ErrNotFound
+		}
+		return model.MFA{}, fmt.Errorf("GetMFA - Scan - %w", err)
+	}
+
+	return mfa, nil
+}
+
+// EnableMFA включает 2FA и заменяет коды восстановления.
+func (s *Store) EnableMFA(ctx context.Context, userUUID uuid.UUID, step int64, recoveryCodeHashes []string) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("EnableMFA - Begin - %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE user_mfa
+		SET enabled_at = NOW(), last_used_step = $2
+		WHERE user_uuid = $1 AND enabled_at IS NULL AND last_used_step < $2`,
+		userUUID, step,
+	)
+	if err != nil {
+		return fmt.Errorf("EnableMFA - update - %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return model.ErrNotFound
+	}
+
+	if err = replaceRecoveryCodes(ctx, tx, userUUID, recoveryCodeHashes); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("EnableMFA - Commit - %w", err)
+	}
+
+	return nil
+}
+
+// UseTOTPStep атомарно отмечает шаг использованным. Повторный код того же
+// или более раннего шага отклоняется.
+func (s *Store) UseTOTPStep(ctx context.Context, userUUID uuid.UUID, step int64) (bool, error) {
+	tag, err := s.db.Exec(ctx, `
+		UPDATE user_mfa
+		SET last_used_step = $2
+		WHERE user_uuid = $1 AND last_used_step < $2`,
+		userUUID, step,
+	)
+	if err != nil {
+		return false, fmt.Errorf("UseTOTPStep - Exec - %w", err)
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
+
+func (s *Store) UseRecoveryCode(ctx context.Context, userUUID uuid.UUID, codeHash string) (bool, error) {
+	tag, err := s.db.Exec(ctx, `
+		UPDATE user_recovery_codes
+		SET used_at = NOW()
+		WHERE user_uuid = $1 AND code_hash = $2 AND used_at IS NULL`,
+		userUUID, codeHash,
+	)
+	if err != nil {
+		return false, fmt.Errorf("UseRecoveryCode - Exec - %w", err)
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
+
+func (s *Store) DeleteMFA(ctx context.Context, userUUID uuid.UUID) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("DeleteMFA - Begin - %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	if _, err = tx.Exec(ctx, `DELETE FROM user_recovery_codes WHERE user_uuid = $1`, userUUID); err != nil {
+		return fmt.Errorf("DeleteMFA - delete codes - %w", err)
+	}
+
+	if _, err = tx.Exec(ctx, `DELETE FROM user_mfa WHERE user_uuid = $1`, userUUID); err != nil {
+		return fmt.Errorf("DeleteMFA - delete mfa - %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("DeleteMFA - Commit - %w", err)
+	}
+
+	return nil
+}
+
+func replaceRecoveryCodes(ctx context.Context, tx pgx.Tx, userUUID uuid.UUID, hashes []string) error {
+	if _, err := tx.Exec(ctx, `DELETE FROM user_recovery_codes WHERE user_uuid = $1`, userUUID); err != nil {
+		return fmt.Errorf("replaceRecoveryCodes - delete - %w", err)
+	}
+
+	_, err := tx.Exec(ctx, `
+		INSERT INTO user_recovery_codes (user_uuid, code_hash)
+		SELECT $1, unnest($2::varchar[])`,
+		userUUID, hashes,
+	)
+	if err != nil {
+		return fmt.Errorf("replaceRecoveryCodes - insert - %w", err)
+	}
+
+	return nil
+}
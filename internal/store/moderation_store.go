@@ -0,0 +1,282 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const moderationLogColumns = `id, target_type, target_id, action, moderator_uuid, COALESCE(author_uuid, uuid_nil()),
+	note, reports_resolved, created_at`
+
+// moderationTables таблицы контента по типу цели модерации.
+var moderationTables = map[string]string{
+	model.ReactionTargetPost:    "posts",
+	model.ReactionTargetComment: "comments",
+}
+
+// CreateReport сохраняет жалобу. Если у читателя уже есть нерассмотренная жалоба
+// на этот контент, новая не добавляется и возвращается created = false.
+func (s *Store) CreateReport(ctx context.Context, report model.Report) (bool, error) {
+	tag, err := s.db.Exec(ctx, `
+		INSERT INTO reports (target_type, target_id, reporter_uuid, reason)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (target_type, target_id, reporter_uuid) WHERE resolved_at IS NULL DO NOTHING`,
+		report.Target.Type, report.Target.ID, report.ReporterUUID, report.Reason,
+	)
+	if err != nil {
+		return false, fmt.Errorf("CreateReport - Exec - %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// ListModerationQueue возвращает контент с нерассмотренными жалобами: сначала
+// с наибольшим числом жалоб, при равенстве — с самой давней жалобой. after —
+// последний элемент предыдущей страницы, nil для первой. Удалённый контент в
+// очередь не попадает.
+func (s *Store) ListModerationQueue(ctx context.Context, targetType string, after *model.ModerationItem, limit int) ([]model.ModerationItem, error) {
+	var (
+		afterReports int64
+		afterFirst   time.Time
+		afterTarget  model.ModerationTarget
+	)
+	if after != nil {
+		afterReports, afterFirst, afterTarget = after.Reports, after.FirstReportedAt, after.Target
+	}
+
+	rows, err := s.db.Query(ctx, `
+		WITH queue AS (
+			SELECT target_type, target_id, COUNT(*) AS reports,
+			       MIN(created_at) AS first_reported_at, MAX(created_at) AS last_reported_at
+			FROM reports
+			WHERE resolved_at IS NULL AND ($1 = '' OR target_type = $1)
+			GROUP BY target_type, target_id
+		)
+		SELECT q.target_type, q.target_id,
+		       COALESCE(p.author_uuid, c.author_uuid, uuid_nil()), COALESCE(p.title, ''), COALESCE(p.content, c.content),
+		       COALESCE(p.hidden_at, c.hidden_at), q.reports, q.first_reported_at, q.last_reported_at
+		FROM queue q
+		         LEFT JOIN posts p ON q.target_type = 'post' AND p.id = q.target_id AND p.deleted_at IS NULL
+		         LEFT JOIN comments c ON q.target_type = 'comment' AND c.id = q.target_id AND c.deleted_at IS NULL
+		WHERE (p.id IS NOT NULL OR c.id IS NOT NULL)
+		  AND ($2 = 0 OR q.reports < $2 OR (q.reports = $2 AND
+		      (q.first_reported_at, q.target_type, q.target_id) > ($3::timestamptz, $4::varchar, $5::integer)))
+		ORDER BY q.reports DESC, q.first_reported_at, q.target_type, q.target_id
+		LIMIT $6`,
+		targetType, afterReports, afterFirst, afterTarget.Type, afterTarget.ID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ListModerationQueue - Query - %w", err)
+	}
+
+	items, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.ModerationItem, error) {
+		var item model.ModerationItem
+		err := row.Scan(
+			&item.Target.Type,
+			&item.Target.ID,
+			&item.AuthorUUID,
+			&item.Title,
+			&item.Content,
+			&item.HiddenAt,
+			&item.Reports,
+			&item.FirstReportedAt,
+			&item.LastReportedAt,
+		)
+		return item, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ListModerationQueue - CollectRows - %w", err)
+	}
+
+	if err = s.fillReportReasons(ctx, items); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// fillReportReasons раскладывает открытые жалобы элементов очереди по причинам.
+func (s *Store) fillReportReasons(ctx context.Context, items []model.ModerationItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	types := make([]string, 0, len(items))
+	ids := make([]int64, 0, len(items))
+	index := make(map[model.ModerationTarget]int, len(items))
+	for i, item := range items {
+		types = append(types, item.Target.Type)
+		ids = append(ids, item.Target.ID)
+		index[item.Target] = i
+		items[i].Reasons = make(map[string]int64)
+	}
+
+	rows, err := s.db.Query(ctx, `
+		SELECT r.target_type, r.target_id, r.reason, COUNT(*)
+		FROM reports r
+		         JOIN UNNEST($1::varchar[], $2::integer[]) AS t(target_type, target_id)
+		              ON r.target_type = t.target_type AND r.target_id = t.target_id
+		WHERE r.resolved_at IS NULL
+		GROUP BY r.target_type, r.target_id, r.reason`,
+		types, ids,
+	)
+	if err != nil {
+		return fmt.Errorf("fillReportReasons - Query - %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			target model.ModerationTarget
+			reason string
+			count  int64
+		)
+		if err = rows.Scan(&target.Type, &target.ID, &reason, &count); err != nil {
+			return fmt.Errorf("fillReportReasons - Scan - %w", err)
+		}
+		if i, ok := index[target]; ok {
+			items[i].Reasons[reason] = count
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("fillReportReasons - Rows - %w", err)
+	}
+
+	return nil
+}
+
+// ApplyModerationAction применяет действие модератора к контенту, закрывает все
+// открытые жалобы на него и записывает действие в журнал в одной транзакции.
+func (s *Store) ApplyModerationAction(ctx context.Context, entry model.ModerationLogEntry) (model.ModerationLogEntry, error) {
+	table, ok := moderationTables[entry.Target.Type]
+	if !ok {
+		return model.ModerationLogEntry{}, fmt.Errorf("%w: unknown moderation target %q", model.ErrInvalidArgument, entry.Target.Type)
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return model.ModerationLogEntry{}, fmt.Errorf("ApplyModerationAction - Begin - %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	switch entry.Action {
+	case model.ModerationActionHide, model.ModerationActionRestore:
+		hiddenAt := "COALESCE(hidden_at, NOW())"
+		if entry.Action == model.ModerationActionRestore {
+			hiddenAt = "NULL"
+		}
+
+		tag, err := tx.Exec(ctx, `UPDATE `+table+` SET hidden_at = `+hiddenAt+` WHERE id = $1 AND deleted_at IS NULL`, entry.Target.ID)
+		if err != nil {
+			return model.ModerationLogEntry{}, fmt.Errorf("ApplyModerationAction - update hidden - %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return model.ModerationLogEntry{}, model.ErrNotFound
+		}
+	case model.ModerationActionDelete:
+		if entry.Target.Type == model.ReactionTargetPost {
+			err = deletePost(ctx, tx, entry.Target.ID, entry.ModeratorUUID)
+		} else {
+			err = deleteComment(ctx, tx, entry.Target.ID, entry.ModeratorUUID)
+		}
+		if err != nil {
+			return model.ModerationLogEntry{}, err
+		}
+	}
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE reports
+		SET resolved_at = NOW()
+		WHERE target_type = $1 AND target_id = $2 AND resolved_at IS NULL`,
+		entry.Target.Type, entry.Target.ID,
+	)
+	if err != nil {
+		return model.ModerationLogEntry{}, fmt.Errorf("ApplyModerationAction - resolve reports - %w", err)
+	}
+
+	rows, err := tx.Query(ctx, `
+		INSERT INTO moderation_log (target_type, target_id, action, moderator_uuid, author_uuid, note, reports_resolved)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING `+moderationLogColumns,
+		entry.Target.Type, entry.Target.ID, entry.Action, entry.ModeratorUUID, nullUUID(entry.AuthorUUID), entry.Note, tag.RowsAffected(),
+	)
+	if err != nil {
+		return model.ModerationLogEntry{}, fmt.Errorf("ApplyModerationAction - insert log - %w", err)
+	}
+
+	created, err := pgx.CollectExactlyOneRow(rows, scanModerationLogEntry)
+	if err != nil {
+		return model.ModerationLogEntry{}, fmt.Errorf("ApplyModerationAction - CollectExactlyOneRow - %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return model.ModerationLogEntry{}, fmt.Errorf("ApplyModerationAction - Commit - %w", err)
+	}
+
+	return created, nil
+}
+
+// ListModerationLog возвращает записи журнала от новых к старым. beforeID = 0 — первая страница.
+func (s *Store) ListModerationLog(ctx context.Context, filter model.ModerationLogFilter, beforeID int64, limit int) ([]model.ModerationLogEntry, error) {
+	var (
+		where  []string
+		args   []any
+		argPos = func(v any) string {
+			args = append(args, v)
+			return "$" + strconv.Itoa(len(args))
+		}
+	)
+
+	if filter.Target.Type != "" {
+		where = append(where, "target_type = "+argPos(filter.Target.Type), "target_id = "+argPos(filter.Target.ID))
+	}
+	if filter.AuthorUUID != uuid.Nil {
+		where = append(where, "author_uuid = "+argPos(filter.AuthorUUID))
+	}
+	if beforeID > 0 {
+		where = append(where, "id < "+argPos(beforeID))
+	}
+
+	query := `SELECT ` + moderationLogColumns + ` FROM moderation_log`
+	if len(where) > 0 {
+		query += ` WHERE ` + strings.Join(where, " AND ")
+	}
+	query += ` ORDER BY id DESC LIMIT ` + argPos(limit)
+
+	rows, err := s.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ListModerationLog - Query - %w", err)
+	}
+
+	entries, err := pgx.CollectRows(rows, scanModerationLogEntry)
+	if err != nil {
+		return nil, fmt.Errorf("ListModerationLog - CollectRows - %w", err)
+	}
+
+	return entries, nil
+}
+
+func scanModerationLogEntry(row pgx.CollectableRow) (model.ModerationLogEntry, error) {
+	var entry model.ModerationLogEntry
+
+	err := row.Scan(
+		&entry.ID,
+		&entry.Target.Type,
+		&entry.Target.ID,
+		&entry.Action,
+		&entry.ModeratorUUID,
+		&entry.AuthorUUID,
+		&entry.Note,
+		&entry.ReportsResolved,
+		&entry.CreatedAt,
+	)
+
+	return entry, err
+}
@@ -0,0 +1,193 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// notificationsChannel канал NOTIFY, в который триггер notifications_notify
+// пишет получателя нового уведомления.
+const notificationsChannel = "notifications"
+
+const notificationColumns = `id, user_uuid, type, actor_uuid, COALESCE(post_id, 0), COALESCE(comment_id, 0), created_at, read_at`
+
+// CreateNotifications сохраняет уведомления одним запросом. Уведомления
+// типов, которые получатель отключил, пропускаются.
+func (s *Store) CreateNotifications(ctx context.Context, notifications []model.Notification) (int64, error) {
+	var (
+		users    = make([]uuid.UUID, 0, len(notifications))
+		types    = make([]string, 0, len(notifications))
+		actors   = make([]uuid.UUID, 0, len(notifications))
+		posts    = make([]int64, 0, len(notifications))
+		comments = make([]int64, 0, len(notifications))
+	)
+	for _, n := range notifications {
+		users = append(users, n.UserUUID)
+		types = append(types, n.Type)
+		actors = append(actors, n.ActorUUID)
+		posts = append(posts, n.PostID)
+		comments = append(comments, n.CommentID)
+	}
+
+	tag, err := s.db.Exec(ctx, `
+		INSERT INTO notifications (user_uuid, type, actor_uuid, post_id, comment_id)
+		SELECT n.user_uuid, n.type, n.actor_uuid, NULLIF(n.post_id, 0), NULLIF(n.comment_id, 0)
+		FROM unnest($1::uuid[], $2::varchar[], $3::uuid[], $4::integer[], $5::integer[])
+		         AS n(user_uuid, type, actor_uuid, post_id, comment_id)
+		WHERE NOT EXISTS (SELECT 1 FROM notification_mutes m WHERE m.user_uuid = n.user_uuid AND m.type = n.type)`,
+		users, types, actors, posts, comments,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("CreateNotifications - Exec - %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// ListNotifications уведомления пользователя от новых к старым.
+func (s *Store) ListNotifications(ctx context.Context, userUUID uuid.UUID, unreadOnly bool, beforeID int64, limit int) ([]model.Notification, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT `+notificationColumns+`
+		FROM notifications
+		WHERE user_uuid = $1
+		  AND (NOT $2 OR read_at IS NULL)
+		  AND ($3 = 0 OR id < $3)
+		ORDER BY id DESC
+		LIMIT $4`,
+		userUUID, unreadOnly, beforeID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ListNotifications - Query - %w", err)
+	}
+
+	notifications, err := pgx.CollectRows(rows, scanNotification)
+	if err != nil {
+		return nil, fmt.Errorf("ListNotifications - CollectRows - %w", err)
+	}
+
+	return notifications, nil
+}
+
+// ListNotificationsAfter уведомления пользователя новее afterID от старых к новым.
+func (s *Store) ListNotificationsAfter(ctx context.Context, userUUID uuid.UUID, afterID int64, limit int) ([]model.Notification, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT `+notificationColumns+`
+		FROM notifications
+		WHERE user_uuid = $1 AND id > $2
+		ORDER BY id
+		LIMIT $3`,
+		userUUID, afterID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ListNotificationsAfter - Query - %w", err)
+	}
+
+	notifications, err := pgx.CollectRows(rows, scanNotification)
+	if err != nil {
+		return nil, fmt.Errorf("ListNotificationsAfter - CollectRows - %w", err)
+	}
+
+	return notifications, nil
+}
+
+// GetLatestNotificationID идентификатор последнего уведомления пользователя, 0 — уведомлений нет.
+func (s *Store) GetLatestNotificationID(ctx context.Context, userUUID uuid.UUID) (int64, error) {
+	var id int64
+	err := s.db.QueryRow(ctx, `SELECT COALESCE(MAX(id), 0) FROM notifications WHERE user_uuid = $1`, userUUID).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("GetLatestNotificationID - QueryRow - %w", err)
+	}
+
+	return id, nil
+}
+
+func (s *Store) CountUnreadNotifications(ctx context.Context, userUUID uuid.UUID) (int64, error) {
+	var count int64
+	err := s.db.QueryRow(ctx, `SELECT COUNT(*) FROM notifications WHERE user_uuid = $1 AND read_at IS NULL`, userUUID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("CountUnreadNotifications - QueryRow - %w", err)
+	}
+
+	return count, nil
+}
+
+// MarkNotificationsRead отмечает прочитанными уведомления ids, а с all — все
+// уведомления пользователя. Чужие идентификаторы пропускаются.
+func (s *Store) MarkNotificationsRead(ctx context.Context, userUUID uuid.UUID, ids []int64, all bool) (int64, error) {
+	tag, err := s.db.Exec(ctx, `
+		UPDATE notifications
+		SET read_at = NOW()
+		WHERE user_uuid = $1 AND read_at IS NULL AND ($2 OR id = ANY($3))`,
+		userUUID, all, ids,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("MarkNotificationsRead - Exec - %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+func scanNotification(row pgx.CollectableRow) (model.Notification, error) {
+	var n model.Notification
+	err := row.Scan(&n.ID, &n.UserUUID, &n.Type, &n.ActorUUID, &n.PostID, &n.CommentID, &n.CreatedAt, &n.ReadAt)
+	return n, err
+}
+
+func (s *Store) GetNotificationMutes(ctx context.Context, userUUID uuid.UUID) ([]string, error) {
+	rows, err := s.db.Query(ctx, `SELECT type FROM notification_mutes WHERE user_uuid = $1 ORDER BY type`, userUUID)
+	if err != nil {
+		return nil, fmt.Errorf("GetNotificationMutes - Query - %w", err)
+	}
+
+	types, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("GetNotificationMutes - CollectRows - %w", err)
+	}
+
+	return types, nil
+}
+
+// SetNotificationMutes заменяет набор отключённых типов уведомлений.
+func (s *Store) SetNotificationMutes(ctx context.Context, userUUID uuid.UUID, types []string) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("SetNotificationMutes - Begin - %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	if _, err = tx.Exec(ctx, `DELETE FROM notification_mutes WHERE user_uuid = $1 AND type <> ALL($2)`, userUUID, types); err != nil {
+		return fmt.Errorf("SetNotificationMutes - delete - %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO notification_mutes (user_uuid, type)
+		SELECT $1, unnest($2::varchar[])
+		ON CONFLICT DO NOTHING`,
+		userUUID, types,
+	)
+	if err != nil {
+		return fmt.Errorf("SetNotificationMutes - insert - %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("SetNotificationMutes - Commit - %w", err)
+	}
+
+	return nil
+}
+
+// ListenNotifications держит отдельное соединение с LISTEN на канале
+// уведомлений и вызывает notify с получателем каждого нового уведомления.
+// Возвращается при отмене ctx или потере соединения; уведомления, пришедшие
+// без соединения, теряются, поэтому после ошибки слушатели должны
+// перечитать состояние сами.
+func (s *Store) ListenNotifications(ctx context.Context, listening func(), notify func(userUUID uuid.UUID)) error {
+	return s.listen(ctx, notificationsChannel, listening, func(payload string) {
+		if userUUID, err := uuid.Parse(payload); err == nil {
+			notify(userUUID)
+		}
+	})
+}
@@ -0,0 +1,392 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const postColumns = `p.id, p.title, p.content, p.comments_enabled, p.author_uuid, p.created_at,
+	p.status, p.publish_at, p.unpublish_at, p.published_at, p.version,
+	p.deleted_at, COALESCE(p.deleted_by, uuid_nil()), p.hidden_at,
+	p.author_uuid IN (` + shadowBannedUsers + `),
+	p.content_html, p.content_html_version`
+
+// postSchedulerLockKey ключ advisory-блокировки планировщика публикаций:
+// за один проход очереди берётся только один экземпляр сервиса.
+const postSchedulerLockKey int64 = 0x706f7374_73636864
+
+// CreatePost сохраняет пост вместе с тегами, упоминаниями и первой ревизией в одной транзакции.
+// Пост, задержанный правилом контент-политики (held != nil), сохраняется скрытым
+// и попадает в очередь модерации.
+func (s *Store) CreatePost(ctx context.Context, post model.Post, held *model.PolicyDecision) (model.Post, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return model.Post{}, fmt.Errorf("CreatePost - Begin - %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	rows, err := tx.Query(ctx, `
+		INSERT INTO posts AS p (title, content, comments_enabled, author_uuid, status, publish_at, unpublish_at, published_at, hidden_at,
+		                        content_html, content_html_version)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, CASE WHEN $5 = 'published' THEN NOW() END, CASE WHEN $8 THEN NOW() END, $9, $10)
+		RETURNING `+postColumns,
+		post.Title, post.Content, post.CommentsEnabled, post.AuthorUUID, post.Status, post.PublishAt, post.UnpublishAt, held != nil,
+		post.ContentHTML, post.ContentHTMLVersion,
+	)
+	if err != nil {
+		return model.Post{}, fmt.Errorf("CreatePost - Query - %w", err)
+	}
+
+	created, err := pgx.CollectExactlyOneRow(rows, scanPost)
+	if err != nil {
+		return model.Post{}, fmt.Errorf("CreatePost - CollectExactlyOneRow - %w", err)
+	}
+
+	if err = setPostTags(ctx, tx, created.ID, post.Tags); err != nil {
+		return model.Post{}, err
+	}
+
+	if err = setPostMentions(ctx, tx, created.ID, post.Mentions); err != nil {
+		return model.Post{}, err
+	}
+
+	if err = insertRevision(ctx, tx, created, created.AuthorUUID, 0); err != nil {
+		return model.Post{}, err
+	}
+
+	if held != nil {
+		target := model.ModerationTarget{Type: model.ReactionTargetPost, ID: created.ID}
+		if err = insertPolicyReport(ctx, tx, target, held.RuleID); err != nil {
+			return model.Post{}, err
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return model.Post{}, fmt.Errorf("CreatePost - Commit - %w", err)
+	}
+
+	created.Tags = post.Tags
+	created.Mentions = post.Mentions
+
+	return created, nil
+}
+
+// UpdatePost заменяет содержимое, теги и упоминания поста, если его версия всё ещё равна
+// post.Version, и сохраняет новую ревизию. Если пост успели изменить,
+// возвращает model.ErrConflict. restoredFrom = 0 для обычной правки.
+func (s *Store) UpdatePost(ctx context.Context, post model.Post, editorUUID uuid.UUID, restoredFrom int32) (model.Post, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return model.Post{}, fmt.Errorf("UpdatePost - Begin - %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	rows, err := tx.Query(ctx, `
+		UPDATE posts AS p
+		SET title = $2, content = $3, comments_enabled = $4, version = p.version + 1,
+		    content_html = $6, content_html_version = $7
+		WHERE p.id = $1 AND p.version = $5 AND p.deleted_at IS NULL
+		RETURNING `+postColumns,
+		post.ID, post.Title, post.Content, post.CommentsEnabled, post.Version, post.ContentHTML, post.ContentHTMLVersion,
+	)
+	if err != nil {
+		return model.Post{}, fmt.Errorf("UpdatePost - Query - %w", err)
+	}
+
+	updated, err := pgx.CollectExactlyOneRow(rows, scanPost)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.Post{}, s.versionMismatch(ctx, post.ID)
+		}
+		return model.Post{}, fmt.Errorf("UpdatePost - CollectExactlyOneRow - %w", err)
+	}
+
+	if err = setPostTags(ctx, tx, updated.ID, post.Tags); err != nil {
+		return model.Post{}, err
+	}
+
+	if err = setPostMentions(ctx, tx, updated.ID, post.Mentions); err != nil {
+		return model.Post{}, err
+	}
+
+	if err = insertRevision(ctx, tx, updated, editorUUID, restoredFrom); err != nil {
+		return model.Post{}, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return model.Post{}, fmt.Errorf("UpdatePost - Commit - %w", err)
+	}
+
+	updated.Tags = post.Tags
+	updated.Mentions = post.Mentions
+
+	return updated, nil
+}
+
+// versionMismatch отличает удалённый пост от поста, который изменили параллельно.
+func (s *Store) versionMismatch(ctx context.Context, id int64) error {
+	var exists bool
+	if err := s.db.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM posts WHERE id = $1 AND deleted_at IS NULL)`, id).Scan(&exists); err != nil {
+		return fmt.Errorf("versionMismatch - QueryRow - %w", err)
+	}
+
+	if !exists {
+		return model.ErrNotFound
+	}
+
+	return fmt.Errorf("%w: post was modified by someone else, reload and try again", model.ErrConflict)
+}
+
+// SavePostContentHTML сохраняет HTML, перестроенный новой версией рендерера.
+// Если текст поста успели изменить или HTML уже перестроен, ничего не делает.
+func (s *Store) SavePostContentHTML(ctx context.Context, post model.Post) error {
+	_, err := s.db.Exec(ctx, `
+		UPDATE posts
+		SET content_html = $3, content_html_version = $4
+		WHERE id = $1 AND version = $2 AND content_html_version < $4`,
+		post.ID, post.Version, post.ContentHTML, post.ContentHTMLVersion,
+	)
+	if err != nil {
+		return fmt.Errorf("SavePostContentHTML - Exec - %w", err)
+	}
+
+	return nil
+}
+
+// GetPost возвращает пост, если он не удалён. Удалённые посты доступны через GetDeletedPost.
+func (s *Store) GetPost(ctx context.Context, id int64) (model.Post, error) {
+	rows, err := s.db.Query(ctx, `SELECT `+postColumns+` FROM posts p WHERE p.id = $1 AND p.deleted_at IS NULL`, id)
+	if err != nil {
+		return model.Post{}, fmt.Errorf("GetPost - Query - %w", err)
+	}
+
+	post, err := pgx.CollectExactlyOneRow(rows, scanPost)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.Post{}, model.ErrNotFound
+		}
+		return model.Post{}, fmt.Errorf("GetPost - CollectExactlyOneRow - %w", err)
+	}
+
+	return post, nil
+}
+
+// ListPosts возвращает посты от новых к старым. Опубликованные посты упорядочены
+// по времени публикации и листаются по паре (before, beforeID), остальные — по id.
+// before = nil и beforeID = 0 означают первую страницу.
+func (s *Store) ListPosts(ctx context.Context, filter model.PostFilter, before *time.Time, beforeID int64, limit int) ([]model.Post, error) {
+	var (
+		joins  string
+		where  []string
+		args   []any
+		argPos = func(v any) string {
+			args = append(args, v)
+			return "$" + strconv.Itoa(len(args))
+		}
+	)
+
+	status := filter.Status
+	if status == "" {
+		status = model.PostStatusPublished
+	}
+	where = append(where, "p.deleted_at IS NULL", "p.status = "+argPos(status))
+
+	if !filter.IncludeHidden {
+		where = append(where, "p.hidden_at IS NULL",
+			"(p.author_uuid NOT IN ("+shadowBannedUsers+") OR p.author_uuid = "+argPos(filter.Viewer)+")")
+	}
+	if filter.TagSlug != "" {
+		joins = ` JOIN post_tags pt ON pt.post_id = p.id JOIN tags t ON t.id = pt.tag_id`
+		where = append(where, "t.slug = "+argPos(filter.TagSlug))
+	}
+	if filter.AuthorUUID != uuid.Nil {
+		where = append(where, "p.author_uuid = "+argPos(filter.AuthorUUID))
+	}
+	if filter.CreatedAfter != nil {
+		where = append(where, "p.created_at >= "+argPos(*filter.CreatedAfter))
+	}
+	if filter.CreatedBefore != nil {
+		where = append(where, "p.created_at < "+argPos(*filter.CreatedBefore))
+	}
+
+	order := "p.id DESC"
+	if status == model.PostStatusPublished {
+		order = "p.published_at DESC, p.id DESC"
+		if before != nil {
+			where = append(where, "(p.published_at, p.id) < ("+argPos(*before)+", "+argPos(beforeID)+")")
+		}
+	} else if beforeID > 0 {
+		where = append(where, "p.id < "+argPos(beforeID))
+	}
+
+	query := `SELECT ` + postColumns + ` FROM posts p` + joins +
+		` WHERE ` + strings.Join(where, " AND ") +
+		` ORDER BY ` + order + ` LIMIT ` + argPos(limit)
+
+	rows, err := s.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ListPosts - Query - %w", err)
+	}
+
+	posts, err := pgx.CollectRows(rows, scanPost)
+	if err != nil {
+		return nil, fmt.Errorf("ListPosts - CollectRows - %w", err)
+	}
+
+	return posts, nil
+}
+
+func scanPost(row pgx.CollectableRow) (model.Post, error) {
+	var post model.Post
+
+	err := row.Scan(postFields(&post)...)
+
+	return post, err
+}
+
+// postFields приёмники для колонок postColumns.
+func postFields(post *model.Post) []any {
+	return []any{
+		&post.ID,
+		&post.Title,
+		&post.Content,
+		&post.CommentsEnabled,
+		&post.AuthorUUID,
+		&post.CreatedAt,
+		&post.Status,
+		&post.PublishAt,
+		&post.UnpublishAt,
+		&post.PublishedAt,
+		&post.Version,
+		&post.DeletedAt,
+		&post.DeletedBy,
+		&post.HiddenAt,
+		&post.AuthorShadowBanned,
+		&post.ContentHTML,
+		&post.ContentHTMLVersion,
+	}
+}
+
+// TransitionPost переводит пост в новый статус, если текущий статус входит в from.
+func (s *Store) TransitionPost(ctx context.Context, id int64, from []string, to model.Post) (model.Post, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return model.Post{}, fmt.Errorf("TransitionPost - Begin - %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	var current string
+	err = tx.QueryRow(ctx, `SELECT status FROM posts WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, id).Scan(&current)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.Post{}, model.ErrNotFound
+		}
+		return model.Post{}, fmt.Errorf("TransitionPost - select - %w", err)
+	}
+
+	if !slices.Contains(from, current) {
+		return model.Post{}, fmt.Errorf("%w: post is %s", model.ErrInvalidArgument, current)
+	}
+
+	rows, err := tx.Query(ctx, `
+		UPDATE posts AS p
+		SET status       = $2,
+		    publish_at   = $3,
+		    unpublish_at = $4,
+		    published_at = CASE WHEN $2 = 'published' AND p.status <> 'published' THEN NOW() ELSE p.published_at END
+		WHERE p.id = $1
+		RETURNING `+postColumns,
+		id, to.Status, to.PublishAt, to.UnpublishAt,
+	)
+	if err != nil {
+		return model.Post{}, fmt.Errorf("TransitionPost - Query - %w", err)
+	}
+
+	updated, err := pgx.CollectExactlyOneRow(rows, scanPost)
+	if err != nil {
+		return model.Post{}, fmt.Errorf("TransitionPost - CollectExactlyOneRow - %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return model.Post{}, fmt.Errorf("TransitionPost - Commit - %w", err)
+	}
+
+	return updated, nil
+}
+
+// ApplyPostSchedule публикует запланированные посты и снимает с публикации
+// посты с истёкшим unpublish_at, не больше batch каждого вида за вызов.
+// Если другой экземпляр уже обрабатывает очередь, возвращает locked = false.
+func (s *Store) ApplyPostSchedule(ctx context.Context, batch int) (published, archived int, locked bool, err error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("ApplyPostSchedule - Begin - %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	if err = tx.QueryRow(ctx, `SELECT pg_try_advisory_xact_lock($1)`, postSchedulerLockKey).Scan(&locked); err != nil {
+		return 0, 0, false, fmt.Errorf("ApplyPostSchedule - lock - %w", err)
+	}
+	if !locked {
+		return 0, 0, false, nil
+	}
+
+	// Временем публикации считается запланированное, а не момент срабатывания
+	// планировщика, чтобы порядок в ленте не зависел от его задержки.
+	publishedIDs, err := collectIDs(ctx, tx, `
+		UPDATE posts
+		SET status = 'published', published_at = publish_at
+		WHERE id IN (
+			SELECT id FROM posts
+			WHERE status = 'scheduled' AND publish_at <= NOW() AND deleted_at IS NULL
+			ORDER BY publish_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id`,
+		batch,
+	)
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("ApplyPostSchedule - publish - %w", err)
+	}
+
+	archivedIDs, err := collectIDs(ctx, tx, `
+		UPDATE posts
+		SET status = 'archived'
+		WHERE id IN (
+			SELECT id FROM posts
+			WHERE status = 'published' AND unpublish_at <= NOW() AND deleted_at IS NULL
+			ORDER BY unpublish_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id`,
+		batch,
+	)
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("ApplyPostSchedule - archive - %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return 0, 0, false, fmt.Errorf("ApplyPostSchedule - Commit - %w", err)
+	}
+
+	return len(publishedIDs), len(archivedIDs), true, nil
+}
+
+func collectIDs(ctx context.Context, tx pgx.Tx, query string, args ...any) ([]int64, error) {
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowTo[int64])
+}
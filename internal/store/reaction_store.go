@@ -0,0 +1,158 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"time"
+)
+
+// AddReaction добавляет реакцию и увеличивает счётчик в одном запросе.
+// Повторная реакция не меняет ни строку, ни счётчик; при параллельных
+// вставках уникальный ключ пропускает только одну.
+func (s *Store) AddReaction(ctx context.Context, userUUID uuid.UUID, target model.ReactionTarget, kind string) (bool, error) {
+	tag, err := s.db.Exec(ctx, `
+		WITH inserted AS (
+			INSERT INTO reactions (user_uuid, target_type, target_id, kind)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT DO NOTHING
+			RETURNING target_type, target_id, kind
+		)
+		INSERT INTO reaction_counts (target_type, target_id, kind, count)
+		SELECT target_type, target_id, kind, 1 FROM inserted
+		ON CONFLICT (target_type, target_id, kind) DO UPDATE
+			SET count = reaction_counts.count + 1`,
+		userUUID, target.Type, target.ID, kind,
+	)
+	if err != nil {
+		if isPgError(err, foreignKeyViolation) {
+			return false, model.ErrNotFound
+		}
+		return false, fmt.Errorf("AddReaction - Exec - %w", err)
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
+
+// RemoveReaction удаляет реакцию и уменьшает счётчик, только если строка
+// действительно была удалена этим запросом.
+func (s *Store) RemoveReaction(ctx context.Context, userUUID uuid.UUID, target model.ReactionTarget, kind string) (bool, error) {
+	tag, err := s.db.Exec(ctx, `
+		WITH deleted AS (
+			DELETE FROM reactions
+			WHERE user_uuid = $1 AND target_type = $2 AND target_id = $3 AND kind = $4
+			RETURNING target_type, target_id, kind
+		)
+		UPDATE reaction_counts c
+		SET count = c.count - 1
+		FROM deleted d
+		WHERE c.target_type = d.target_type AND c.target_id = d.target_id AND c.kind = d.kind`,
+		userUUID, target.Type, target.ID, kind,
+	)
+	if err != nil {
+		return false, fmt.Errorf("RemoveReaction - Exec - %w", err)
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
+
+// GetReactionCounts возвращает ненулевые счётчики для набора объектов одного типа.
+func (s *Store) GetReactionCounts(ctx context.Context, targetType string, ids []int64) (map[int64]map[string]int64, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT target_id, kind, count
+		FROM reaction_counts
+		WHERE target_type = $1 AND target_id = ANY($2) AND count > 0`,
+		targetType, ids,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("GetReactionCounts - Query - %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[int64]map[string]int64, len(ids))
+	for rows.Next() {
+		var (
+			id    int64
+			kind  string
+			count int64
+		)
+		if err = rows.Scan(&id, &kind, &count); err != nil {
+			return nil, fmt.Errorf("GetReactionCounts - Scan - %w", err)
+		}
+
+		if counts[id] == nil {
+			counts[id] = make(map[string]int64)
+		}
+		counts[id][kind] = count
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("GetReactionCounts - rows.Err - %w", err)
+	}
+
+	return counts, nil
+}
+
+func (s *Store) GetUserReactions(ctx context.Context, userUUID uuid.UUID, targetType string, ids []int64) (map[int64][]string, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT target_id, kind
+		FROM reactions
+		WHERE user_uuid = $1 AND target_type = $2 AND target_id = ANY($3)
+		ORDER BY kind`,
+		userUUID, targetType, ids,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("GetUserReactions - Query - %w", err)
+	}
+	defer rows.Close()
+
+	mine := make(map[int64][]string)
+	for rows.Next() {
+		var (
+			id   int64
+			kind string
+		)
+		if err = rows.Scan(&id, &kind); err != nil {
+			return nil, fmt.Errorf("GetUserReactions - Scan - %w", err)
+		}
+
+		mine[id] = append(mine[id], kind)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("GetUserReactions - rows.Err - %w", err)
+	}
+
+	return mine, nil
+}
+
+// ListReactions возвращает реакции от новых к старым. Курсор — пара
+// (created_at, user_uuid) последней реакции предыдущей страницы.
+func (s *Store) ListReactions(ctx context.Context, target model.ReactionTarget, kind string, before *time.Time, beforeUser uuid.UUID, limit int) ([]model.Reaction, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT user_uuid, target_type, target_id, kind, created_at
+		FROM reactions
+		WHERE target_type = $1 AND target_id = $2
+		  AND ($3 = '' OR kind = $3)
+		  AND ($4::timestamptz IS NULL OR (created_at, user_uuid) < ($4, $5))
+		ORDER BY created_at DESC, user_uuid DESC
+		LIMIT $6`,
+		target.Type, target.ID, kind, before, beforeUser, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ListReactions - Query - %w", err)
+	}
+
+	reactions, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.Reaction, error) {
+		var r model.Reaction
+		err := row.Scan(&r.UserUUID, &r.Target.Type, &r.Target.ID, &r.Kind, &r.CreatedAt)
+		return r, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ListReactions - CollectRows - %w", err)
+	}
+
+	return reactions, nil
+}
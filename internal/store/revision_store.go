@@ -0,0 +1,85 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const revisionColumns = `post_id, version, title, content, COALESCE(editor_uuid, uuid_nil()), COALESCE(restored_from, 0), created_at`
+
+func insertRevision(ctx context.Context, tx pgx.Tx, post model.Post, editorUUID uuid.UUID, restoredFrom int32) error {
+	_, err := tx.Exec(ctx, `
+		INSERT INTO post_revisions (post_id, version, title, content, editor_uuid, restored_from)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, 0))`,
+		post.ID, post.Version, post.Title, post.Content, editorUUID, restoredFrom,
+	)
+	if err != nil {
+		return fmt.Errorf("insertRevision - Exec - %w", err)
+	}
+
+	return nil
+}
+
+func (s *Store) GetRevision(ctx context.Context, postID int64, version int32) (model.PostRevision, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT `+revisionColumns+`
+		FROM post_revisions
+		WHERE post_id = $1 AND version = $2`,
+		postID, version,
+	)
+	if err != nil {
+		return model.PostRevision{}, fmt.Errorf("GetRevision - Query - %w", err)
+	}
+
+	revision, err := pgx.CollectExactlyOneRow(rows, scanRevision)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.PostRevision{}, model.ErrNotFound
+		}
+		return model.PostRevision{}, fmt.Errorf("GetRevision - CollectExactlyOneRow - %w", err)
+	}
+
+	return revision, nil
+}
+
+// ListRevisions возвращает ревизии от новых к старым. beforeVersion = 0 означает первую страницу.
+func (s *Store) ListRevisions(ctx context.Context, postID int64, beforeVersion int32, limit int) ([]model.PostRevision, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT `+revisionColumns+`
+		FROM post_revisions
+		WHERE post_id = $1 AND ($2 = 0 OR version < $2)
+		ORDER BY version DESC
+		LIMIT $3`,
+		postID, beforeVersion, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ListRevisions - Query - %w", err)
+	}
+
+	revisions, err := pgx.CollectRows(rows, scanRevision)
+	if err != nil {
+		return nil, fmt.Errorf("ListRevisions - CollectRows - %w", err)
+	}
+
+	return revisions, nil
+}
+
+func scanRevision(row pgx.CollectableRow) (model.PostRevision, error) {
+	var revision model.PostRevision
+
+	err := row.Scan(
+		&revision.PostID,
+		&revision.Version,
+		&revision.Title,
+		&revision.Content,
+		&revision.EditorUUID,
+		&revision.RestoredFrom,
+		&revision.CreatedAt,
+	)
+
+	return revision, err
+}
@@ -0,0 +1,107 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+func (s *Store) GetUserRoles(ctx context.Context, userUUID uuid.UUID) ([]string, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT r.name
+		FROM user_roles ur
+			JOIN roles r ON r.id = ur.role_id
+		WHERE ur.user_uuid = $1
+		ORDER BY r.name`,
+		userUUID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("GetUserRoles - Query - %w", err)
+	}
+
+	roles, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("GetUserRoles - CollectRows - %w", err)
+	}
+
+	return roles, nil
+}
+
+func (s *Store) GetUserPermissions(ctx context.Context, userUUID uuid.UUID) ([]string, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT DISTINCT p.name
+		FROM user_roles ur
+			JOIN role_permissions rp ON rp.role_id = ur.role_id
+			JOIN permissions p ON p.id = rp.permission_id
+		WHERE ur.user_uuid = $1
+		ORDER BY p.name`,
+		userUUID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("GetUserPermissions - Query - %w", err)
+	}
+
+	permissions, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("GetUserPermissions - CollectRows - %w", err)
+	}
+
+	return permissions, nil
+}
+
+func (s *Store) AssignRole(ctx context.Context, userUUID uuid.UUID, role string) error {
+	tag, err := s.db.Exec(ctx, `
+		INSERT INTO user_roles (user_uuid, role_id)
+		SELECT $1, id FROM roles WHERE name = $2
+		ON CONFLICT DO NOTHING`,
+		userUUID, role,
+	)
+	if err != nil {
+		if isPgError(err, foreignKeyViolation) {
+			return model.ErrNotFound
+		}
+		return fmt.Errorf("AssignRole - Exec - %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		exists, err := s.roleExists(ctx, role)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return model.ErrNotFound
+		}
+	}
+
+	return nil
+}
+
+func (s *Store) RevokeRole(ctx context.Context, userUUID uuid.UUID, role string) error {
+	tag, err := s.db.Exec(ctx, `
+		DELETE FROM user_roles
+		WHERE user_uuid = $1 AND role_id = (SELECT id FROM roles WHERE name = $2)`,
+		userUUID, role,
+	)
+	if err != nil {
+		return fmt.Errorf("RevokeRole - Exec - %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return model.ErrNotFound
+	}
+
+	return nil
+}
+
+func (s *Store) roleExists(ctx context.Context, role string) (bool, error) {
+	var exists bool
+
+	err := s.db.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM roles WHERE name = $1)`, role).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("roleExists - Scan - %w", err)
+	}
+
+	return exists, nil
+}
@@ -0,0 +1,153 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/jackc/pgx/v5"
+	"strings"
+)
+
+// tagPostsCount число постов тега t в публичной выдаче. Счётчик не хранится
+// в tags, а считается при чтении с тем же условием видимости, что у ListPosts.
+const tagPostsCount = `(
+	SELECT COUNT(*)
+	FROM post_tags tp
+	JOIN posts p ON p.id = tp.post_id
+	WHERE tp.tag_id = t.id AND p.status = 'published' AND p.deleted_at IS NULL AND p.hidden_at IS NULL
+	  AND p.author_uuid NOT IN (` + shadowBannedUsers + `))`
+
+// setPostTags приводит теги поста к заданному набору.
+func setPostTags(ctx context.Context, tx pgx.Tx, postID int64, tags []model.Tag) error {
+	slugs := make([]string, 0, len(tags))
+	names := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		slugs = append(slugs, tag.Slug)
+		names = append(names, tag.Name)
+	}
+
+	_, err := tx.Exec(ctx, `
+		INSERT INTO tags (slug, name)
+		SELECT * FROM unnest($1::varchar[], $2::varchar[])
+		ON CONFLICT (slug) DO NOTHING`,
+		slugs, names,
+	)
+	if err != nil {
+		return fmt.Errorf("setPostTags - insert tags - %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		DELETE FROM post_tags pt
+		USING tags t
+		WHERE pt.post_id = $1 AND pt.tag_id = t.id AND NOT (t.slug = ANY($2))`,
+		postID, slugs,
+	)
+	if err != nil {
+		return fmt.Errorf("setPostTags - remove - %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO post_tags (post_id, tag_id)
+		SELECT $1, id FROM tags WHERE slug = ANY($2)
+		ON CONFLICT DO NOTHING`,
+		postID, slugs,
+	)
+	if err != nil {
+		return fmt.Errorf("setPostTags - add - %w", err)
+	}
+
+	return nil
+}
+
+// GetPostTags возвращает теги для набора постов.
+func (s *Store) GetPostTags(ctx context.Context, postIDs []int64) (map[int64][]model.Tag, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT pt.post_id, t.id, t.slug, t.name, `+tagPostsCount+`
+		FROM post_tags pt
+		JOIN tags t ON t.id = pt.tag_id
+		WHERE pt.post_id = ANY($1)
+		ORDER BY t.slug`,
+		postIDs,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("GetPostTags - Query - %w", err)
+	}
+	defer rows.Close()
+
+	tags := make(map[int64][]model.Tag, len(postIDs))
+	for rows.Next() {
+		var (
+			postID int64
+			tag    model.Tag
+		)
+		if err = rows.Scan(&postID, &tag.ID, &tag.Slug, &tag.Name, &tag.PostsCount); err != nil {
+			return nil, fmt.Errorf("GetPostTags - Scan - %w", err)
+		}
+
+		tags[postID] = append(tags[postID], tag)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("GetPostTags - rows.Err - %w", err)
+	}
+
+	return tags, nil
+}
+
+// SearchTags ищет теги по префиксу slug, сначала самые популярные.
+func (s *Store) SearchTags(ctx context.Context, prefix string, limit int) ([]model.Tag, error) {
+	escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(prefix)
+
+	rows, err := s.db.Query(ctx, `
+		SELECT id, slug, name, posts_count
+		FROM (
+			SELECT t.id, t.slug, t.name, `+tagPostsCount+` AS posts_count
+			FROM tags t
+			WHERE t.slug LIKE $1 || '%'
+		) c
+		WHERE posts_count > 0
+		ORDER BY posts_count DESC, slug
+		LIMIT $2`,
+		escaped, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("SearchTags - Query - %w", err)
+	}
+
+	tags, err := pgx.CollectRows(rows, scanTag)
+	if err != nil {
+		return nil, fmt.Errorf("SearchTags - CollectRows - %w", err)
+	}
+
+	return tags, nil
+}
+
+func (s *Store) ListPopularTags(ctx context.Context, limit int) ([]model.Tag, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT id, slug, name, posts_count
+		FROM (
+			SELECT t.id, t.slug, t.name, `+tagPostsCount+` AS posts_count
+			FROM tags t
+		) c
+		WHERE posts_count > 0
+		ORDER BY posts_count DESC, slug
+		LIMIT $1`,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ListPopularTags - Query - %w", err)
+	}
+
+	tags, err := pgx.CollectRows(rows, scanTag)
+	if err != nil {
+		return nil, fmt.Errorf("ListPopularTags - CollectRows - %w", err)
+	}
+
+	return tags, nil
+}
+
+func scanTag(row pgx.CollectableRow) (model.Tag, error) {
+	var tag model.Tag
+	err := row.Scan(&tag.ID, &tag.Slug, &tag.Name, &tag.PostsCount)
+	return tag, err
+}
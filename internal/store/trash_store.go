@@ -0,0 +1,518 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"time"
+)
+
+// trashPurgeLockKey ключ advisory-блокировки очистки корзины.
+const trashPurgeLockKey int64 = 0x74726173_68707267
+
+// DeletePost помечает пост удалённым.
+func (s *Store) DeletePost(ctx context.Context, id int64, deletedBy uuid.UUID) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("DeletePost - Begin - %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	if err = deletePost(ctx, tx, id, deletedBy); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("DeletePost - Commit - %w", err)
+	}
+
+	return nil
+}
+
+func deletePost(ctx context.Context, tx pgx.Tx, id int64, deletedBy uuid.UUID) error {
+	tag, err := tx.Exec(ctx, `
+		UPDATE posts
+		SET deleted_at = NOW(), deleted_by = $2
+		WHERE id = $1 AND deleted_at IS NULL`,
+		id, deletedBy,
+	)
+	if err != nil {
+		return fmt.Errorf("deletePost - update - %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return model.ErrNotFound
+	}
+
+	return nil
+}
+
+// RestorePost снимает пометку удаления, если пост удалён позже cutoff.
+func (s *Store) RestorePost(ctx context.Context, id int64, cutoff time.Time) (model.Post, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return model.Post{}, fmt.Errorf("RestorePost - Begin - %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	rows, err := tx.Query(ctx, `
+		UPDATE posts AS p
+		SET deleted_at = NULL, deleted_by = NULL
+		WHERE p.id = $1 AND p.deleted_at > $2
+		RETURNING `+postColumns,
+		id, cutoff,
+	)
+	if err != nil {
+		return model.Post{}, fmt.Errorf("RestorePost - Query - %w", err)
+	}
+
+	post, err := pgx.CollectExactlyOneRow(rows, scanPost)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.Post{}, model.ErrNotFound
+		}
+		return model.Post{}, fmt.Errorf("RestorePost - CollectExactlyOneRow - %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return model.Post{}, fmt.Errorf("RestorePost - Commit - %w", err)
+	}
+
+	return post, nil
+}
+
+func (s *Store) GetDeletedPost(ctx context.Context, id int64) (model.Post, error) {
+	rows, err := s.db.Query(ctx, `SELECT `+postColumns+` FROM posts p WHERE p.id = $1 AND p.deleted_at IS NOT NULL`, id)
+	if err != nil {
+		return model.Post{}, fmt.Errorf("GetDeletedPost - Query - %w", err)
+	}
+
+	post, err := pgx.CollectExactlyOneRow(rows, scanPost)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.Post{}, model.ErrNotFound
+		}
+		return model.Post{}, fmt.Errorf("GetDeletedPost - CollectExactlyOneRow - %w", err)
+	}
+
+	return post, nil
+}
+
+// ListDeletedPosts корзина автора: посты, которые он удалил сам после cutoff.
+func (s *Store) ListDeletedPosts(ctx context.Context, authorUUID uuid.UUID, cutoff time.Time, beforeID int64, limit int) ([]model.Post, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT `+postColumns+`
+		FROM posts p
+		WHERE p.author_uuid = $1 AND p.deleted_by = $1 AND p.deleted_at > $2
+		  AND ($3 = 0 OR p.id < $3)
+		ORDER BY p.id DESC
+		LIMIT $4`,
+		authorUUID, cutoff, beforeID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ListDeletedPosts - Query - %w", err)
+	}
+
+	posts, err := pgx.CollectRows(rows, scanPost)
+	if err != nil {
+		return nil, fmt.Errorf("ListDeletedPosts - CollectRows - %w", err)
+	}
+
+	return posts, nil
+}
+
+func (s *Store) DeleteComment(ctx context.Context, id int64, deletedBy uuid.UUID) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("DeleteComment - Begin - %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	if err = deleteComment(ctx, tx, id, deletedBy); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("DeleteComment - Commit - %w", err)
+	}
+
+	return nil
+}
+
+func deleteComment(ctx context.Context, tx pgx.Tx, id int64, deletedBy uuid.UUID) error {
+	tag, err := tx.Exec(ctx, `
+		UPDATE comments
+		SET deleted_at = NOW(), deleted_by = $2
+		WHERE id = $1 AND deleted_at IS NULL`,
+		id, deletedBy,
+	)
+	if err != nil {
+		return fmt.Errorf("deleteComment - Exec - %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return model.ErrNotFound
+	}
+
+	return nil
+}
+
+// RestoreComment снимает пометку удаления, если комментарий удалён позже cutoff
+// и ещё не очищен.
+func (s *Store) RestoreComment(ctx context.Context, id int64, cutoff time.Time) (model.Comment, error) {
+	rows, err := s.db.Query(ctx, `
+		UPDATE comments
+		SET deleted_at = NULL, deleted_by = NULL
+		WHERE id = $1 AND deleted_at > $2 AND author_uuid IS NOT NULL
+		RETURNING `+commentColumns,
+		id, cutoff,
+	)
+	if err != nil {
+		return model.Comment{}, fmt.Errorf("RestoreComment - Query - %w", err)
+	}
+
+	comment, err := pgx.CollectExactlyOneRow(rows, scanComment)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.Comment{}, model.ErrNotFound
+		}
+		return model.Comment{}, fmt.Errorf("RestoreComment - CollectExactlyOneRow - %w", err)
+	}
+
+	return comment, nil
+}
+
+func (s *Store) GetDeletedComment(ctx context.Context, id int64) (model.Comment, error) {
+	rows, err := s.db.Query(ctx, `SELECT `+commentColumns+` FROM comments WHERE id = $1 AND deleted_at IS NOT NULL`, id)
+	if err != nil {
+		return model.Comment{}, fmt.Errorf("GetDeletedComment - Query - %w", err)
+	}
+
+	comment, err := pgx.CollectExactlyOneRow(rows, scanComment)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.Comment{}, model.ErrNotFound
+		}
+		return model.Comment{}, fmt.Errorf("GetDeletedComment - CollectExactlyOneRow - %w", err)
+	}
+
+	return comment, nil
+}
+
+// ListDeletedComments корзина автора: комментарии, которые он удалил сам после cutoff.
+func (s *Store) ListDeletedComments(ctx context.Context, authorUUID uuid.UUID, cutoff time.Time, beforeID int64, limit int) ([]model.Comment, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT `+commentColumns+`
+		FROM comments
+		WHERE author_uuid = $1 AND deleted_by = $1 AND deleted_at > $2
+		  AND ($3 = 0 OR id < $3)
+		ORDER BY id DESC
+		LIMIT $4`,
+		authorUUID, cutoff, beforeID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ListDeletedComments - Query - %w", err)
+	}
+
+	comments, err := pgx.CollectRows(rows, scanComment)
+	if err != nil {
+		return nil, fmt.Errorf("ListDeletedComments - CollectRows - %w", err)
+	}
+
+	return comments, nil
+}
+
+// DeleteUser помечает пользователя удалённым вместе с его постами и комментариями.
+// У всех записей одно и то же время удаления, по нему RestoreUser находит, что
+// вернуть, не трогая удалённое раньше.
+func (s *Store) DeleteUser(ctx context.Context, userUUID uuid.UUID) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("DeleteUser - Begin - %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	tag, err := tx.Exec(ctx, `UPDATE users SET deleted_at = NOW() WHERE uuid = $1 AND deleted_at IS NULL`, userUUID)
+	if err != nil {
+		return fmt.Errorf("DeleteUser - update user - %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return model.ErrNotFound
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE posts
+		SET deleted_at = NOW(), deleted_by = $1
+		WHERE author_uuid = $1 AND deleted_at IS NULL`,
+		userUUID,
+	)
+	if err != nil {
+		return fmt.Errorf("DeleteUser - delete posts - %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE comments
+		SET deleted_at = NOW(), deleted_by = $1
+		WHERE author_uuid = $1 AND deleted_at IS NULL`,
+		userUUID,
+	)
+	if err != nil {
+		return fmt.Errorf("DeleteUser - delete comments - %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("DeleteUser - Commit - %w", err)
+	}
+
+	return nil
+}
+
+// RestoreUser возвращает пользователя, удалённого позже cutoff, и всё, что было
+// удалено вместе с ним.
+func (s *Store) RestoreUser(ctx context.Context, userUUID uuid.UUID, cutoff time.Time) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("RestoreUser - Begin - %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	var deletedAt time.Time
+	err = tx.QueryRow(ctx, `
+		SELECT deleted_at FROM users
+		WHERE uuid = $1 AND deleted_at > $2
+		FOR UPDATE`,
+		userUUID, cutoff,
+	).Scan(&deletedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.ErrNotFound
+		}
+		return fmt.Errorf("RestoreUser - select - %w", err)
+	}
+
+	if _, err = tx.Exec(ctx, `UPDATE users SET deleted_at = NULL WHERE uuid = $1`, userUUID); err != nil {
+		return fmt.Errorf("RestoreUser - update user - %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE posts
+		SET deleted_at = NULL, deleted_by = NULL
+		WHERE author_uuid = $1 AND deleted_by = $1 AND deleted_at = $2`,
+		userUUID, deletedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("RestoreUser - restore posts - %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE comments
+		SET deleted_at = NULL, deleted_by = NULL
+		WHERE author_uuid = $1 AND deleted_by = $1 AND deleted_at = $2`,
+		userUUID, deletedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("RestoreUser - restore comments - %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("RestoreUser - Commit - %w", err)
+	}
+
+	return nil
+}
+
+// PurgeDeleted окончательно удаляет то, что пролежало в корзине дольше cutoff,
+// не больше batch записей каждого вида. Порядок задан внешними ключами:
+// комментарии удаляемых постов, посты, затем пользователи. Комментарии
+// с ответами не удаляются, а обезличиваются, чтобы ветка не распалась.
+// Если очистку уже выполняет другой экземпляр, возвращает locked = false.
+func (s *Store) PurgeDeleted(ctx context.Context, cutoff time.Time, batch int) (result model.PurgeResult, locked bool, err error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return result, false, fmt.Errorf("PurgeDeleted - Begin - %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	if err = tx.QueryRow(ctx, `SELECT pg_try_advisory_xact_lock($1)`, trashPurgeLockKey).Scan(&locked); err != nil {
+		return result, false, fmt.Errorf("PurgeDeleted - lock - %w", err)
+	}
+	if !locked {
+		return result, false, nil
+	}
+
+	rows, err := tx.Query(ctx, `
+		SELECT uuid FROM users
+		WHERE deleted_at < $1
+		ORDER BY deleted_at
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED`,
+		cutoff, batch,
+	)
+	if err != nil {
+		return result, false, fmt.Errorf("PurgeDeleted - select users - %w", err)
+	}
+	users, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		return result, false, fmt.Errorf("PurgeDeleted - collect users - %w", err)
+	}
+
+	// Посты удаляемых пользователей уходят вместе с ними, даже если их успели восстановить.
+	posts, err := collectIDs(ctx, tx, `
+		SELECT id FROM posts
+		WHERE id IN (
+			SELECT id FROM posts
+			WHERE deleted_at < $1
+			ORDER BY deleted_at
+			LIMIT $2
+		) OR author_uuid = ANY($3)
+		FOR UPDATE`,
+		cutoff, batch, users,
+	)
+	if err != nil {
+		return result, false, fmt.Errorf("PurgeDeleted - select posts - %w", err)
+	}
+
+	postComments, err := collectIDs(ctx, tx, `DELETE FROM comments WHERE post_id = ANY($1) RETURNING id`, posts)
+	if err != nil {
+		return result, false, fmt.Errorf("PurgeDeleted - delete post comments - %w", err)
+	}
+
+	if err = purgeReactions(ctx, tx, model.ReactionTargetComment, postComments); err != nil {
+		return result, false, err
+	}
+	if err = purgeReactions(ctx, tx, model.ReactionTargetPost, posts); err != nil {
+		return result, false, err
+	}
+	if err = purgeReports(ctx, tx, model.ReactionTargetComment, postComments); err != nil {
+		return result, false, err
+	}
+	if err = purgeReports(ctx, tx, model.ReactionTargetPost, posts); err != nil {
+		return result, false, err
+	}
+
+	// post_tags, post_revisions и attachments удаляются каскадом.
+	if _, err = tx.Exec(ctx, `DELETE FROM posts WHERE id = ANY($1)`, posts); err != nil {
+		return result, false, fmt.Errorf("PurgeDeleted - delete posts - %w", err)
+	}
+
+	// Комментарии удаляемых пользователей к чужим постам обезличиваются и
+	// дальше живут по общим правилам корзины.
+	_, err = tx.Exec(ctx, `
+		UPDATE comments
+		SET author_uuid = NULL, content = '', deleted_at = COALESCE(deleted_at, NOW()), deleted_by = NULL
+		WHERE author_uuid = ANY($1)`,
+		users,
+	)
+	if err != nil {
+		return result, false, fmt.Errorf("PurgeDeleted - scrub user comments - %w", err)
+	}
+
+	comments, err := collectIDs(ctx, tx, `
+		DELETE FROM comments
+		WHERE id IN (
+			SELECT c.id FROM comments c
+			WHERE c.deleted_at < $1
+			  AND NOT EXISTS (SELECT 1 FROM comments r WHERE r.parent_id = c.id)
+			ORDER BY c.deleted_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id`,
+		cutoff, batch,
+	)
+	if err != nil {
+		return result, false, fmt.Errorf("PurgeDeleted - delete comments - %w", err)
+	}
+
+	scrubbed, err := collectIDs(ctx, tx, `
+		UPDATE comments
+		SET author_uuid = NULL, content = ''
+		WHERE id IN (
+			SELECT id FROM comments
+			WHERE deleted_at < $1 AND author_uuid IS NOT NULL
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id`,
+		cutoff, batch,
+	)
+	if err != nil {
+		return result, false, fmt.Errorf("PurgeDeleted - scrub comments - %w", err)
+	}
+
+	if err = purgeReactions(ctx, tx, model.ReactionTargetComment, append(comments, scrubbed...)); err != nil {
+		return result, false, err
+	}
+	if err = purgeReports(ctx, tx, model.ReactionTargetComment, append(comments, scrubbed...)); err != nil {
+		return result, false, err
+	}
+
+	// Реакции пользователей удалились бы каскадом, но счётчики нужно уменьшить явно.
+	_, err = tx.Exec(ctx, `
+		WITH removed AS (
+			DELETE FROM reactions
+			WHERE user_uuid = ANY($1)
+			RETURNING target_type, target_id, kind
+		)
+		UPDATE reaction_counts rc
+		SET count = rc.count - c.n
+		FROM (
+			SELECT target_type, target_id, kind, COUNT(*) AS n
+			FROM removed
+			GROUP BY target_type, target_id, kind
+		) c
+		WHERE rc.target_type = c.target_type AND rc.target_id = c.target_id AND rc.kind = c.kind`,
+		users,
+	)
+	if err != nil {
+		return result, false, fmt.Errorf("PurgeDeleted - delete user reactions - %w", err)
+	}
+
+	if _, err = tx.Exec(ctx, `DELETE FROM users WHERE uuid = ANY($1)`, users); err != nil {
+		return result, false, fmt.Errorf("PurgeDeleted - delete users - %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return result, false, fmt.Errorf("PurgeDeleted - Commit - %w", err)
+	}
+
+	return model.PurgeResult{
+		Users:    len(users),
+		Posts:    len(posts),
+		Comments: len(postComments) + len(comments),
+	}, true, nil
+}
+
+func purgeReactions(ctx context.Context, tx pgx.Tx, targetType string, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	_, err := tx.Exec(ctx, `DELETE FROM reactions WHERE target_type = $1 AND target_id = ANY($2)`, targetType, ids)
+	if err != nil {
+		return fmt.Errorf("purgeReactions - delete reactions - %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `DELETE FROM reaction_counts WHERE target_type = $1 AND target_id = ANY($2)`, targetType, ids)
+	if err != nil {
+		return fmt.Errorf("purgeReactions - delete counts - %w", err)
+	}
+
+	return nil
+}
+
+// purgeReports удаляет жалобы на очищенный контент. Журнал модерации остаётся.
+func purgeReports(ctx context.Context, tx pgx.Tx, targetType string, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	_, err := tx.Exec(ctx, `DELETE FROM reports WHERE target_type = $1 AND target_id = ANY($2)`, targetType, ids)
+	if err != nil {
+		return fmt.Errorf("purgeReports - Exec - %w", err)
+	}
+
+	return nil
+}
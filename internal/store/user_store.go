@@ -0,0 +1,121 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+func (s *Store) CreateUser(ctx context.Context, user model.User) (uuid.UUID, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("CreateUser - Begin - %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	var id uuid.UUID
+	err = tx.QueryRow(ctx, `
+		INSERT INTO users (email, first_name, password_hash)
+		VALUES ($1, $2, $3)
+		RETURNING uuid`,
+		user.Email, user.FirstName, user.PasswordHash,
+	).Scan(&id)
+	if err != nil {
+		if isPgError(err, uniqueViolation) {
+			return uuid.Nil, model.ErrAlreadyExists
+		}
+		return uuid.Nil, fmt.Errorf("CreateUser - insert user - %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO user_roles (user_uuid, role_id)
+		SELECT $1, id FROM roles WHERE name = $2`,
+		id, model.RoleUser,
+	)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("CreateUser - insert role - %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return uuid.Nil, fmt.Errorf("CreateUser - Commit - %w", err)
+	}
+
+	return id, nil
+}
+
+func (s *Store) GetUserByEmail(ctx context.Context, email string) (model.User, error) {
+	return s.getUser(ctx, "email = $1", email)
+}
+
+func (s *Store) GetUserByUUID(ctx context.Context, id uuid.UUID) (model.User, error) {
+	return s.getUser(ctx, "uuid = $1", id)
+}
+
+func (s *Store) getUser(ctx context.Context, where string, arg any) (model.User, error) {
+	var user model.User
+
+	err := s.db.QueryRow(ctx, `
+		SELECT uuid, email, first_name, COALESCE(username, ''), password_hash, email_verified_at,
+		       account_state, state_until, state_reason, sessions_revoked_at, created_at
+		FROM users
+		WHERE deleted_at IS NULL AND `+where,
+		arg,
+	).Scan(
+		&user.UUID, &user.Email, &user.FirstName, &user.Username, &user.PasswordHash, &user.EmailVerifiedAt,
+		&user.AccountState, &user.StateUntil, &user.StateReason, &user.SessionsRevokedAt, &user.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.User{}, model.ErrNotFound
+		}
+		return model.User{}, fmt.Errorf("getUser - Scan - %w", err)
+	}
+
+	return user, nil
+}
+
+func (s *Store) MarkEmailVerified(ctx context.Context, id uuid.UUID) error {
+	_, err := s.db.Exec(ctx, `
+		UPDATE users
+		SET email_verified_at = COALESCE(email_verified_at, NOW())
+		WHERE uuid = $1`,
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("MarkEmailVerified - Exec - %w", err)
+	}
+
+	return nil
+}
+
+// UpdatePassword меняет пароль и, как при бане, отзывает все выданные
+// токены доступа и API-ключи: сессия, полученная до смены пароля, не переживает её.
+func (s *Store) UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash string) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("UpdatePassword - Begin - %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	tag, err := tx.Exec(ctx, `UPDATE users SET password_hash = $2, sessions_revoked_at = NOW() WHERE uuid = $1`, id, passwordHash)
+	if err != nil {
+		return fmt.Errorf("UpdatePassword - update user - %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return model.ErrNotFound
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE api_keys SET revoked_at = NOW() WHERE user_uuid = $1 AND revoked_at IS NULL`, id)
+	if err != nil {
+		return fmt.Errorf("UpdatePassword - revoke api keys - %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("UpdatePassword - Commit - %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"time"
+)
+
+// CreateUserToken сохраняет хеш одноразового токена и отзывает выданные ранее
+// неиспользованные токены с тем же назначением.
+func (s *Store) CreateUserToken(ctx context.Context, userUUID uuid.UUID, purpose, tokenHash string, expiresAt time.Time) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("CreateUserToken - Begin - %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	_, err = tx.Exec(ctx, `
+		UPDATE user_tokens
+		SET used_at = NOW()
+		WHERE user_uuid = $1 AND purpose = $2 AND used_at IS NULL`,
+		userUUID, purpose,
+	)
+	if err != nil {
+		return fmt.Errorf("CreateUserToken - invalidate - %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO user_tokens (user_uuid, purpose, token_hash, expires_at)
+		VALUES ($1, $2, $3, $4)`,
+		userUUID, purpose, tokenHash, expiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("CreateUserToken - insert - %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("CreateUserToken - Commit - %w", err)
+	}
+
+	return nil
+}
+
+// GetLastUserTokenTime время выпуска последнего токена с назначением purpose, nil — токенов не было.
+func (s *Store) GetLastUserTokenTime(ctx context.Context, userUUID uuid.UUID, purpose string) (*time.Time, error) {
+	var createdAt *time.Time
+	err := s.db.QueryRow(ctx, `
+		SELECT MAX(created_at)
+		FROM user_tokens
+		WHERE user_uuid = $1 AND purpose = $2`,
+		userUUID, purpose,
+	).Scan(&createdAt)
+	if err != nil {
+		return nil, fmt.Errorf("GetLastUserTokenTime - QueryRow - %w", err)
+	}
+
+	return createdAt, nil
+}
+
+// ConsumeUserToken атомарно помечает токен использованным и возвращает его владельца.
+func (s *Store) ConsumeUserToken(ctx context.Context, purpose, tokenHash string) (uuid.UUID, error) {
+	var userUUID uuid.UUID
+
+	err := s.db.QueryRow(ctx, `
+		UPDATE user_tokens
+		SET used_at = NOW()
+		WHERE token_hash = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > NOW()
+		RETURNING user_uuid`,
+		tokenHash, purpose,
+	).Scan(&userUUID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.Nil, model.ErrNotFound
+		}
+		return uuid.Nil, fmt.Errorf("ConsumeUserToken - Scan - %w", err)
+	}
+
+	return userUUID, nil
+}
+
+// DeleteSpentUserTokens удаляет использованные и просроченные токены, выпущенные
+// раньше cutoff. Более новые остаются: по ним GetLastUserTokenTime ограничивает
+// частоту писем.
+func (s *Store) DeleteSpentUserTokens(ctx context.Context, cutoff time.Time) (int64, error) {
+	tag, err := s.db.Exec(ctx, `
+		DELETE FROM user_tokens
+		WHERE created_at < $1
+		  AND (used_at IS NOT NULL OR expires_at < NOW())`,
+		cutoff,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("DeleteSpentUserTokens - Exec - %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
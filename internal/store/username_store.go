@@ -0,0 +1,141 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/AdilBaidual/baseProject/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"time"
+)
+
+// SetUsername меняет имя пользователя и сохраняет прежнее в истории. Имя,
+// которое другой пользователь освободил после heldSince, занять нельзя.
+// Если пользователь уже менял имя после changedSince, возвращает ErrFailedPrecondition.
+func (s *Store) SetUsername(ctx context.Context, userUUID uuid.UUID, username string, heldSince, changedSince time.Time) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("SetUsername - Begin - %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	var current string
+	err = tx.QueryRow(ctx, `
+		SELECT COALESCE(username, '')
+		FROM users
+		WHERE uuid = $1 AND deleted_at IS NULL
+		FOR UPDATE`,
+		userUUID,
+	).Scan(&current)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.ErrNotFound
+		}
+		return fmt.Errorf("SetUsername - select user - %w", err)
+	}
+
+	if current == username {
+		return nil
+	}
+
+	var recent bool
+	err = tx.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1
+			FROM username_history
+			WHERE user_uuid = $1 AND changed_at > $2
+		)`,
+		userUUID, changedSince,
+	).Scan(&recent)
+	if err != nil {
+		return fmt.Errorf("SetUsername - check last change - %w", err)
+	}
+
+	if recent {
+		return fmt.Errorf("%w: username was changed recently, try again later", model.ErrFailedPrecondition)
+	}
+
+	var held bool
+	err = tx.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1
+			FROM username_history
+			WHERE LOWER(username) = LOWER($1) AND user_uuid <> $2 AND changed_at > $3
+		)`,
+		username, userUUID, heldSince,
+	).Scan(&held)
+	if err != nil {
+		return fmt.Errorf("SetUsername - check history - %w", err)
+	}
+
+	if held {
+		return fmt.Errorf("%w: username was recently used by another user", model.ErrAlreadyExists)
+	}
+
+	if _, err = tx.Exec(ctx, `UPDATE users SET username = $2 WHERE uuid = $1`, userUUID, username); err != nil {
+		if isPgError(err, uniqueViolation) {
+			return fmt.Errorf("%w: username is taken", model.ErrAlreadyExists)
+		}
+		return fmt.Errorf("SetUsername - update user - %w", err)
+	}
+
+	if current != "" {
+		_, err = tx.Exec(ctx, `INSERT INTO username_history (user_uuid, username) VALUES ($1, $2)`, userUUID, current)
+		if err != nil {
+			return fmt.Errorf("SetUsername - insert history - %w", err)
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("SetUsername - Commit - %w", err)
+	}
+
+	return nil
+}
+
+// ListUsernameHistory прежние имена пользователя от последнего к первому.
+func (s *Store) ListUsernameHistory(ctx context.Context, userUUID uuid.UUID) ([]model.UsernameChange, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT username, changed_at
+		FROM username_history
+		WHERE user_uuid = $1
+		ORDER BY id DESC`,
+		userUUID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ListUsernameHistory - Query - %w", err)
+	}
+
+	changes, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.UsernameChange, error) {
+		var c model.UsernameChange
+		err := row.Scan(&c.Username, &c.ChangedAt)
+		return c, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ListUsernameHistory - CollectRows - %w", err)
+	}
+
+	return changes, nil
+}
+
+// ResolveUsernames находит действующих пользователей с именами handles
+// (в нижнем регистре). Неизвестные имена пропускаются.
+func (s *Store) ResolveUsernames(ctx context.Context, handles []string) ([]model.Mention, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT LOWER(username), uuid
+		FROM users
+		WHERE LOWER(username) = ANY($1) AND deleted_at IS NULL`,
+		handles,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ResolveUsernames - Query - %w", err)
+	}
+
+	mentions, err := pgx.CollectRows(rows, scanMention)
+	if err != nil {
+		return nil, fmt.Errorf("ResolveUsernames - CollectRows - %w", err)
+	}
+
+	return mentions, nil
+}
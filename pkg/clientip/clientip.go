@@ -0,0 +1,103 @@
+package clientip
+
+import (
+	"context"
+	"fmt"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"net"
+	"net/http"
+	"strings"
+)
+
+const forwardedForKey = "x-forwarded-for"
+
+// Resolver определяет IP клиента. Заголовку X-Forwarded-For доверяем только
+// если запрос пришёл от доверенного прокси (например, от grpc-gateway).
+type Resolver struct {
+	trusted []*net.IPNet
+}
+
+func NewResolver(trustedProxies []string) (*Resolver, error) {
+	trusted := make([]*net.IPNet, 0, len(trustedProxies))
+	for _, cidr := range trustedProxies {
+		if !strings.Contains(cidr, "/") {
+			if ip := net.ParseIP(cidr); ip != nil && ip.To4() != nil {
+				cidr += "/32"
+			} else {
+				cidr += "/128"
+			}
+		}
+
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("NewResolver - net.ParseCIDR - %w", err)
+		}
+		trusted = append(trusted, ipNet)
+	}
+
+	return &Resolver{trusted: trusted}, nil
+}
+
+func (r *Resolver) FromContext(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	return r.resolve(p.Addr.String(), md.Get(forwardedForKey))
+}
+
+// FromRequest то же для HTTP-эндпоинтов, обслуживаемых в обход grpc-gateway.
+func (r *Resolver) FromRequest(req *http.Request) string {
+	return r.resolve(req.RemoteAddr, req.Header.Values(forwardedForKey))
+}
+
+func (r *Resolver) resolve(remote string, forwardedFor []string) string {
+	if host, _, err := net.SplitHostPort(remote); err == nil {
+		remote = host
+	}
+
+	if !r.isTrusted(remote) {
+		return remote
+	}
+
+	var hops []string
+	for _, v := range forwardedFor {
+		for _, hop := range strings.Split(v, ",") {
+			if hop = strings.TrimSpace(hop); hop != "" {
+				hops = append(hops, hop)
+			}
+		}
+	}
+
+	// Идём справа налево и берём первый адрес, не принадлежащий доверенным прокси.
+	for i := len(hops) - 1; i >= 0; i-- {
+		if net.ParseIP(hops[i]) == nil {
+			break
+		}
+		if !r.isTrusted(hops[i]) {
+			return hops[i]
+		}
+		remote = hops[i]
+	}
+
+	return remote
+}
+
+func (r *Resolver) isTrusted(addr string) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+
+	for _, ipNet := range r.trusted {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
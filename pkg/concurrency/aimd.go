@@ -0,0 +1,35 @@
+package concurrency
+
+import "time"
+
+// AIMD увеличивает лимит на единицу при успешных запросах и
+// уменьшает его мультипликативно при потерях или превышении таймаута.
+type AIMD struct {
+	backoffRatio float64
+	timeout      time.Duration
+}
+
+func NewAIMD(cfg AIMDConfig) *AIMD {
+	backoff := cfg.BackoffRatio
+	if backoff <= 0 || backoff >= 1 {
+		backoff = 0.9
+	}
+
+	return &AIMD{
+		backoffRatio: backoff,
+		timeout:      cfg.Timeout,
+	}
+}
+
+func (a *AIMD) Update(limit float64, rtt time.Duration, inFlight int, dropped bool) float64 {
+	if dropped || (a.timeout > 0 && rtt > a.timeout) {
+		return limit * a.backoffRatio
+	}
+
+	// Увеличиваем лимит, только если он действительно используется.
+	if float64(inFlight)*2 >= limit {
+		return limit + 1
+	}
+
+	return limit
+}
@@ -0,0 +1,199 @@
+package concurrency
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	AlgorithmAIMD     = "aimd"
+	AlgorithmGradient = "gradient"
+)
+
+type Priority string
+
+const (
+	PriorityCritical Priority = "critical"
+	PriorityNormal   Priority = "normal"
+	PriorityBulk     Priority = "bulk"
+)
+
+var ErrUnknownAlgorithm = errors.New("unknown concurrency limit algorithm")
+
+type Config struct {
+	Enabled      bool                 `yaml:"enabled" env:"CONCURRENCY_LIMIT_ENABLED"`
+	Algorithm    string               `yaml:"algorithm" env:"CONCURRENCY_LIMIT_ALGORITHM" env-default:"gradient"`
+	InitialLimit int                  `yaml:"initial_limit"`
+	MinLimit     int                  `yaml:"min_limit"`
+	MaxLimit     int                  `yaml:"max_limit"`
+	AIMD         AIMDConfig           `yaml:"aimd"`
+	Gradient     GradientConfig       `yaml:"gradient"`
+	Shares       map[Priority]float64 `yaml:"shares"`
+	Priorities   map[string]Priority  `yaml:"priorities"`
+	// DebugAddr внутренний адрес, на котором отдаётся состояние ограничителя
+	// (GET /debug/concurrency). Пустой — не отдаётся. Наружу не публикуется.
+	DebugAddr string `yaml:"debug_addr" env:"CONCURRENCY_DEBUG_ADDR"`
+}
+
+type AIMDConfig struct {
+	BackoffRatio float64       `yaml:"backoff_ratio"`
+	Timeout      time.Duration `yaml:"timeout"`
+}
+
+type GradientConfig struct {
+	Smoothing   float64 `yaml:"smoothing"`
+	ShortWindow int     `yaml:"short_window"`
+	LongWindow  int     `yaml:"long_window"`
+	Tolerance   float64 `yaml:"tolerance"`
+}
+
+// Algorithm вычисляет новый лимит по результату очередного запроса.
+type Algorithm interface {
+	Update(limit float64, rtt time.Duration, inFlight int, dropped bool) float64
+}
+
+func NewAlgorithm(cfg Config) (Algorithm, error) {
+	switch cfg.Algorithm {
+	case AlgorithmAIMD:
+		return NewAIMD(cfg.AIMD), nil
+	case "", AlgorithmGradient:
+		return NewGradient(cfg.Gradient), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownAlgorithm, cfg.Algorithm)
+	}
+}
+
+// Limiter ограничивает число одновременно обрабатываемых запросов.
+// Каждому приоритету доступна своя доля текущего лимита, поэтому при
+// перегрузке первыми отбрасываются запросы с низким приоритетом.
+type Limiter struct {
+	mu       sync.Mutex
+	algo     Algorithm
+	limit    float64
+	minLimit float64
+	maxLimit float64
+	inFlight int
+	shares   map[Priority]float64
+
+	rejected map[Priority]*atomic.Uint64
+	dropped  atomic.Uint64
+}
+
+type Snapshot struct {
+	Limit    int                 `json:"limit"`
+	InFlight int                 `json:"in_flight"`
+	Dropped  uint64              `json:"dropped"`
+	Rejected map[Priority]uint64 `json:"rejected"`
+}
+
+func NewLimiter(cfg Config, algo Algorithm) *Limiter {
+	minLimit := math.Max(1, float64(cfg.MinLimit))
+	maxLimit := math.Max(minLimit, float64(cfg.MaxLimit))
+	limit := math.Min(maxLimit, math.Max(minLimit, float64(cfg.InitialLimit)))
+
+	shares := map[Priority]float64{
+		PriorityCritical: 1,
+		PriorityNormal:   0.9,
+		PriorityBulk:     0.5,
+	}
+	for p, share := range cfg.Shares {
+		shares[p] = share
+	}
+
+	rejected := make(map[Priority]*atomic.Uint64, len(shares))
+	for p := range shares {
+		rejected[p] = new(atomic.Uint64)
+	}
+
+	return &Limiter{
+		algo:     algo,
+		limit:    limit,
+		minLimit: minLimit,
+		maxLimit: maxLimit,
+		shares:   shares,
+		rejected: rejected,
+	}
+}
+
+// Acquire занимает слот. Если слот получен, по завершении запроса
+// нужно вызвать ровно один из методов Token.
+func (l *Limiter) Acquire(priority Priority) (*Token, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	share, ok := l.shares[priority]
+	if !ok {
+		priority, share = PriorityNormal, l.shares[PriorityNormal]
+	}
+
+	allowed := math.Max(1, math.Floor(l.limit*share))
+	if float64(l.inFlight) >= allowed {
+		l.rejected[priority].Add(1)
+		return nil, false
+	}
+
+	l.inFlight++
+
+	return &Token{limiter: l, start: time.Now()}, true
+}
+
+func (l *Limiter) release(rtt time.Duration, dropped, ignore bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	inFlight := l.inFlight
+	l.inFlight--
+
+	if ignore {
+		return
+	}
+
+	if dropped {
+		l.dropped.Add(1)
+	}
+
+	limit := l.algo.Update(l.limit, rtt, inFlight, dropped)
+	l.limit = math.Min(l.maxLimit, math.Max(l.minLimit, limit))
+}
+
+func (l *Limiter) Snapshot() Snapshot {
+	l.mu.Lock()
+	snapshot := Snapshot{
+		Limit:    int(l.limit),
+		InFlight: l.inFlight,
+		Dropped:  l.dropped.Load(),
+		Rejected: make(map[Priority]uint64, len(l.rejected)),
+	}
+	l.mu.Unlock()
+
+	for p, counter := range l.rejected {
+		snapshot.Rejected[p] = counter.Load()
+	}
+
+	return snapshot
+}
+
+type Token struct {
+	limiter *Limiter
+	start   time.Time
+	once    sync.Once
+}
+
+// OnSuccess сообщает, что запрос обработан, и учитывает его время.
+func (t *Token) OnSuccess() {
+	t.once.Do(func() { t.limiter.release(time.Since(t.start), false, false) })
+}
+
+// OnDropped сообщает, что запрос завершился из-за перегрузки (таймаут, недоступность).
+func (t *Token) OnDropped() {
+	t.once.Do(func() { t.limiter.release(time.Since(t.start), true, false) })
+}
+
+// OnIgnore освобождает слот без влияния на лимит (например, клиент отменил запрос).
+func (t *Token) OnIgnore() {
+	t.once.Do(func() { t.limiter.release(0, false, true) })
+}
@@ -0,0 +1,98 @@
+package concurrency
+
+import (
+	"math"
+	"time"
+)
+
+// Gradient сравнивает краткосрочную и долгосрочную среднюю задержку.
+// Рост краткосрочной задержки относительно долгосрочной означает
+// образование очереди, и лимит уменьшается пропорционально.
+type Gradient struct {
+	smoothing float64
+	tolerance float64
+	shortRTT  *ewma
+	longRTT   *ewma
+}
+
+func NewGradient(cfg GradientConfig) *Gradient {
+	smoothing := cfg.Smoothing
+	if smoothing <= 0 || smoothing > 1 {
+		smoothing = 0.2
+	}
+
+	tolerance := cfg.Tolerance
+	if tolerance < 1 {
+		tolerance = 1.5
+	}
+
+	shortWindow := cfg.ShortWindow
+	if shortWindow <= 0 {
+		shortWindow = 10
+	}
+
+	longWindow := cfg.LongWindow
+	if longWindow <= shortWindow {
+		longWindow = 600
+	}
+
+	return &Gradient{
+		smoothing: smoothing,
+		tolerance: tolerance,
+		shortRTT:  newEWMA(shortWindow),
+		longRTT:   newEWMA(longWindow),
+	}
+}
+
+func (g *Gradient) Update(limit float64, rtt time.Duration, inFlight int, dropped bool) float64 {
+	if dropped {
+		return limit * (1 - g.smoothing)
+	}
+
+	short := g.shortRTT.add(float64(rtt))
+	long := g.longRTT.add(float64(rtt))
+	if short <= 0 {
+		return limit
+	}
+
+	// Долгосрочная оценка не должна бесконечно догонять растущую задержку.
+	if long/short > 2 {
+		g.longRTT.value = short * 2
+		long = g.longRTT.value
+	}
+
+	// Недогруженный сервер не даёт информации о том, можно ли увеличить лимит.
+	if float64(inFlight)*2 < limit {
+		return limit
+	}
+
+	gradient := math.Max(0.5, math.Min(1, g.tolerance*long/short))
+	queueSize := math.Sqrt(limit)
+	newLimit := limit*gradient + queueSize
+
+	return limit*(1-g.smoothing) + newLimit*g.smoothing
+}
+
+type ewma struct {
+	window int
+	count  int
+	value  float64
+}
+
+func newEWMA(window int) *ewma {
+	return &ewma{window: window}
+}
+
+func (e *ewma) add(sample float64) float64 {
+	// Первые значения усредняем арифметически, чтобы быстро получить оценку.
+	if e.count < e.window {
+		e.count++
+		e.value += (sample - e.value) / float64(e.count)
+		return e.value
+	}
+
+	factor := 2 / float64(e.window+1)
+	e.value = e.value*(1-factor) + sample*factor
+
+	return e.value
+}
@@ -0,0 +1,45 @@
+package concurrency
+
+import (
+	"context"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// RegisterMetrics публикует текущий лимит, число запросов в обработке и счётчики отказов.
+func RegisterMetrics(meter metric.Meter, l *Limiter) error {
+	limit, err := meter.Int64ObservableGauge("concurrency.limit")
+	if err != nil {
+		return err
+	}
+
+	inFlight, err := meter.Int64ObservableGauge("concurrency.in_flight")
+	if err != nil {
+		return err
+	}
+
+	rejected, err := meter.Int64ObservableCounter("concurrency.rejected")
+	if err != nil {
+		return err
+	}
+
+	dropped, err := meter.Int64ObservableCounter("concurrency.dropped")
+	if err != nil {
+		return err
+	}
+
+	_, err = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		snapshot := l.Snapshot()
+
+		o.ObserveInt64(limit, int64(snapshot.Limit))
+		o.ObserveInt64(inFlight, int64(snapshot.InFlight))
+		o.ObserveInt64(dropped, int64(snapshot.Dropped))
+		for p, count := range snapshot.Rejected {
+			o.ObserveInt64(rejected, int64(count), metric.WithAttributes(attribute.String("priority", string(p))))
+		}
+
+		return nil
+	}, limit, inFlight, rejected, dropped)
+
+	return err
+}
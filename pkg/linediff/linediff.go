@@ -0,0 +1,200 @@
+package linediff
+
+import "strings"
+
+type Op int
+
+const (
+	Equal Op = iota
+	Insert
+	Delete
+)
+
+// Line строка результата сравнения. OldLine и NewLine — номера строк с единицы
+// в старом и новом тексте, 0 если строки в соответствующем тексте нет.
+type Line struct {
+	Op      Op
+	Text    string
+	OldLine int
+	NewLine int
+}
+
+// Diff построчно сравнивает два текста алгоритмом Майерса в линейной памяти
+// и возвращает минимальный набор вставок и удалений вместе с общими строками.
+func Diff(oldText, newText string) []Line {
+	oldLines := split(oldText)
+	newLines := split(newText)
+
+	// Строки сравниваются по номерам, а не по содержимому.
+	ids := make(map[string]int, len(oldLines)+len(newLines))
+	d := differ{
+		a:       toIDs(oldLines, ids),
+		b:       toIDs(newLines, ids),
+		aLines:  oldLines,
+		bLines:  newLines,
+		results: make([]Line, 0, max(len(oldLines), len(newLines))),
+	}
+
+	d.compare(0, len(d.a), 0, len(d.b))
+	deletionsFirst(d.results)
+
+	return d.results
+}
+
+// deletionsFirst переставляет строки внутри каждого блока изменений так, чтобы
+// удаления шли перед вставками, как в привычном диффе. Порядок строк каждого
+// вида сохраняется.
+func deletionsFirst(lines []Line) {
+	for start := 0; start < len(lines); {
+		if lines[start].Op == Equal {
+			start++
+			continue
+		}
+
+		end := start
+		for end < len(lines) && lines[end].Op != Equal {
+			end++
+		}
+
+		block := make([]Line, 0, end-start)
+		for _, op := range []Op{Delete, Insert} {
+			for _, line := range lines[start:end] {
+				if line.Op == op {
+					block = append(block, line)
+				}
+			}
+		}
+		copy(lines[start:end], block)
+
+		start = end
+	}
+}
+
+func split(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+func toIDs(lines []string, ids map[string]int) []int {
+	res := make([]int, len(lines))
+	for i, line := range lines {
+		id, ok := ids[line]
+		if !ok {
+			id = len(ids)
+			ids[line] = id
+		}
+		res[i] = id
+	}
+	return res
+}
+
+type differ struct {
+	a, b           []int
+	aLines, bLines []string
+	results        []Line
+}
+
+func (d *differ) compare(aLo, aHi, bLo, bHi int) {
+	for aLo < aHi && bLo < bHi && d.a[aLo] == d.b[bLo] {
+		d.equal(aLo, bLo)
+		aLo++
+		bLo++
+	}
+
+	suffix := 0
+	for aLo < aHi-suffix && bLo < bHi-suffix && d.a[aHi-suffix-1] == d.b[bHi-suffix-1] {
+		suffix++
+	}
+	aHi -= suffix
+	bHi -= suffix
+
+	switch {
+	case aLo == aHi:
+		for y := bLo; y < bHi; y++ {
+			d.results = append(d.results, Line{Op: Insert, Text: d.bLines[y], NewLine: y + 1})
+		}
+	case bLo == bHi:
+		for x := aLo; x < aHi; x++ {
+			d.results = append(d.results, Line{Op: Delete, Text: d.aLines[x], OldLine: x + 1})
+		}
+	default:
+		x, y, u, v := d.middleSnake(aLo, aHi, bLo, bHi)
+		d.compare(aLo, x, bLo, y)
+		for ; x < u; x, y = x+1, y+1 {
+			d.equal(x, y)
+		}
+		d.compare(u, aHi, v, bHi)
+	}
+
+	for i := 0; i < suffix; i++ {
+		d.equal(aHi+i, bHi+i)
+	}
+}
+
+func (d *differ) equal(x, y int) {
+	d.results = append(d.results, Line{Op: Equal, Text: d.aLines[x], OldLine: x + 1, NewLine: y + 1})
+}
+
+// middleSnake ищет середину кратчайшего пути правки встречными проходами
+// от начала и от конца. Возвращает диагональный участок (x, y) -> (u, v)
+// в абсолютных координатах.
+func (d *differ) middleSnake(aLo, aHi, bLo, bHi int) (x, y, u, v int) {
+	n, m := aHi-aLo, bHi-bLo
+	delta := n - m
+	odd := delta%2 != 0
+	limit := (n+m+1)/2 + 1
+	offset := limit + 1
+
+	vf := make([]int, 2*offset+1)
+	vb := make([]int, 2*offset+1)
+	vf[offset+1] = 0
+	vb[offset+1] = n + 1
+
+	for step := 0; step <= limit; step++ {
+		for k := -step; k <= step; k += 2 {
+			var px int
+			if k == -step || (k != step && vf[offset+k-1] < vf[offset+k+1]) {
+				px = vf[offset+k+1]
+			} else {
+				px = vf[offset+k-1] + 1
+			}
+			py := px - k
+			sx, sy := px, py
+			for px < n && py < m && d.a[aLo+px] == d.b[bLo+py] {
+				px++
+				py++
+			}
+			vf[offset+k] = px
+
+			// Диагональ k в обратном проходе имеет номер k-delta.
+			if rk := k - delta; odd && rk >= -(step-1) && rk <= step-1 && px >= vb[offset+rk] {
+				return aLo + sx, bLo + sy, aLo + px, bLo + py
+			}
+		}
+
+		for k := -step; k <= step; k += 2 {
+			var px int
+			if k == -step || (k != step && vb[offset+k+1] <= vb[offset+k-1]) {
+				px = vb[offset+k+1] - 1
+			} else {
+				px = vb[offset+k-1]
+			}
+			py := px - k - delta
+			ex, ey := px, py
+			for px > 0 && py > 0 && d.a[aLo+px-1] == d.b[bLo+py-1] {
+				px--
+				py--
+			}
+			vb[offset+k] = px
+
+			if fk := k + delta; !odd && fk >= -step && fk <= step && px <= vf[offset+fk] {
+				return aLo + px, bLo + py, aLo + ex, bLo + ey
+			}
+		}
+	}
+
+	// Недостижимо: пути всегда встречаются не позже чем за (n+m+1)/2 шагов.
+	return aLo, bLo, aLo, bLo
+}
@@ -0,0 +1,143 @@
+package linediff
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// render записывает результат в виде унифицированного диффа без заголовков.
+func render(lines []Line) string {
+	var b strings.Builder
+	for _, line := range lines {
+		switch line.Op {
+		case Equal:
+			b.WriteString(" ")
+		case Insert:
+			b.WriteString("+")
+		case Delete:
+			b.WriteString("-")
+		}
+		b.WriteString(line.Text)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		old  string
+		new  string
+		want string
+	}{
+		{name: "both empty", old: "", new: "", want: ""},
+		{name: "identical", old: "a\nb\n", new: "a\nb\n", want: " a\n b\n"},
+		{name: "from empty", old: "", new: "a\nb", want: "+a\n+b\n"},
+		{name: "to empty", old: "a\nb", new: "", want: "-a\n-b\n"},
+		{name: "trailing newline ignored", old: "a\nb", new: "a\nb\n", want: " a\n b\n"},
+		{name: "append", old: "a\nb", new: "a\nb\nc", want: " a\n b\n+c\n"},
+		{name: "prepend", old: "b\nc", new: "a\nb\nc", want: "+a\n b\n c\n"},
+		{name: "delete middle", old: "a\nb\nc", new: "a\nc", want: " a\n-b\n c\n"},
+		{name: "replace middle", old: "a\nb\nc", new: "a\nx\nc", want: " a\n-b\n+x\n c\n"},
+		{name: "empty lines", old: "a\n\nb", new: "a\nb", want: " a\n-\n b\n"},
+		{name: "duplicate lines", old: "x\nx\nx", new: "x\nx", want: " x\n x\n-x\n"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := render(Diff(tc.old, tc.new)); got != tc.want {
+				t.Errorf("Diff(%q, %q) =\n%s\nwant\n%s", tc.old, tc.new, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDiffLineNumbers(t *testing.T) {
+	got := Diff("a\nb\nc\nd", "a\nx\nc\nd\ne")
+	want := []Line{
+		{Op: Equal, Text: "a", OldLine: 1, NewLine: 1},
+		{Op: Delete, Text: "b", OldLine: 2},
+		{Op: Insert, Text: "x", NewLine: 2},
+		{Op: Equal, Text: "c", OldLine: 3, NewLine: 3},
+		{Op: Equal, Text: "d", OldLine: 4, NewLine: 4},
+		{Op: Insert, Text: "e", NewLine: 5},
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("Diff = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("line %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+// TestDiffRandom сверяет Diff со справочным решением на случайных текстах из
+// небольшого алфавита, где много совпадающих строк: результат должен собирать
+// оба текста, а число правок — совпадать с вычисленным через LCS.
+func TestDiffRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	randomText := func() []string {
+		lines := make([]string, rng.Intn(40))
+		for i := range lines {
+			lines[i] = string(rune('a' + rng.Intn(4)))
+		}
+		return lines
+	}
+
+	for i := 0; i < 500; i++ {
+		oldLines, newLines := randomText(), randomText()
+		diff := Diff(strings.Join(oldLines, "\n"), strings.Join(newLines, "\n"))
+
+		var gotOld, gotNew []string
+		edits := 0
+		for _, line := range diff {
+			if line.Op != Insert {
+				gotOld = append(gotOld, line.Text)
+				if line.OldLine != len(gotOld) {
+					t.Fatalf("case %d: old line number %d, want %d", i, line.OldLine, len(gotOld))
+				}
+			}
+			if line.Op != Delete {
+				gotNew = append(gotNew, line.Text)
+				if line.NewLine != len(gotNew) {
+					t.Fatalf("case %d: new line number %d, want %d", i, line.NewLine, len(gotNew))
+				}
+			}
+			if line.Op != Equal {
+				edits++
+			}
+		}
+
+		if strings.Join(gotOld, "\n") != strings.Join(oldLines, "\n") || strings.Join(gotNew, "\n") != strings.Join(newLines, "\n") {
+			t.Fatalf("case %d: diff does not reproduce the texts\nold %q\nnew %q\ndiff\n%s", i, oldLines, newLines, render(diff))
+		}
+
+		if want := len(oldLines) + len(newLines) - 2*lcs(oldLines, newLines); edits != want {
+			t.Fatalf("case %d: %d edits, want minimal %d\nold %q\nnew %q", i, edits, want, oldLines, newLines)
+		}
+	}
+}
+
+// lcs длина наибольшей общей подпоследовательности динамическим программированием.
+func lcs(a, b []string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for i := range a {
+		for j := range b {
+			switch {
+			case a[i] == b[j]:
+				cur[j+1] = prev[j] + 1
+			case prev[j+1] >= cur[j]:
+				cur[j+1] = prev[j+1]
+			default:
+				cur[j+1] = cur[j]
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
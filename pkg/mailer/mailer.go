@@ -0,0 +1,47 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+const (
+	DriverSMTP   = "smtp"
+	DriverFile   = "file"
+	DriverMemory = "memory"
+)
+
+var ErrUnknownDriver = errors.New("unknown mailer driver")
+
+type Config struct {
+	Driver string     `yaml:"driver" env:"MAILER_DRIVER" env-default:"file"`
+	From   string     `yaml:"from" env:"MAILER_FROM"`
+	SMTP   SMTPConfig `yaml:"smtp"`
+	Dir    string     `yaml:"dir" env:"MAILER_DIR"`
+}
+
+type Message struct {
+	From    string
+	To      []string
+	Subject string
+	HTML    string
+	Text    string
+}
+
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+func New(cfg Config) (Mailer, error) {
+	switch cfg.Driver {
+	case DriverSMTP:
+		return NewSMTPMailer(cfg.SMTP, cfg.From), nil
+	case "", DriverFile:
+		return NewFileMailer(cfg.Dir, cfg.From)
+	case DriverMemory:
+		return NewMemoryMailer(cfg.From), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownDriver, cfg.Driver)
+	}
+}
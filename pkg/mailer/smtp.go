@@ -0,0 +1,119 @@
+package mailer
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+)
+
+type SMTPConfig struct {
+	Host     string `yaml:"host" env:"SMTP_HOST"`
+	Port     int    `yaml:"port" env:"SMTP_PORT" env-default:"587"`
+	Username string `env:"SMTP_USERNAME"`
+	Password string `env:"SMTP_PASSWORD"`
+	// ImplicitTLS включает TLS с момента подключения (порт 465), иначе используется STARTTLS.
+	ImplicitTLS bool `yaml:"implicit_tls" env:"SMTP_IMPLICIT_TLS"`
+}
+
+type SMTPMailer struct {
+	cfg  SMTPConfig
+	from string
+}
+
+func NewSMTPMailer(cfg SMTPConfig, from string) *SMTPMailer {
+	return &SMTPMailer{cfg: cfg, from: from}
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	if msg.From == "" {
+		msg.From = m.from
+	}
+
+	// В заголовках адреса остаются как есть, например "baseProject <no-reply@example.com>",
+	// а в конверт SMTP передаются только сами адреса.
+	from, err := mail.ParseAddress(msg.From)
+	if err != nil {
+		return fmt.Errorf("SMTPMailer.Send - ParseAddress from - %w", err)
+	}
+
+	recipients := make([]string, 0, len(msg.To))
+	for _, to := range msg.To {
+		recipient, err := mail.ParseAddress(to)
+		if err != nil {
+			return fmt.Errorf("SMTPMailer.Send - ParseAddress to - %w", err)
+		}
+		recipients = append(recipients, recipient.Address)
+	}
+
+	body, err := build(msg)
+	if err != nil {
+		return fmt.Errorf("SMTPMailer.Send - build - %w", err)
+	}
+
+	addr := net.JoinHostPort(m.cfg.Host, strconv.Itoa(m.cfg.Port))
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("SMTPMailer.Send - DialContext - %w", err)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	tlsConfig := &tls.Config{ServerName: m.cfg.Host, MinVersion: tls.VersionTLS12}
+	if m.cfg.ImplicitTLS {
+		conn = tls.Client(conn, tlsConfig)
+	}
+
+	client, err := smtp.NewClient(conn, m.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("SMTPMailer.Send - NewClient - %w", err)
+	}
+	defer client.Close()
+
+	if !m.cfg.ImplicitTLS {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err = client.StartTLS(tlsConfig); err != nil {
+				return fmt.Errorf("SMTPMailer.Send - StartTLS - %w", err)
+			}
+		}
+	}
+
+	if m.cfg.Username != "" {
+		if err = client.Auth(smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)); err != nil {
+			return fmt.Errorf("SMTPMailer.Send - Auth - %w", err)
+		}
+	}
+
+	if err = client.Mail(from.Address); err != nil {
+		return fmt.Errorf("SMTPMailer.Send - Mail - %w", err)
+	}
+
+	for _, to := range recipients {
+		if err = client.Rcpt(to); err != nil {
+			return fmt.Errorf("SMTPMailer.Send - Rcpt - %w", err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("SMTPMailer.Send - Data - %w", err)
+	}
+
+	if _, err = w.Write(body); err != nil {
+		return fmt.Errorf("SMTPMailer.Send - Write - %w", err)
+	}
+
+	if err = w.Close(); err != nil {
+		return fmt.Errorf("SMTPMailer.Send - Close - %w", err)
+	}
+
+	return client.Quit()
+}
@@ -0,0 +1,52 @@
+package mailer
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"strings"
+	texttemplate "text/template"
+)
+
+// Templates рендерит письма из набора файлов <name>.subject.txt, <name>.txt и <name>.html.
+type Templates struct {
+	html *htmltemplate.Template
+	text *texttemplate.Template
+}
+
+func NewTemplates(fsys fs.FS) (*Templates, error) {
+	html, err := htmltemplate.ParseFS(fsys, "*.html")
+	if err != nil {
+		return nil, fmt.Errorf("NewTemplates - parse html - %w", err)
+	}
+
+	text, err := texttemplate.ParseFS(fsys, "*.txt")
+	if err != nil {
+		return nil, fmt.Errorf("NewTemplates - parse text - %w", err)
+	}
+
+	return &Templates{html: html, text: text}, nil
+}
+
+func (t *Templates) Render(name string, data any) (Message, error) {
+	var subject, text, html bytes.Buffer
+
+	if err := t.text.ExecuteTemplate(&subject, name+".subject.txt", data); err != nil {
+		return Message{}, fmt.Errorf("Templates.Render - subject - %w", err)
+	}
+
+	if err := t.text.ExecuteTemplate(&text, name+".txt", data); err != nil {
+		return Message{}, fmt.Errorf("Templates.Render - text - %w", err)
+	}
+
+	if err := t.html.ExecuteTemplate(&html, name+".html", data); err != nil {
+		return Message{}, fmt.Errorf("Templates.Render - html - %w", err)
+	}
+
+	return Message{
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
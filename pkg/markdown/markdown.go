@@ -0,0 +1,72 @@
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	highlighting "github.com/yuin/goldmark-highlighting/v2"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"regexp"
+)
+
+// Version версия разметки и политики очистки. Увеличивается при любом их
+// изменении, чтобы HTML, сохранённый прежней версией, был перестроен.
+const Version = 2
+
+var (
+	converter = goldmark.New(
+		goldmark.WithExtensions(
+			extension.GFM,
+			// Подсветка классами chroma, а не inline-стилями: style санитайзер не пропускает.
+			highlighting.NewHighlighting(
+				highlighting.WithFormatOptions(chromahtml.WithClasses(true)),
+			),
+			mentionExtension{},
+		),
+	)
+
+	sanitizer = newPolicy()
+)
+
+// newPolicy разрешённые элементы и атрибуты поверх политики для пользовательского
+// контента bluemonday. Сырой HTML goldmark и так не выводит, санитайзер —
+// вторая линия защиты от того, что может сгенерировать разметка или расширения.
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+
+	p.AllowURLSchemes("http", "https", "mailto")
+	p.RequireParseableURLs(true)
+	p.RequireNoFollowOnLinks(true)
+	p.RequireNoReferrerOnLinks(true)
+	p.AddTargetBlankToFullyQualifiedLinks(true)
+
+	// Классы подсветки кода и language-* у блоков без подсветки.
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^[a-zA-Z0-9_ -]+$`)).OnElements("pre", "code", "span")
+
+	// Ссылки на профили упомянутых пользователей.
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^mention$`)).OnElements("a")
+
+	// Чекбоксы списков задач GFM, всегда disabled.
+	p.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	p.AllowAttrs("checked", "disabled").OnElements("input")
+
+	return p
+}
+
+// Render переводит Markdown в HTML, безопасный для вставки в страницу.
+// mentions сопоставляет handle в нижнем регистре с адресом профиля:
+// такие @handle становятся ссылками, остальные остаются текстом.
+func Render(source string, mentions map[string]string) (string, error) {
+	pc := parser.NewContext()
+	pc.Set(mentionsKey, &mentionState{links: mentions})
+
+	var buf bytes.Buffer
+	if err := converter.Convert([]byte(source), &buf, parser.WithContext(pc)); err != nil {
+		return "", fmt.Errorf("markdown.Render - Convert - %w", err)
+	}
+
+	return sanitizer.Sanitize(buf.String()), nil
+}
@@ -0,0 +1,139 @@
+package markdown
+
+import (
+	"golang.org/x/net/html"
+	"strings"
+	"testing"
+)
+
+// xssCorpus хранимые XSS, которые Render обязан обезвредить: сырой HTML,
+// опасные схемы в ссылках и картинках, обходы через кодирование и
+// расширения разметки — упоминания и классы подсветки.
+var xssCorpus = []struct {
+	name     string
+	source   string
+	mentions map[string]string
+}{
+	{name: "script tag", source: `<script>alert(1)</script>`},
+	{name: "script in paragraph", source: "hello <script>alert(1)</script> world"},
+	{name: "img onerror", source: `<img src=x onerror=alert(1)>`},
+	{name: "svg onload", source: `<svg onload=alert(1)>`},
+	{name: "iframe srcdoc", source: `<iframe srcdoc="<script>alert(1)</script>"></iframe>`},
+	{name: "body onload", source: `<body onload=alert(1)>`},
+	{name: "style expression", source: `<div style="background:url(javascript:alert(1))">x</div>`},
+	{name: "html block", source: "<div>\n<a href=\"javascript:alert(1)\">x</a>\n</div>"},
+	{name: "link javascript", source: `[x](javascript:alert(1))`},
+	{name: "link javascript upper", source: `[x](JaVaScRiPt:alert(1))`},
+	{name: "link javascript entity", source: `[x](&#106;avascript:alert(1))`},
+	{name: "link javascript tab", source: "[x](java\tscript:alert(1))"},
+	{name: "link javascript encoded", source: `[x](javascript%3Aalert(1))`},
+	{name: "link vbscript", source: `[x](vbscript:msgbox(1))`},
+	{name: "link data", source: `[x](data:text/html;base64,PHNjcmlwdD5hbGVydCgxKTwvc2NyaXB0Pg==)`},
+	{name: "link title breakout", source: `[x](https://example.com "a\" onmouseover=\"alert(1)")`},
+	{name: "reference link", source: "[x][r]\n\n[r]: javascript:alert(1)"},
+	{name: "image javascript", source: `![x](javascript:alert(1))`},
+	{name: "image data", source: `![x](data:image/svg+xml;base64,PHN2ZyBvbmxvYWQ9YWxlcnQoMSk+)`},
+	{name: "image alt breakout", source: `![x" onerror="alert(1)](https://example.com/a.png)`},
+	{name: "autolink javascript", source: `<javascript:alert(1)>`},
+	{name: "autolink data", source: `<data:text/html,<script>alert(1)</script>>`},
+	{name: "gfm autolink", source: `www.example.com/"onmouseover="alert(1)`},
+	{name: "code block language", source: "```\" onmouseover=\"alert(1)\nx\n```"},
+	{name: "code block class breakout", source: "```js\"><script>alert(1)</script>\nx\n```"},
+	{name: "inline code", source: "`<script>alert(1)</script>`"},
+	{name: "task list", source: "- [x] <input onfocus=alert(1) autofocus>"},
+	{name: "table cell", source: "| a |\n| - |\n| <img src=x onerror=alert(1)> |"},
+	{
+		name:     "mention javascript url",
+		source:   `hi @evil`,
+		mentions: map[string]string{"evil": "javascript:alert(1)"},
+	},
+	{
+		name:     "mention data url",
+		source:   `hi @evil`,
+		mentions: map[string]string{"evil": "data:text/html,<script>alert(1)</script>"},
+	},
+	{
+		name:     "mention attribute breakout",
+		source:   `hi @evil`,
+		mentions: map[string]string{"evil": `/users/x" onmouseover="alert(1)`},
+	},
+	{
+		name:     "mention inside link",
+		source:   `[@evil](javascript:alert(1))`,
+		mentions: map[string]string{"evil": "/users/evil"},
+	},
+	{name: "mention class on raw link", source: `<a class="mention" href="javascript:alert(1)">x</a>`},
+	{name: "foreign class on link", source: `<a class="mention x" onclick="alert(1)" href="/x">x</a>`},
+	{name: "class on span", source: `<span class="x" onmouseover="alert(1)">x</span>`},
+}
+
+// dangerousTags элементы, которые не должны появляться в выводе ни в каком виде.
+var dangerousTags = map[string]bool{
+	"script": true, "iframe": true, "object": true, "embed": true, "svg": true, "math": true,
+	"style": true, "form": true, "base": true, "meta": true, "link": true, "frame": true,
+}
+
+func TestRenderNeutralizesXSS(t *testing.T) {
+	for _, tc := range xssCorpus {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := Render(tc.source, tc.mentions)
+			if err != nil {
+				t.Fatalf("Render: %v", err)
+			}
+
+			if problem := unsafeHTML(out); problem != "" {
+				t.Errorf("%s\nsource: %s\noutput: %s", problem, tc.source, out)
+			}
+		})
+	}
+}
+
+// unsafeHTML описывает первую найденную в s опасную конструкцию, "" — не найдено.
+func unsafeHTML(s string) string {
+	z := html.NewTokenizer(strings.NewReader(s))
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return ""
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := z.Token()
+			if dangerousTags[token.Data] {
+				return "element <" + token.Data + ">"
+			}
+
+			for _, attr := range token.Attr {
+				name := strings.ToLower(attr.Key)
+				value := strings.ToLower(strings.Join(strings.Fields(attr.Val), ""))
+
+				switch {
+				case strings.HasPrefix(name, "on"):
+					return "event handler " + name + " on <" + token.Data + ">"
+				case name == "style" || name == "srcdoc" || name == "formaction":
+					return "attribute " + name + " on <" + token.Data + ">"
+				case name == "href" || name == "src":
+					for _, scheme := range []string{"javascript:", "vbscript:", "data:"} {
+						if strings.HasPrefix(value, scheme) {
+							return "url " + attr.Val + " in " + name
+						}
+					}
+				case name == "class" && token.Data == "a" && attr.Val != "mention":
+					return "class " + attr.Val + " on <a>"
+				}
+			}
+		}
+	}
+}
+
+func TestRenderMention(t *testing.T) {
+	out, err := Render("hi @Alice and @bob", map[string]string{"alice": "/users/alice"})
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+
+	if !strings.Contains(out, `<a class="mention" href="/users/alice" rel="nofollow noreferrer">@Alice</a>`) {
+		t.Errorf("mention link not rendered: %s", out)
+	}
+	if !strings.Contains(out, "@bob") || strings.Contains(out, `href="/users/bob"`) {
+		t.Errorf("unknown mention must stay text: %s", out)
+	}
+}
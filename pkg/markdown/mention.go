@@ -0,0 +1,148 @@
+package markdown
+
+import (
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+	"strings"
+)
+
+// mentionsKey ключ состояния упоминаний в контексте разбора. Без него
+// @handle остаётся обычным текстом.
+var mentionsKey = parser.NewContextKey()
+
+// mentionState links сопоставляет handle в нижнем регистре с адресом профиля,
+// в handles собираются все встреченные handle.
+type mentionState struct {
+	links   map[string]string
+	handles []string
+}
+
+var kindMention = ast.NewNodeKind("Mention")
+
+// mentionNode упоминание пользователя, для которого известен адрес профиля.
+type mentionNode struct {
+	ast.BaseInline
+
+	handle string
+	url    string
+}
+
+func (n *mentionNode) Kind() ast.NodeKind {
+	return kindMention
+}
+
+func (n *mentionNode) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Handle": n.handle, "URL": n.url}, nil)
+}
+
+type mentionParser struct{}
+
+func (p mentionParser) Trigger() []byte {
+	return []byte{'@'}
+}
+
+// Parse распознаёт @handle, перед которым нет символа имени: так адреса
+// почты и a@b не считаются упоминаниями.
+func (p mentionParser) Parse(_ ast.Node, block text.Reader, pc parser.Context) ast.Node {
+	state, _ := pc.Get(mentionsKey).(*mentionState)
+	if state == nil {
+		return nil
+	}
+
+	if before := block.PrecendingCharacter(); before == '@' || isHandleChar(before) {
+		return nil
+	}
+
+	line, _ := block.PeekLine()
+	n := 1
+	for n < len(line) && isHandleChar(rune(line[n])) {
+		n++
+	}
+	if n == 1 {
+		return nil
+	}
+
+	written := string(line[1:n])
+	handle := strings.ToLower(written)
+	state.handles = append(state.handles, handle)
+
+	url, ok := state.links[handle]
+	if !ok {
+		return nil
+	}
+
+	block.Advance(n)
+	return &mentionNode{handle: written, url: url}
+}
+
+type mentionRenderer struct{}
+
+func (r mentionRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(kindMention, r.render)
+}
+
+// render выводит ссылку на профиль. Внутри другой ссылки упоминание остаётся
+// текстом: вложенные ссылки браузеры разбирают непредсказуемо.
+func (r mentionRenderer) render(w util.BufWriter, _ []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+
+	n := node.(*mentionNode)
+
+	for p := n.Parent(); p != nil; p = p.Parent() {
+		if p.Kind() == ast.KindLink || p.Kind() == ast.KindAutoLink {
+			_ = w.WriteByte('@')
+			_, _ = w.Write(util.EscapeHTML([]byte(n.handle)))
+			return ast.WalkContinue, nil
+		}
+	}
+
+	_, _ = w.WriteString(`<a class="mention" href="`)
+	_, _ = w.Write(util.EscapeHTML(util.URLEscape([]byte(n.url), true)))
+	_, _ = w.WriteString(`">@`)
+	_, _ = w.Write(util.EscapeHTML([]byte(n.handle)))
+	_, _ = w.WriteString(`</a>`)
+
+	return ast.WalkContinue, nil
+}
+
+type mentionExtension struct{}
+
+func (e mentionExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithInlineParsers(
+		util.Prioritized(mentionParser{}, 500),
+	))
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(mentionRenderer{}, 500),
+	))
+}
+
+// Mentions возвращает handle, упомянутые в source, в нижнем регистре без
+// повторов в порядке появления. Упоминания в коде не учитываются.
+func Mentions(source string) []string {
+	state := &mentionState{}
+
+	pc := parser.NewContext()
+	pc.Set(mentionsKey, state)
+	converter.Parser().Parse(text.NewReader([]byte(source)), parser.WithContext(pc))
+
+	handles := make([]string, 0, len(state.handles))
+	seen := make(map[string]struct{}, len(state.handles))
+	for _, h := range state.handles {
+		if _, ok := seen[h]; !ok {
+			seen[h] = struct{}{}
+			handles = append(handles, h)
+		}
+	}
+
+	return handles
+}
+
+func isHandleChar(r rune) bool {
+	return r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'
+}
@@ -0,0 +1,36 @@
+package markdown
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMentions(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   []string
+	}{
+		{name: "none", source: "plain text", want: []string{}},
+		{name: "lowercased in order", source: "hi @Alice and @bob", want: []string{"alice", "bob"}},
+		{name: "duplicates", source: "@Bob @bob @BOB", want: []string{"bob"}},
+		{name: "start of line", source: "@carol\n@dave", want: []string{"carol", "dave"}},
+		{name: "punctuation around", source: "(@eve), @frank! @grace.", want: []string{"eve", "frank", "grace"}},
+		{name: "digits and underscore", source: "@user_1", want: []string{"user_1"}},
+		{name: "email is not a mention", source: "write to a@example.com", want: []string{}},
+		{name: "double at", source: "@@alice", want: []string{}},
+		{name: "bare at", source: "meet @ noon", want: []string{}},
+		{name: "code span", source: "`@alice` and @bob", want: []string{"bob"}},
+		{name: "code block", source: "```\n@alice\n```\n\n@bob", want: []string{"bob"}},
+		{name: "link text", source: "[@alice](https://example.com)", want: []string{"alice"}},
+		{name: "non-latin handle", source: "@имя", want: []string{}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Mentions(tc.source); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Mentions(%q) = %q, want %q", tc.source, got, tc.want)
+			}
+		})
+	}
+}
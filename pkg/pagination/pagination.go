@@ -0,0 +1,56 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+var ErrInvalidToken = errors.New("invalid page token")
+
+// PageSize приводит запрошенный размер страницы к допустимому диапазону.
+func PageSize(requested int32) int {
+	switch {
+	case requested <= 0:
+		return DefaultPageSize
+	case requested > MaxPageSize:
+		return MaxPageSize
+	default:
+		return int(requested)
+	}
+}
+
+// EncodeToken упаковывает курсор в непрозрачную для клиента строку.
+func EncodeToken(cursor any) (string, error) {
+	raw, err := json.Marshal(cursor)
+	if err != nil {
+		return "", fmt.Errorf("EncodeToken - json.Marshal - %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// DecodeToken распаковывает курсор. Пустой токен означает первую страницу
+// и оставляет cursor без изменений.
+func DecodeToken(token string, cursor any) error {
+	if token == "" {
+		return nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return ErrInvalidToken
+	}
+
+	if err = json.Unmarshal(raw, cursor); err != nil {
+		return ErrInvalidToken
+	}
+
+	return nil
+}
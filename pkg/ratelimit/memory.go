@@ -0,0 +1,67 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+}
+
+type MemoryBackend struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	now     func() time.Time
+}
+
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+func (b *MemoryBackend) Take(_ context.Context, key string, rule Rule) (Result, error) {
+	now := b.now()
+	capacity := rule.Capacity()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	bk, ok := b.buckets[key]
+	if !ok {
+		bk = &bucket{tokens: capacity, updatedAt: now}
+		b.buckets[key] = bk
+	}
+
+	elapsed := now.Sub(bk.updatedAt).Seconds()
+	bk.tokens = math.Min(capacity, bk.tokens+elapsed*rule.Rate())
+	bk.updatedAt = now
+
+	if bk.tokens < 1 {
+		return Result{Allowed: false, RetryAfter: retryAfter(bk.tokens, rule)}, nil
+	}
+
+	bk.tokens--
+
+	return Result{Allowed: true, Remaining: int(bk.tokens)}, nil
+}
+
+func (b *MemoryBackend) Cleanup(_ context.Context, idle time.Duration) error {
+	threshold := b.now().Add(-idle)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for key, bk := range b.buckets {
+		if bk.updatedAt.Before(threshold) {
+			delete(b.buckets, key)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,151 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// fakeClock время, которое тест двигает вручную.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func newTestBackend() (*MemoryBackend, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	backend := NewMemoryBackend()
+	backend.now = clock.Now
+	return backend, clock
+}
+
+// step один вызов Take после сдвига часов на advance.
+type step struct {
+	advance    time.Duration
+	allowed    bool
+	remaining  int
+	retryAfter time.Duration
+}
+
+func TestMemoryBackendTake(t *testing.T) {
+	perSecond := Rule{Limit: 60, Period: time.Minute, Burst: 3}
+
+	tests := []struct {
+		name  string
+		rule  Rule
+		steps []step
+	}{
+		{
+			name: "burst then deny",
+			rule: perSecond,
+			steps: []step{
+				{allowed: true, remaining: 2},
+				{allowed: true, remaining: 1},
+				{allowed: true, remaining: 0},
+				{allowed: false, retryAfter: time.Second},
+			},
+		},
+		{
+			name: "refill by elapsed time",
+			rule: perSecond,
+			steps: []step{
+				{allowed: true, remaining: 2},
+				{allowed: true, remaining: 1},
+				{allowed: true, remaining: 0},
+				{advance: 2 * time.Second, allowed: true, remaining: 1},
+				{allowed: true, remaining: 0},
+				{allowed: false, retryAfter: time.Second},
+			},
+		},
+		{
+			name: "partial token shortens retry",
+			rule: perSecond,
+			steps: []step{
+				{allowed: true, remaining: 2},
+				{allowed: true, remaining: 1},
+				{allowed: true, remaining: 0},
+				{advance: 250 * time.Millisecond, allowed: false, retryAfter: 750 * time.Millisecond},
+				{advance: 750 * time.Millisecond, allowed: true, remaining: 0},
+			},
+		},
+		{
+			name: "refill capped by burst",
+			rule: perSecond,
+			steps: []step{
+				{allowed: true, remaining: 2},
+				{advance: time.Hour, allowed: true, remaining: 2},
+				{allowed: true, remaining: 1},
+				{allowed: true, remaining: 0},
+				{allowed: false, retryAfter: time.Second},
+			},
+		},
+		{
+			name: "capacity defaults to limit",
+			rule: Rule{Limit: 2, Period: time.Minute},
+			steps: []step{
+				{allowed: true, remaining: 1},
+				{allowed: true, remaining: 0},
+				{allowed: false, retryAfter: 30 * time.Second},
+				{advance: 30 * time.Second, allowed: true, remaining: 0},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			backend, clock := newTestBackend()
+
+			for i, s := range tc.steps {
+				clock.now = clock.now.Add(s.advance)
+
+				got, err := backend.Take(context.Background(), "key", tc.rule)
+				if err != nil {
+					t.Fatalf("step %d: Take: %v", i, err)
+				}
+
+				want := Result{Allowed: s.allowed, Remaining: s.remaining, RetryAfter: s.retryAfter}
+				if got != want {
+					t.Fatalf("step %d: Take = %+v, want %+v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestMemoryBackendKeysAreIndependent(t *testing.T) {
+	backend, _ := newTestBackend()
+	rule := Rule{Limit: 1, Period: time.Minute}
+
+	if got, _ := backend.Take(context.Background(), "a", rule); !got.Allowed {
+		t.Fatal("first take for a must be allowed")
+	}
+	if got, _ := backend.Take(context.Background(), "a", rule); got.Allowed {
+		t.Fatal("second take for a must be denied")
+	}
+	if got, _ := backend.Take(context.Background(), "b", rule); !got.Allowed {
+		t.Fatal("bucket of b must not be affected by a")
+	}
+}
+
+func TestMemoryBackendCleanup(t *testing.T) {
+	backend, clock := newTestBackend()
+	rule := Rule{Limit: 1, Period: time.Minute}
+
+	_, _ = backend.Take(context.Background(), "idle", rule)
+	clock.now = clock.now.Add(10 * time.Minute)
+	_, _ = backend.Take(context.Background(), "active", rule)
+
+	if err := backend.Cleanup(context.Background(), 5*time.Minute); err != nil {
+		t.Fatalf("Cleanup: %v", err)
+	}
+
+	if _, ok := backend.buckets["idle"]; ok {
+		t.Error("idle bucket must be removed")
+	}
+	if _, ok := backend.buckets["active"]; !ok {
+		t.Error("active bucket must be kept")
+	}
+}
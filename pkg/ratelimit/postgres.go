@@ -0,0 +1,60 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"time"
+)
+
+// Пополнение и списание выполняются одним UPSERT, поэтому бакет
+// корректно разделяется между несколькими инстансами сервиса.
+const takeQuery = `
+INSERT INTO rate_limit_buckets AS b (key, tokens, allowed, updated_at)
+VALUES ($1, $2::float8 - 1, TRUE, now())
+ON CONFLICT (key) DO UPDATE SET
+    tokens     = CASE
+                     WHEN LEAST($2::float8, b.tokens + EXTRACT(EPOCH FROM now() - b.updated_at)::float8 * $3::float8) >= 1
+                         THEN LEAST($2::float8, b.tokens + EXTRACT(EPOCH FROM now() - b.updated_at)::float8 * $3::float8) - 1
+                     ELSE LEAST($2::float8, b.tokens + EXTRACT(EPOCH FROM now() - b.updated_at)::float8 * $3::float8)
+                 END,
+    allowed    = LEAST($2::float8, b.tokens + EXTRACT(EPOCH FROM now() - b.updated_at)::float8 * $3::float8) >= 1,
+    updated_at = now()
+RETURNING tokens, allowed`
+
+const cleanupQuery = `DELETE FROM rate_limit_buckets WHERE updated_at < now() - make_interval(secs => $1)`
+
+type PostgresBackend struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresBackend(db *pgxpool.Pool) *PostgresBackend {
+	return &PostgresBackend{db: db}
+}
+
+func (b *PostgresBackend) Take(ctx context.Context, key string, rule Rule) (Result, error) {
+	var (
+		tokens  float64
+		allowed bool
+	)
+
+	err := b.db.QueryRow(ctx, takeQuery, key, rule.Capacity(), rule.Rate()).Scan(&tokens, &allowed)
+	if err != nil {
+		return Result{}, fmt.Errorf("PostgresBackend.Take - QueryRow - %w", err)
+	}
+
+	if !allowed {
+		return Result{Allowed: false, RetryAfter: retryAfter(tokens, rule)}, nil
+	}
+
+	return Result{Allowed: true, Remaining: int(tokens)}, nil
+}
+
+func (b *PostgresBackend) Cleanup(ctx context.Context, idle time.Duration) error {
+	_, err := b.db.Exec(ctx, cleanupQuery, idle.Seconds())
+	if err != nil {
+		return fmt.Errorf("PostgresBackend.Cleanup - Exec - %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"time"
+)
+
+const (
+	BackendMemory   = "memory"
+	BackendPostgres = "postgres"
+)
+
+const (
+	KeyUser   = "user"
+	KeyIP     = "ip"
+	KeyAPIKey = "api_key"
+)
+
+var ErrUnknownBackend = errors.New("unknown rate limit backend")
+
+type Config struct {
+	Enabled         bool            `yaml:"enabled" env:"RATE_LIMIT_ENABLED"`
+	Backend         string          `yaml:"backend" env:"RATE_LIMIT_BACKEND" env-default:"memory"`
+	TrustedProxies  []string        `yaml:"trusted_proxies"`
+	CleanupInterval time.Duration   `yaml:"cleanup_interval"`
+	Default         Rule            `yaml:"default"`
+	Methods         map[string]Rule `yaml:"methods"`
+}
+
+// Rule описывает token bucket: Limit запросов за Period с запасом Burst.
+type Rule struct {
+	Key    string        `yaml:"key"`
+	Limit  int           `yaml:"limit"`
+	Period time.Duration `yaml:"period"`
+	Burst  int           `yaml:"burst"`
+}
+
+func (r Rule) Enabled() bool {
+	return r.Limit > 0 && r.Period > 0
+}
+
+// Rate возвращает скорость пополнения в токенах в секунду.
+func (r Rule) Rate() float64 {
+	return float64(r.Limit) / r.Period.Seconds()
+}
+
+func (r Rule) Capacity() float64 {
+	if r.Burst > 0 {
+		return float64(r.Burst)
+	}
+	return float64(r.Limit)
+}
+
+type Result struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration
+}
+
+type Backend interface {
+	Take(ctx context.Context, key string, rule Rule) (Result, error)
+	Cleanup(ctx context.Context, idle time.Duration) error
+}
+
+func retryAfter(tokens float64, rule Rule) time.Duration {
+	if tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - tokens) / rule.Rate() * float64(time.Second))
+}
+
+func NewBackend(cfg Config, db *pgxpool.Pool) (Backend, error) {
+	switch cfg.Backend {
+	case "", BackendMemory:
+		return NewMemoryBackend(), nil
+	case BackendPostgres:
+		return NewPostgresBackend(db), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownBackend, cfg.Backend)
+	}
+}
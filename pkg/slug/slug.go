@@ -0,0 +1,38 @@
+package slug
+
+import (
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+	"strings"
+	"unicode"
+)
+
+var folder = cases.Fold()
+
+// Make приводит строку к slug: регистр сворачивается, буквы и цифры любых
+// алфавитов сохраняются, остальные символы схлопываются в один дефис.
+// "Go & Postgres" -> "go-postgres", "Базы Данных" -> "базы-данных".
+func Make(s string) string {
+	s = norm.NFKC.String(folder.String(s))
+
+	var (
+		b   strings.Builder
+		sep bool
+	)
+	b.Grow(len(s))
+
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			sep = true
+			continue
+		}
+
+		if sep && b.Len() > 0 {
+			b.WriteByte('-')
+		}
+		sep = false
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,30 @@
+package slug
+
+import "testing"
+
+func TestMake(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "Go & Postgres", want: "go-postgres"},
+		{in: "Базы Данных", want: "базы-данных"},
+		{in: "  --Hello__World--  ", want: "hello-world"},
+		{in: "v1.2", want: "v1-2"},
+		{in: "C++", want: "c"},
+		{in: "Straße", want: "strasse"},
+		{in: "ＧＯ", want: "go"},
+		{in: "cafe\u0301", want: "caf\u00e9"},
+		{in: "日本語", want: "日本語"},
+		{in: "", want: ""},
+		{in: "!!! ???", want: ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.in, func(t *testing.T) {
+			if got := Make(tc.in); got != tc.want {
+				t.Errorf("Make(%q) = %q, want %q", tc.in, got, tc.want)
+			}
+		})
+	}
+}
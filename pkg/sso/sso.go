@@ -0,0 +1,92 @@
+package sso
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+var ErrUnknownProvider = errors.New("unknown identity provider")
+
+type ProviderConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Issuer  string `yaml:"issuer"`
+	// ClientSecret можно не указывать для публичного клиента: код защищён PKCE.
+	ClientID     string   `yaml:"client_id"`
+	ClientSecret string   `yaml:"client_secret"`
+	RedirectURL  string   `yaml:"redirect_url"`
+	Scopes       []string `yaml:"scopes"`
+}
+
+type Config struct {
+	Providers map[string]ProviderConfig `yaml:"providers"`
+	// StateTTL время, за которое пользователь должен вернуться от провайдера.
+	StateTTL time.Duration `yaml:"state_ttl"`
+	// CleanupInterval период удаления просроченных state.
+	CleanupInterval time.Duration `yaml:"cleanup_interval"`
+}
+
+// Identity учётная запись пользователя у внешнего провайдера.
+type Identity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// Provider внешний провайдер входа. Кроме OIDC можно подключить любой
+// провайдер, поддерживающий authorization code flow с PKCE.
+type Provider interface {
+	AuthCodeURL(ctx context.Context, state, nonce, codeVerifier string) (string, error)
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (Identity, error)
+}
+
+type Registry struct {
+	providers map[string]Provider
+}
+
+func NewRegistry(cfg Config) (*Registry, error) {
+	r := &Registry{providers: make(map[string]Provider, len(cfg.Providers))}
+
+	for name, providerCfg := range cfg.Providers {
+		if !providerCfg.Enabled {
+			continue
+		}
+
+		provider, err := NewOIDCProvider(name, providerCfg)
+		if err != nil {
+			return nil, fmt.Errorf("NewRegistry - provider %q - %w", name, err)
+		}
+
+		r.providers[name] = provider
+	}
+
+	return r, nil
+}
+
+// Register добавляет провайдер вручную, например, не-OIDC реализацию.
+func (r *Registry) Register(name string, provider Provider) {
+	r.providers[name] = provider
+}
+
+func (r *Registry) Get(name string) (Provider, error) {
+	provider, ok := r.providers[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownProvider, name)
+	}
+
+	return provider, nil
+}
+
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
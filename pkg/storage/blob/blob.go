@@ -0,0 +1,66 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+const (
+	DriverLocal = "local"
+	DriverS3    = "s3"
+)
+
+var (
+	ErrUnknownDriver = errors.New("unknown blob storage driver")
+	ErrNotFound      = errors.New("blob not found")
+	ErrInvalidKey    = errors.New("invalid blob key")
+)
+
+var keyRe = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9/_.-]*$`)
+
+type Config struct {
+	Driver string      `yaml:"driver" env:"BLOB_DRIVER" env-default:"local"`
+	Local  LocalConfig `yaml:"local"`
+	S3     S3Config    `yaml:"s3"`
+}
+
+// Object метаданные объекта, известные хранилищу.
+type Object struct {
+	Size        int64
+	ContentType string
+}
+
+// Storage хранилище неизменяемых объектов по ключу. Запись по существующему
+// ключу перезаписывает объект целиком.
+type Storage interface {
+	// Put сохраняет size байт из r. Драйверы, которым нужно перечитать тело
+	// (например, для подписи запроса), ожидают io.ReadSeeker.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get открывает объект на чтение, для отсутствующего возвращает ErrNotFound.
+	Get(ctx context.Context, key string) (io.ReadCloser, Object, error)
+	// Delete удаляет объект. Удаление отсутствующего объекта не ошибка.
+	Delete(ctx context.Context, key string) error
+}
+
+func New(ctx context.Context, cfg Config) (Storage, error) {
+	switch cfg.Driver {
+	case "", DriverLocal:
+		return NewLocalStorage(cfg.Local)
+	case DriverS3:
+		return NewS3Storage(ctx, cfg.S3)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownDriver, cfg.Driver)
+	}
+}
+
+// validateKey ключ — относительный путь без выхода за пределы хранилища.
+func validateKey(key string) error {
+	if !keyRe.MatchString(key) || strings.Contains(key, "..") || strings.Contains(key, "//") {
+		return fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+	return nil
+}
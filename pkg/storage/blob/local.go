@@ -0,0 +1,125 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+type LocalConfig struct {
+	Dir string `yaml:"dir" env:"BLOB_LOCAL_DIR"`
+}
+
+// LocalStorage хранит объекты файлами в каталоге. Тип содержимого хранится
+// в соседнем файле с суффиксом .type.
+type LocalStorage struct {
+	dir string
+}
+
+func NewLocalStorage(cfg LocalConfig) (*LocalStorage, error) {
+	dir := cfg.Dir
+	if dir == "" {
+		dir = filepath.Join(os.TempDir(), "blobs")
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("NewLocalStorage - MkdirAll - %w", err)
+	}
+
+	return &LocalStorage{dir: dir}, nil
+}
+
+// Put пишет во временный файл и переименовывает его, поэтому читатели никогда
+// не видят объект записанным наполовину.
+func (s *LocalStorage) Put(_ context.Context, key string, r io.Reader, size int64, contentType string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("LocalStorage.Put - MkdirAll - %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("LocalStorage.Put - CreateTemp - %w", err)
+	}
+	defer os.Remove(tmp.Name()) //nolint:errcheck
+
+	n, err := io.Copy(tmp, r)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("LocalStorage.Put - Copy - %w", err)
+	}
+	if n != size {
+		return fmt.Errorf("LocalStorage.Put - wrote %d bytes, expected %d", n, size)
+	}
+
+	if err = os.WriteFile(path+".type", []byte(contentType), 0o644); err != nil {
+		return fmt.Errorf("LocalStorage.Put - WriteFile - %w", err)
+	}
+
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("LocalStorage.Put - Rename - %w", err)
+	}
+
+	return nil
+}
+
+func (s *LocalStorage) Get(_ context.Context, key string) (io.ReadCloser, Object, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, Object{}, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, Object{}, ErrNotFound
+		}
+		return nil, Object{}, fmt.Errorf("LocalStorage.Get - Open - %w", err)
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close() //nolint:errcheck
+		return nil, Object{}, fmt.Errorf("LocalStorage.Get - Stat - %w", err)
+	}
+
+	contentType, err := os.ReadFile(path + ".type")
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		f.Close() //nolint:errcheck
+		return nil, Object{}, fmt.Errorf("LocalStorage.Get - ReadFile - %w", err)
+	}
+
+	return f, Object{Size: info.Size(), ContentType: string(contentType)}, nil
+}
+
+func (s *LocalStorage) Delete(_ context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range []string{path, path + ".type"} {
+		if err = os.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("LocalStorage.Delete - Remove - %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *LocalStorage) path(key string) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
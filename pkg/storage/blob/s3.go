@@ -0,0 +1,118 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"io"
+)
+
+// S3Config подходит для AWS S3 и совместимых хранилищ. Для MinIO и других
+// локальных замен задаётся Endpoint и обычно UsePathStyle.
+type S3Config struct {
+	Endpoint     string `yaml:"endpoint" env:"BLOB_S3_ENDPOINT"`
+	Region       string `yaml:"region" env:"BLOB_S3_REGION" env-default:"us-east-1"`
+	Bucket       string `yaml:"bucket" env:"BLOB_S3_BUCKET"`
+	AccessKey    string `env:"BLOB_S3_ACCESS_KEY"`
+	SecretKey    string `env:"BLOB_S3_SECRET_KEY"`
+	UsePathStyle bool   `yaml:"use_path_style" env:"BLOB_S3_USE_PATH_STYLE"`
+	// Prefix добавляется ко всем ключам, чтобы делить бакет с другими сервисами.
+	Prefix string `yaml:"prefix" env:"BLOB_S3_PREFIX"`
+}
+
+type S3Storage struct {
+	client *s3.Client
+	bucket string
+	prefix string
+}
+
+// NewS3Storage проверяет доступность бакета при старте, чтобы ошибка
+// конфигурации не проявилась только на первой загрузке.
+func NewS3Storage(ctx context.Context, cfg S3Config) (*S3Storage, error) {
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("NewS3Storage - bucket is required")
+	}
+
+	options := s3.Options{
+		Region:       cfg.Region,
+		UsePathStyle: cfg.UsePathStyle,
+	}
+	if cfg.Endpoint != "" {
+		options.BaseEndpoint = aws.String(cfg.Endpoint)
+	}
+	if cfg.AccessKey != "" {
+		options.Credentials = aws.NewCredentialsCache(aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+			return aws.Credentials{AccessKeyID: cfg.AccessKey, SecretAccessKey: cfg.SecretKey, Source: "config"}, nil
+		}))
+	}
+
+	storage := &S3Storage{
+		client: s3.New(options),
+		bucket: cfg.Bucket,
+		prefix: cfg.Prefix,
+	}
+
+	if _, err := storage.client.HeadBucket(ctx, &s3.HeadBucketInput{Bucket: aws.String(cfg.Bucket)}); err != nil {
+		return nil, fmt.Errorf("NewS3Storage - HeadBucket - %w", err)
+	}
+
+	return storage, nil
+}
+
+func (s *S3Storage) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(s.bucket),
+		Key:           aws.String(s.prefix + key),
+		Body:          r,
+		ContentLength: aws.Int64(size),
+		ContentType:   aws.String(contentType),
+	})
+	if err != nil {
+		return fmt.Errorf("S3Storage.Put - PutObject - %w", err)
+	}
+
+	return nil
+}
+
+func (s *S3Storage) Get(ctx context.Context, key string) (io.ReadCloser, Object, error) {
+	if err := validateKey(key); err != nil {
+		return nil, Object{}, err
+	}
+
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.prefix + key),
+	})
+	if err != nil {
+		var noSuchKey *types.NoSuchKey
+		if errors.As(err, &noSuchKey) {
+			return nil, Object{}, ErrNotFound
+		}
+		return nil, Object{}, fmt.Errorf("S3Storage.Get - GetObject - %w", err)
+	}
+
+	return out.Body, Object{Size: aws.ToInt64(out.ContentLength), ContentType: aws.ToString(out.ContentType)}, nil
+}
+
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.prefix + key),
+	})
+	if err != nil {
+		return fmt.Errorf("S3Storage.Delete - DeleteObject - %w", err)
+	}
+
+	return nil
+}
@@ -3,23 +3,42 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// Минимальный statement_timeout, который выставляется по дедлайну запроса.
+	minStatementTimeout = 10 * time.Millisecond
+	// overrideRatio statement_timeout переопределяется, только если до дедлайна
+	// осталось меньше этой доли настроенного значения: иначе почти каждое
+	// соединение платило бы за лишние SET и RESET.
+	overrideRatio = 0.8
+	// overrideTimeout сколько ждать SET и RESET.
+	overrideTimeout = time.Second
 )
 
 type Config struct {
-	Host     string `env:"POSTGRES_HOST" env-required:"true"`
-	Port     int    `env:"POSTGRES_PORT" env-required:"true"`
-	User     string `env:"POSTGRES_USER" env-required:"true"`
-	Password string `env:"POSTGRES_PASSWORD" env-required:"true"`
-	DBName   string `env:"POSTGRES_DB" env-required:"true"`
-	SSLMode  string `env:"POSTGRES_SSLMODE" env-required:"true"`
-	MaxConns int32  `yaml:"max_conns"`
-	MinConns int32  `yaml:"min_conns"`
+	Host             string        `env:"POSTGRES_HOST" env-required:"true"`
+	Port             int           `env:"POSTGRES_PORT" env-required:"true"`
+	User             string        `env:"POSTGRES_USER" env-required:"true"`
+	Password         string        `env:"POSTGRES_PASSWORD" env-required:"true"`
+	DBName           string        `env:"POSTGRES_DB" env-required:"true"`
+	SSLMode          string        `env:"POSTGRES_SSLMODE" env-required:"true"`
+	MaxConns         int32         `yaml:"max_conns"`
+	MinConns         int32         `yaml:"min_conns"`
+	StatementTimeout time.Duration `yaml:"statement_timeout"`
 }
 
 type Storage struct {
 	DB  *pgxpool.Pool
 	cfg Config
+
+	// Соединения, для которых statement_timeout переопределён по дедлайну запроса.
+	overridden sync.Map
 }
 
 func NewStorage(cfg Config) *Storage {
@@ -43,6 +62,13 @@ func (s *Storage) Connect(ctx context.Context) error {
 
 	pgxConf.MaxConns = s.cfg.MaxConns
 	pgxConf.MinConns = s.cfg.MinConns
+	pgxConf.ConnConfig.Tracer = tracer{}
+	pgxConf.BeforeAcquire = s.beforeAcquire
+	pgxConf.AfterRelease = s.afterRelease
+
+	if s.cfg.StatementTimeout > 0 {
+		pgxConf.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(s.cfg.StatementTimeout.Milliseconds(), 10)
+	}
 
 	db, err := pgxpool.NewWithConfig(ctx, pgxConf)
 	if err != nil {
@@ -62,3 +88,48 @@ func (s *Storage) Connect(ctx context.Context) error {
 func (s *Storage) Close() {
 	s.DB.Close()
 }
+
+// beforeAcquire ограничивает statement_timeout соединения оставшимся временем
+// запроса, чтобы Postgres сам прерывал запросы, результат которых уже никому не нужен.
+// Истёкший запрос соединение не трогает: его запросы прервёт отмена контекста.
+func (s *Storage) beforeAcquire(ctx context.Context, conn *pgx.Conn) bool {
+	deadline, ok := ctx.Deadline()
+	if !ok || ctx.Err() != nil {
+		return true
+	}
+
+	timeout := time.Until(deadline)
+	if s.cfg.StatementTimeout > 0 && timeout >= time.Duration(float64(s.cfg.StatementTimeout)*overrideRatio) {
+		return true
+	}
+	if timeout < minStatementTimeout {
+		timeout = minStatementTimeout
+	}
+
+	// Служебный запрос не учитываем во времени выполнения запросов. Отмена
+	// запроса посреди SET оборвала бы соединение, поэтому у SET свой таймаут.
+	ctx, cancel := context.WithTimeout(context.WithValue(context.WithoutCancel(ctx), timingsKey{}, nil), overrideTimeout)
+	defer cancel()
+
+	_, err := conn.Exec(ctx, "SET statement_timeout = "+strconv.FormatInt(timeout.Milliseconds(), 10))
+	if err != nil {
+		return false
+	}
+
+	s.overridden.Store(conn, struct{}{})
+
+	return true
+}
+
+func (s *Storage) afterRelease(conn *pgx.Conn) bool {
+	if _, ok := s.overridden.LoadAndDelete(conn); !ok {
+		return true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), overrideTimeout)
+	defer cancel()
+
+	_, err := conn.Exec(ctx, "RESET statement_timeout")
+
+	return err == nil
+}
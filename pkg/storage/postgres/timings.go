@@ -0,0 +1,113 @@
+package postgres
+
+import (
+	"context"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"sync/atomic"
+	"time"
+)
+
+type timingsKey struct{}
+
+type traceStartKey struct{}
+
+// Timings накапливает время, проведённое запросом в базе данных:
+// ожидание соединения из пула и выполнение запросов.
+type Timings struct {
+	acquire  atomic.Int64
+	query    atomic.Int64
+	acquires atomic.Int64
+	queries  atomic.Int64
+}
+
+func WithTimings(ctx context.Context) (context.Context, *Timings) {
+	t := &Timings{}
+	return context.WithValue(ctx, timingsKey{}, t), t
+}
+
+func TimingsFromContext(ctx context.Context) (*Timings, bool) {
+	t, ok := ctx.Value(timingsKey{}).(*Timings)
+	return t, ok
+}
+
+func (t *Timings) Acquire() time.Duration {
+	return time.Duration(t.acquire.Load())
+}
+
+func (t *Timings) Query() time.Duration {
+	return time.Duration(t.query.Load())
+}
+
+func (t *Timings) Acquires() int64 {
+	return t.acquires.Load()
+}
+
+func (t *Timings) Queries() int64 {
+	return t.queries.Load()
+}
+
+// tracer заполняет Timings из контекста запроса.
+type tracer struct{}
+
+var (
+	_ pgx.QueryTracer       = tracer{}
+	_ pgx.BatchTracer       = tracer{}
+	_ pgxpool.AcquireTracer = tracer{}
+)
+
+func (tracer) start(ctx context.Context) context.Context {
+	if _, ok := TimingsFromContext(ctx); !ok {
+		return ctx
+	}
+	return context.WithValue(ctx, traceStartKey{}, time.Now())
+}
+
+func (tracer) elapsed(ctx context.Context) (*Timings, time.Duration, bool) {
+	t, ok := TimingsFromContext(ctx)
+	if !ok {
+		return nil, 0, false
+	}
+
+	start, ok := ctx.Value(traceStartKey{}).(time.Time)
+	if !ok {
+		return nil, 0, false
+	}
+
+	return t, time.Since(start), true
+}
+
+func (tr tracer) TraceAcquireStart(ctx context.Context, _ *pgxpool.Pool, _ pgxpool.TraceAcquireStartData) context.Context {
+	return tr.start(ctx)
+}
+
+func (tr tracer) TraceAcquireEnd(ctx context.Context, _ *pgxpool.Pool, _ pgxpool.TraceAcquireEndData) {
+	if t, d, ok := tr.elapsed(ctx); ok {
+		t.acquire.Add(int64(d))
+		t.acquires.Add(1)
+	}
+}
+
+func (tr tracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, _ pgx.TraceQueryStartData) context.Context {
+	return tr.start(ctx)
+}
+
+func (tr tracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, _ pgx.TraceQueryEndData) {
+	if t, d, ok := tr.elapsed(ctx); ok {
+		t.query.Add(int64(d))
+		t.queries.Add(1)
+	}
+}
+
+func (tr tracer) TraceBatchStart(ctx context.Context, _ *pgx.Conn, _ pgx.TraceBatchStartData) context.Context {
+	return tr.start(ctx)
+}
+
+func (tracer) TraceBatchQuery(context.Context, *pgx.Conn, pgx.TraceBatchQueryData) {}
+
+func (tr tracer) TraceBatchEnd(ctx context.Context, _ *pgx.Conn, _ pgx.TraceBatchEndData) {
+	if t, d, ok := tr.elapsed(ctx); ok {
+		t.query.Add(int64(d))
+		t.queries.Add(1)
+	}
+}
@@ -0,0 +1,96 @@
+package totp
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret ключ тестовых векторов RFC 6238 для SHA-1, "12345678901234567890" в base32.
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCodeRFC6238(t *testing.T) {
+	// В RFC коды восьмизначные, здесь — их последние шесть цифр.
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{unix: 59, code: "287082"},
+		{unix: 1111111109, code: "081804"},
+		{unix: 1111111111, code: "050471"},
+		{unix: 1234567890, code: "005924"},
+		{unix: 2000000000, code: "279037"},
+		{unix: 20000000000, code: "353130"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.code, func(t *testing.T) {
+			got, err := Code(rfcSecret, Step(time.Unix(tc.unix, 0)))
+			if err != nil {
+				t.Fatalf("Code: %v", err)
+			}
+			if got != tc.code {
+				t.Errorf("Code at %d = %s, want %s", tc.unix, got, tc.code)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	current := Step(now)
+
+	codeAt := func(step int64) string {
+		code, err := Code(rfcSecret, step)
+		if err != nil {
+			t.Fatalf("Code: %v", err)
+		}
+		return code
+	}
+
+	tests := []struct {
+		name     string
+		secret   string
+		code     string
+		skew     int
+		wantStep int64
+		wantOK   bool
+	}{
+		{name: "current step", secret: rfcSecret, code: codeAt(current), skew: 0, wantStep: current, wantOK: true},
+		{name: "previous step within skew", secret: rfcSecret, code: codeAt(current - 1), skew: 1, wantStep: current - 1, wantOK: true},
+		{name: "next step within skew", secret: rfcSecret, code: codeAt(current + 1), skew: 1, wantStep: current + 1, wantOK: true},
+		{name: "previous step without skew", secret: rfcSecret, code: codeAt(current - 1), skew: 0},
+		{name: "beyond skew", secret: rfcSecret, code: codeAt(current + 2), skew: 1},
+		{name: "surrounding spaces", secret: rfcSecret, code: " " + codeAt(current) + "\n", skew: 0, wantStep: current, wantOK: true},
+		{name: "lowercase secret", secret: strings.ToLower(rfcSecret), code: codeAt(current), skew: 0, wantStep: current, wantOK: true},
+		{name: "short code", secret: rfcSecret, code: codeAt(current)[:5], skew: 1},
+		{name: "long code", secret: rfcSecret, code: codeAt(current) + "0", skew: 1},
+		{name: "invalid secret", secret: "not base32!", code: "123456", skew: 1},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			step, ok := Validate(tc.secret, tc.code, now, tc.skew)
+			if ok != tc.wantOK || step != tc.wantStep {
+				t.Errorf("Validate = (%d, %v), want (%d, %v)", step, ok, tc.wantStep, tc.wantOK)
+			}
+		})
+	}
+}
+
+func TestGenerateSecretRoundTrip(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret: %v", err)
+	}
+
+	now := time.Now()
+	code, err := Code(secret, Step(now))
+	if err != nil {
+		t.Fatalf("Code: %v", err)
+	}
+
+	if _, ok := Validate(secret, code, now, 0); !ok {
+		t.Errorf("code %s of a generated secret is rejected", code)
+	}
+}
@@ -0,0 +1,443 @@
+package webp
+
+import (
+	"container/heap"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/draw"
+	"io"
+)
+
+// maxDimension предельная ширина и высота изображения VP8L.
+const maxDimension = 1 << 14
+
+const (
+	numLiteralCodes  = 256
+	numLengthCodes   = 24
+	numDistanceCodes = 40
+	// maxCopyLength предельная длина одного повтора.
+	maxCopyLength = 4096
+	// minCopyLength короче повтор дороже литералов.
+	minCopyLength = 3
+
+	maxCodeLength           = 15
+	maxCodeLengthCodeLength = 7
+
+	// Коды расстояний 1 и 2 в VP8L означают пиксель сверху и пиксель слева.
+	distanceCodeUp   = 1
+	distanceCodeLeft = 2
+)
+
+var ErrTooLarge = errors.New("image is too large for webp")
+
+// codeLengthCodeOrder порядок, в котором записываются длины кода длин.
+var codeLengthCodeOrder = [...]int{17, 18, 0, 1, 2, 3, 4, 5, 16, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+
+// Encode записывает img в формате WebP без потерь (VP8L). Преобразования и
+// цветовой кэш не используются: сжатие дают префиксные коды и повторы пикселя
+// слева или сверху, чего достаточно для превью и графики.
+func Encode(w io.Writer, img image.Image) error {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width < 1 || height < 1 || width > maxDimension || height > maxDimension {
+		return ErrTooLarge
+	}
+
+	nrgba := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(nrgba, nrgba.Bounds(), img, bounds.Min, draw.Src)
+
+	pixels := make([]uint32, width*height)
+	hasAlpha := false
+	for i := range pixels {
+		p := nrgba.Pix[i*4 : i*4+4]
+		pixels[i] = uint32(p[3])<<24 | uint32(p[0])<<16 | uint32(p[1])<<8 | uint32(p[2])
+		if p[3] != 0xff {
+			hasAlpha = true
+		}
+	}
+
+	data := encodeVP8L(pixels, width, height, hasAlpha)
+
+	pad := len(data) & 1
+	header := make([]byte, 20)
+	copy(header[0:], "RIFF")
+	binary.LittleEndian.PutUint32(header[4:], uint32(4+8+len(data)+pad))
+	copy(header[8:], "WEBPVP8L")
+	binary.LittleEndian.PutUint32(header[16:], uint32(len(data)))
+
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if pad == 1 {
+		if _, err := w.Write([]byte{0}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// token литерал пикселя или повтор length пикселей по коду расстояния distance.
+type token struct {
+	argb     uint32
+	length   int
+	distance int
+}
+
+func encodeVP8L(pixels []uint32, width, height int, hasAlpha bool) []byte {
+	tokens := tokenize(pixels, width)
+
+	var (
+		green    = make([]uint32, numLiteralCodes+numLengthCodes)
+		red      = make([]uint32, numLiteralCodes)
+		blue     = make([]uint32, numLiteralCodes)
+		alpha    = make([]uint32, numLiteralCodes)
+		distance = make([]uint32, numDistanceCodes)
+	)
+	for _, t := range tokens {
+		if t.length == 0 {
+			green[t.argb>>8&0xff]++
+			red[t.argb>>16&0xff]++
+			blue[t.argb&0xff]++
+			alpha[t.argb>>24]++
+			continue
+		}
+		lengthCode, _, _ := prefixEncode(t.length)
+		distanceCode, _, _ := prefixEncode(t.distance)
+		green[numLiteralCodes+lengthCode]++
+		distance[distanceCode]++
+	}
+
+	bw := &bitWriter{}
+	bw.write(0x2f, 8)
+	bw.write(uint32(width-1), 14)
+	bw.write(uint32(height-1), 14)
+	if hasAlpha {
+		bw.write(1, 1)
+	} else {
+		bw.write(0, 1)
+	}
+	bw.write(0, 3) // версия
+
+	bw.write(0, 1) // без преобразований
+	bw.write(0, 1) // без цветового кэша
+	bw.write(0, 1) // один набор префиксных кодов на всё изображение
+
+	codes := make([]prefixCode, 0, 5)
+	for _, hist := range [][]uint32{green, red, blue, alpha, distance} {
+		codes = append(codes, writePrefixCode(bw, hist))
+	}
+
+	for _, t := range tokens {
+		if t.length == 0 {
+			codes[0].write(bw, int(t.argb>>8&0xff))
+			codes[1].write(bw, int(t.argb>>16&0xff))
+			codes[2].write(bw, int(t.argb&0xff))
+			codes[3].write(bw, int(t.argb>>24))
+			continue
+		}
+
+		lengthCode, extraBits, extra := prefixEncode(t.length)
+		codes[0].write(bw, numLiteralCodes+lengthCode)
+		bw.write(extra, extraBits)
+
+		distanceCode, extraBits, extra := prefixEncode(t.distance)
+		codes[4].write(bw, distanceCode)
+		bw.write(extra, extraBits)
+	}
+
+	return bw.flush()
+}
+
+// tokenize заменяет серии пикселей, совпадающих с соседом слева или сверху,
+// на повторы.
+func tokenize(pixels []uint32, width int) []token {
+	tokens := make([]token, 0, len(pixels)/2)
+
+	for i := 0; i < len(pixels); {
+		left := 0
+		if i > 0 {
+			left = matchLength(pixels, i, 1)
+		}
+		up := 0
+		if i >= width {
+			up = matchLength(pixels, i, width)
+		}
+
+		switch {
+		case up >= minCopyLength && up >= left:
+			tokens = append(tokens, token{length: up, distance: distanceCodeUp})
+			i += up
+		case left >= minCopyLength:
+			tokens = append(tokens, token{length: left, distance: distanceCodeLeft})
+			i += left
+		default:
+			tokens = append(tokens, token{argb: pixels[i]})
+			i++
+		}
+	}
+
+	return tokens
+}
+
+// matchLength сколько пикселей начиная с i совпадают с пикселями на dist раньше.
+func matchLength(pixels []uint32, i, dist int) int {
+	n := 0
+	for i+n < len(pixels) && n < maxCopyLength && pixels[i+n] == pixels[i+n-dist] {
+		n++
+	}
+	return n
+}
+
+// prefixEncode раскладывает значение длины или кода расстояния (от 1) на
+// префиксный символ и дополнительные биты.
+func prefixEncode(value int) (int, uint, uint32) {
+	d := value - 1
+	if d < 4 {
+		return d, 0, 0
+	}
+
+	highest := 0
+	for d>>(highest+1) != 0 {
+		highest++
+	}
+	second := d >> (highest - 1) & 1
+	extraBits := uint(highest - 1)
+
+	return 2*highest + second, extraBits, uint32(d) & (1<<extraBits - 1)
+}
+
+// prefixCode канонический префиксный код: codes уже развёрнуты для записи
+// младшими битами вперёд.
+type prefixCode struct {
+	lengths []uint8
+	codes   []uint32
+}
+
+func (c prefixCode) write(bw *bitWriter, symbol int) {
+	bw.write(c.codes[symbol], uint(c.lengths[symbol]))
+}
+
+// writePrefixCode строит код по гистограмме и записывает его описание.
+// Код из одного символа записывается в простой форме и занимает ноль бит.
+func writePrefixCode(bw *bitWriter, hist []uint32) prefixCode {
+	used, symbol := 0, 0
+	for s, count := range hist {
+		if count > 0 {
+			used++
+			symbol = s
+		}
+	}
+
+	if used <= 1 {
+		bw.write(1, 1) // простой код
+		bw.write(0, 1) // один символ
+		if symbol < 2 {
+			bw.write(0, 1)
+			bw.write(uint32(symbol), 1)
+		} else {
+			bw.write(1, 1)
+			bw.write(uint32(symbol), 8)
+		}
+		return prefixCode{lengths: make([]uint8, len(hist)), codes: make([]uint32, len(hist))}
+	}
+
+	lengths := codeLengths(hist, maxCodeLength)
+
+	// Длины записываются кодом длин без символов повтора 16-18.
+	lengthHist := make([]uint32, len(codeLengthCodeOrder))
+	for _, l := range lengths {
+		lengthHist[l]++
+	}
+	if only := singleSymbol(lengthHist); only >= 0 {
+		lengthHist[(only+1)%len(lengthHist)] = 1
+	}
+	lengthCode := newPrefixCode(codeLengths(lengthHist, maxCodeLengthCodeLength))
+
+	numCodes := 4
+	for i, s := range codeLengthCodeOrder {
+		if lengthCode.lengths[s] != 0 && i+1 > numCodes {
+			numCodes = i + 1
+		}
+	}
+
+	bw.write(0, 1) // обычный код
+	bw.write(uint32(numCodes-4), 4)
+	for _, s := range codeLengthCodeOrder[:numCodes] {
+		bw.write(uint32(lengthCode.lengths[s]), 3)
+	}
+	bw.write(0, 1) // длины записаны для всего алфавита
+	for _, l := range lengths {
+		lengthCode.write(bw, int(l))
+	}
+
+	return newPrefixCode(lengths)
+}
+
+func singleSymbol(hist []uint32) int {
+	symbol := -1
+	for s, count := range hist {
+		if count > 0 {
+			if symbol >= 0 {
+				return -1
+			}
+			symbol = s
+		}
+	}
+	return symbol
+}
+
+// newPrefixCode назначает канонические коды: по возрастанию длины, при равной
+// длине — по возрастанию символа.
+func newPrefixCode(lengths []uint8) prefixCode {
+	var count [maxCodeLength + 1]uint32
+	for _, l := range lengths {
+		count[l]++
+	}
+	count[0] = 0
+
+	var next [maxCodeLength + 1]uint32
+	code := uint32(0)
+	for l := 1; l <= maxCodeLength; l++ {
+		code = (code + count[l-1]) << 1
+		next[l] = code
+	}
+
+	codes := make([]uint32, len(lengths))
+	for s, l := range lengths {
+		if l == 0 {
+			continue
+		}
+		codes[s] = reverseBits(next[l], l)
+		next[l]++
+	}
+
+	return prefixCode{lengths: lengths, codes: codes}
+}
+
+func reverseBits(code uint32, length uint8) uint32 {
+	var r uint32
+	for i := uint8(0); i < length; i++ {
+		r = r<<1 | code&1
+		code >>= 1
+	}
+	return r
+}
+
+// codeLengths длины кода Хаффмана не длиннее limit. Если дерево выходит
+// глубже, частоты сглаживаются и дерево строится заново.
+func codeLengths(hist []uint32, limit int) []uint8 {
+	counts := make([]uint32, len(hist))
+	copy(counts, hist)
+
+	for {
+		lengths, depth := huffman(counts)
+		if depth <= limit {
+			return lengths
+		}
+		for s, c := range counts {
+			if c > 0 {
+				counts[s] = (c + 1) / 2
+			}
+		}
+	}
+}
+
+type huffmanNode struct {
+	count       uint32
+	id          int
+	left, right int
+}
+
+type nodeHeap struct {
+	nodes []huffmanNode
+	items []int
+}
+
+func (h *nodeHeap) Len() int { return len(h.items) }
+func (h *nodeHeap) Less(i, j int) bool {
+	a, b := h.nodes[h.items[i]], h.nodes[h.items[j]]
+	if a.count != b.count {
+		return a.count < b.count
+	}
+	return a.id < b.id
+}
+func (h *nodeHeap) Swap(i, j int) { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *nodeHeap) Push(x any)    { h.items = append(h.items, x.(int)) }
+func (h *nodeHeap) Pop() any {
+	last := h.items[len(h.items)-1]
+	h.items = h.items[:len(h.items)-1]
+	return last
+}
+
+// huffman строит дерево по ненулевым частотам (их не меньше двух) и
+// возвращает глубину каждого символа и максимальную глубину.
+func huffman(counts []uint32) ([]uint8, int) {
+	h := &nodeHeap{}
+	for s, c := range counts {
+		if c > 0 {
+			h.nodes = append(h.nodes, huffmanNode{count: c, id: s, left: -1, right: -1})
+			h.items = append(h.items, len(h.nodes)-1)
+		}
+	}
+	heap.Init(h)
+
+	for h.Len() > 1 {
+		a := heap.Pop(h).(int)
+		b := heap.Pop(h).(int)
+		h.nodes = append(h.nodes, huffmanNode{
+			count: h.nodes[a].count + h.nodes[b].count,
+			id:    len(counts) + len(h.nodes),
+			left:  a,
+			right: b,
+		})
+		heap.Push(h, len(h.nodes)-1)
+	}
+
+	lengths := make([]uint8, len(counts))
+	maxDepth := 0
+
+	var walk func(n, depth int)
+	walk = func(n, depth int) {
+		node := h.nodes[n]
+		if node.left < 0 {
+			lengths[node.id] = uint8(min(depth, 255))
+			maxDepth = max(maxDepth, depth)
+			return
+		}
+		walk(node.left, depth+1)
+		walk(node.right, depth+1)
+	}
+	walk(h.items[0], 0)
+
+	return lengths, maxDepth
+}
+
+// bitWriter пишет биты начиная с младших, как того требует VP8L.
+type bitWriter struct {
+	buf   []byte
+	acc   uint64
+	nbits uint
+}
+
+func (w *bitWriter) write(value uint32, n uint) {
+	w.acc |= uint64(value) << w.nbits
+	w.nbits += n
+	for w.nbits >= 8 {
+		w.buf = append(w.buf, byte(w.acc))
+		w.acc >>= 8
+		w.nbits -= 8
+	}
+}
+
+func (w *bitWriter) flush() []byte {
+	if w.nbits > 0 {
+		w.buf = append(w.buf, byte(w.acc))
+		w.acc, w.nbits = 0, 0
+	}
+	return w.buf
+}